@@ -1,3 +0,0 @@
-module woodpecker-kalshi
-
-go 1.25
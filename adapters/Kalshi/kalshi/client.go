@@ -2,17 +2,26 @@ package kalshi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
-	"woodpecker-kalshi/model"
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
 )
 
+// ErrNotFound is returned when Kalshi answers 404 for a market or event.
+var ErrNotFound = errors.New("kalshi: not found")
+
 type Client struct {
 	BaseURL string
 	APIKey  string
 	Client  *http.Client
+
+	// Verbose registra cada request de listado y cuántos markets devolvió.
+	Verbose bool
 }
 
 func New(apiKey string) *Client {
@@ -27,13 +36,35 @@ func New(apiKey string) *Client {
 
 // 🔑 USAR EVENT_TICKER para above/below
 func (c *Client) GetMarketsByEvent(eventTicker string) ([]model.Market, error) {
-	url := fmt.Sprintf(
+	return c.getMarkets(fmt.Sprintf(
 		"%s/markets?event_ticker=%s&limit=1000",
 		c.BaseURL,
 		eventTicker,
-	)
+	))
+}
+
+// GetSettledMarketsByEvent lists the settled markets of an event.
+func (c *Client) GetSettledMarketsByEvent(eventTicker string) ([]model.Market, error) {
+	return c.getMarkets(fmt.Sprintf(
+		"%s/markets?event_ticker=%s&status=settled&limit=1000",
+		c.BaseURL,
+		eventTicker,
+	))
+}
 
-	fmt.Println("📌 GET", url)
+// GetResolutions returns the resolutions of the settled markets of an event.
+func (c *Client) GetResolutions(eventTicker string) ([]polymarket.Resolution, error) {
+	markets, err := c.GetSettledMarketsByEvent(eventTicker)
+	if err != nil {
+		return nil, err
+	}
+	return Resolutions(markets), nil
+}
+
+func (c *Client) getMarkets(url string) ([]model.Market, error) {
+	if c.Verbose {
+		log.Println("📌 GET", url)
+	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -58,6 +89,72 @@ func (c *Client) GetMarketsByEvent(eventTicker string) ([]model.Market, error) {
 		return nil, err
 	}
 
-	fmt.Printf("↳ Kalshi respondió %d markets\n", len(out.Markets))
+	if c.Verbose {
+		log.Printf("↳ Kalshi respondió %d markets", len(out.Markets))
+	}
 	return out.Markets, nil
 }
+
+// GetMarket fetches a single market by ticker.
+func (c *Client) GetMarket(ticker string) (model.Market, error) {
+	url := fmt.Sprintf("%s/markets/%s", c.BaseURL, ticker)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return model.Market{}, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return model.Market{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.Market{}, fmt.Errorf("%w: market %s", ErrNotFound, ticker)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.Market{}, fmt.Errorf("kalshi HTTP %s", resp.Status)
+	}
+
+	var out model.MarketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return model.Market{}, err
+	}
+	return out.Market, nil
+}
+
+// GetOrderBook fetches the order book of a market.
+func (c *Client) GetOrderBook(ticker string) (model.OrderBook, error) {
+	url := fmt.Sprintf("%s/markets/%s/orderbook", c.BaseURL, ticker)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return model.OrderBook{}, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return model.OrderBook{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return model.OrderBook{}, fmt.Errorf("%w: market %s", ErrNotFound, ticker)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.OrderBook{}, fmt.Errorf("kalshi HTTP %s", resp.Status)
+	}
+
+	var out model.OrderBookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return model.OrderBook{}, err
+	}
+	return out.OrderBook, nil
+}
@@ -0,0 +1,15 @@
+package kalshi
+
+// TakerFeeRate is the Kalshi taker fee coefficient: a trade of C contracts
+// at price P pays ceil(rate × C × P × (1−P)) dollars.
+const TakerFeeRate = 0.07
+
+// TakerFee is the taker fee of one contract traded at probability p, in
+// probability units. Rounding up to the cent is ignored: it depends on the
+// order size.
+func TakerFee(p float64) float64 {
+	if p <= 0 || p >= 1 {
+		return 0
+	}
+	return TakerFeeRate * p * (1 - p)
+}
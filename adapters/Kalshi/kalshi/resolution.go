@@ -0,0 +1,50 @@
+package kalshi
+
+import (
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+)
+
+// ToResolution normalizes a settled Kalshi market. Markets without a "yes" or
+// "no" result are not resolved. The resolution time is the market close.
+//
+// Like Gamma, Outcome is the winning outcome label: the YES side label of the
+// market (YesSubTitle, "Yes" without one) or "No".
+func ToResolution(m model.Market) (polymarket.Resolution, bool) {
+	r := polymarket.Resolution{
+		MarketID: m.Ticker,
+		EventID:  m.EventTicker,
+		Source:   SnapshotSource,
+	}
+
+	switch m.Result {
+	case "yes":
+		r.Value = 1
+		r.Outcome = m.YesSubTitle
+		if r.Outcome == "" {
+			r.Outcome = "Yes"
+		}
+	case "no":
+		r.Value = 0
+		r.Outcome = "No"
+	default:
+		return polymarket.Resolution{}, false
+	}
+	if m.SettlementValue != nil {
+		r.Value = CentsToProb(*m.SettlementValue)
+	}
+
+	r.ResolvedAt = m.CloseTime.Time
+	return r, true
+}
+
+// Resolutions returns the resolutions of the settled markets among markets.
+func Resolutions(markets []model.Market) []polymarket.Resolution {
+	var out []polymarket.Resolution
+	for _, m := range markets {
+		if r, ok := ToResolution(m); ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
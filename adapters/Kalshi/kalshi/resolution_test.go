@@ -0,0 +1,40 @@
+package kalshi
+
+import (
+	"testing"
+	"time"
+
+	"woodpecker/adapters/Kalshi/model"
+	"woodpecker/adapters/timestamp"
+)
+
+func TestToResolution(t *testing.T) {
+	settled := 100
+	r, ok := ToResolution(model.Market{
+		Ticker:          "KXBTCD-25DEC3117-T97249.99",
+		EventTicker:     "KXBTCD-25DEC3117",
+		YesSubTitle:     "$97,250 or above",
+		Result:          "yes",
+		SettlementValue: &settled,
+		CloseTime:       timestamp.FromString("2025-12-31T22:00:00Z"),
+	})
+	if !ok {
+		t.Fatal("expected a settled market to resolve")
+	}
+	if !r.Yes() || r.Outcome != "$97,250 or above" || r.Value != 1 || r.Source != SnapshotSource {
+		t.Fatalf("unexpected resolution %+v", r)
+	}
+	if !r.ResolvedAt.Equal(time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected close time as resolution time, got %s", r.ResolvedAt)
+	}
+
+	if r, ok := ToResolution(model.Market{Ticker: "T", Result: "no"}); !ok || r.Yes() || r.Value != 0 || r.Outcome != "No" {
+		t.Fatalf("unexpected NO resolution %+v", r)
+	}
+	if r, ok := ToResolution(model.Market{Ticker: "T", Result: "yes"}); !ok || r.Outcome != "Yes" {
+		t.Fatalf("expected Yes without a YES side label, got %+v", r)
+	}
+	if _, ok := ToResolution(model.Market{Ticker: "T", Status: "active"}); ok {
+		t.Fatal("an active market must not resolve")
+	}
+}
@@ -0,0 +1,145 @@
+package kalshi
+
+import (
+	"time"
+
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/adapters/timestamp"
+)
+
+// SnapshotSource identifies Kalshi snapshots.
+const SnapshotSource = "kalshi"
+
+// CentsToProb converts a Kalshi price in cents (0..100) to a probability.
+func CentsToProb(cents int) float64 {
+	return float64(cents) / 100
+}
+
+// ToMarketPoint normalizes a Kalshi market into the MarketPoint used by the
+// feature/signal logic. YES prices are converted from cents to probability and
+// liquidity from cents to dollars.
+func ToMarketPoint(m model.Market) polymarket.MarketPoint {
+	mp := polymarket.MarketPoint{
+		MarketID:  m.Ticker,
+		Slug:      m.Ticker,
+		Title:     MarketTitle(m),
+		BestBid:   CentsToProb(m.YesBid),
+		BestAsk:   CentsToProb(m.YesAsk),
+		LastTrade: CentsToProb(m.LastPrice),
+		Liquidity: float64(m.Liquidity) / 100,
+		Volume:    float64(m.Volume24h),
+
+		ParseErrors: timestamp.Failures(map[string]timestamp.Time{
+			"open_time":       m.OpenTime,
+			"close_time":      m.CloseTime,
+			"expiration_time": m.ExpirationTime,
+		}),
+	}
+
+	// Same mid/spread rules as polymarket.BuildSnapshot
+	switch {
+	case mp.BestBid > 0 && mp.BestAsk > 0:
+		mp.MidPrice = (mp.BestBid + mp.BestAsk) / 2
+		mp.Spread = mp.BestAsk - mp.BestBid
+	case mp.BestBid > 0:
+		mp.MidPrice = mp.BestBid
+	case mp.BestAsk > 0:
+		mp.MidPrice = mp.BestAsk
+	}
+	return mp
+}
+
+// ToOrderBook converts a Kalshi book to YES bids and asks in probability.
+// NO bids become YES asks at 100 − price.
+func ToOrderBook(b model.OrderBook) polymarket.OrderBook {
+	bids := make([]polymarket.BookLevel, 0, len(b.Yes))
+	for _, l := range b.Yes {
+		bids = append(bids, polymarket.BookLevel{Price: CentsToProb(l[0]), Size: float64(l[1])})
+	}
+	asks := make([]polymarket.BookLevel, 0, len(b.No))
+	for _, l := range b.No {
+		asks = append(asks, polymarket.BookLevel{Price: CentsToProb(100 - l[0]), Size: float64(l[1])})
+	}
+	return polymarket.NewOrderBook(bids, asks)
+}
+
+// MarketTitle is the question a Kalshi market asks: the market title plus the
+// subtitle naming its outcome (e.g. "$100,000 or above") when there is one.
+func MarketTitle(m model.Market) string {
+	sub := m.YesSubTitle
+	if sub == "" {
+		sub = m.Subtitle
+	}
+	switch {
+	case m.Title == "":
+		return sub
+	case sub == "":
+		return m.Title
+	default:
+		return m.Title + " " + sub
+	}
+}
+
+// BuildSnapshot groups Kalshi markets by event ticker into a Snapshot taken at ts.
+func BuildSnapshot(markets []model.Market, ts time.Time) polymarket.Snapshot {
+	var (
+		events         []polymarket.EventSnapshot
+		byEvent        = map[string]int{}
+		totalLiquidity float64
+		totalSpread    float64
+		spreadCount    int
+		extremeCount   int
+	)
+
+	for _, m := range markets {
+		i, ok := byEvent[m.EventTicker]
+		if !ok {
+			es := polymarket.EventSnapshot{
+				EventID: m.EventTicker,
+				Slug:    m.EventTicker,
+			}
+			es.EndDate = m.CloseTime.Time
+			i = len(events)
+			byEvent[m.EventTicker] = i
+			events = append(events, es)
+		}
+
+		mp := ToMarketPoint(m)
+		if mp.Spread > 0 {
+			totalSpread += mp.Spread
+			spreadCount++
+		}
+		if (mp.BestBid > 0 && mp.BestBid < 0.05) || (mp.BestAsk > 0.95) {
+			extremeCount++
+		}
+		totalLiquidity += mp.Liquidity
+
+		events[i].Liquidity += mp.Liquidity
+		events[i].Volume += mp.Volume
+		events[i].Markets = append(events[i].Markets, mp)
+	}
+
+	stats := polymarket.SnapshotStats{
+		TotalEvents:    len(events),
+		TotalMarkets:   len(markets),
+		ExtremeMarkets: extremeCount,
+		ParseFailures:  polymarket.ParseFailures(events),
+	}
+	if len(markets) > 0 {
+		stats.AvgLiquidity = totalLiquidity / float64(len(markets))
+	}
+	if spreadCount > 0 {
+		stats.AvgSpread = totalSpread / float64(spreadCount)
+	}
+
+	s := polymarket.Snapshot{
+		Timestamp: ts.UTC(),
+		Source:    SnapshotSource,
+		Events:    events,
+		Stats:     stats,
+	}
+	s.SnapshotID = polymarket.SnapshotID(s)
+	polymarket.AssessQuality(&s, polymarket.DefaultQualityConfig)
+	return s
+}
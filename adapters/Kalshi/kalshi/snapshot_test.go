@@ -0,0 +1,83 @@
+package kalshi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"woodpecker/adapters/Kalshi/model"
+)
+
+func TestToMarketPoint_ConvertsCents(t *testing.T) {
+	mp := ToMarketPoint(model.Market{Ticker: "T", YesBid: 40, YesAsk: 44, LastPrice: 42, Liquidity: 12345})
+
+	if mp.BestBid != 0.40 || mp.BestAsk != 0.44 || mp.LastTrade != 0.42 {
+		t.Fatalf("expected cents converted to probability, got %+v", mp)
+	}
+	if d := mp.MidPrice - 0.42; d > 1e-9 || d < -1e-9 {
+		t.Fatalf("expected mid 0.42, got %v", mp.MidPrice)
+	}
+	if mp.Liquidity != 123.45 {
+		t.Fatalf("expected liquidity in dollars, got %v", mp.Liquidity)
+	}
+}
+
+func TestBuildSnapshot_StoredSnapshot(t *testing.T) {
+	files, _ := filepath.Glob("../snapshots/snapshot_*.json")
+	if len(files) == 0 {
+		t.Skip("no stored snapshots")
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var markets []model.Market
+	if err := json.Unmarshal(data, &markets); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := BuildSnapshot(markets, time.Date(2025, 12, 31, 4, 15, 58, 0, time.UTC))
+	if snap.Source != SnapshotSource || snap.Stats.TotalMarkets != len(markets) || snap.Stats.TotalEvents != 1 {
+		t.Fatalf("unexpected snapshot stats: %+v", snap.Stats)
+	}
+	if snap.Events[0].EventID != markets[0].EventTicker || snap.Events[0].EndDate.IsZero() {
+		t.Fatalf("unexpected event: %+v", snap.Events[0].EventID)
+	}
+	for _, mp := range snap.Events[0].Markets {
+		if mp.MidPrice < 0 || mp.MidPrice > 1 {
+			t.Fatalf("mid price out of range for %s: %v", mp.MarketID, mp.MidPrice)
+		}
+	}
+}
+
+func TestToOrderBook_NoBidsAreYesAsks(t *testing.T) {
+	b := ToOrderBook(model.OrderBook{
+		Yes: [][2]int{{38, 100}, {40, 50}},
+		No:  [][2]int{{55, 70}, {50, 20}},
+	})
+
+	if len(b.Bids) != 2 || b.Bids[0].Price != 0.40 || b.Bids[0].Size != 50 {
+		t.Fatalf("expected best YES bid first, got %+v", b.Bids)
+	}
+	if len(b.Asks) != 2 || b.Asks[0].Price != 0.45 || b.Asks[0].Size != 70 || b.Asks[1].Price != 0.50 {
+		t.Fatalf("expected NO bids as YES asks at 100 - price, got %+v", b.Asks)
+	}
+}
+
+func TestBuildSnapshot_ParseFailures(t *testing.T) {
+	var markets []model.Market
+	raw := `[{"ticker":"EV-T1","event_ticker":"EV","yes_bid":40,"yes_ask":44,"close_time":"2025-12-31T22:00:00Z","open_time":"","expiration_time":"soon"}]`
+	if err := json.Unmarshal([]byte(raw), &markets); err != nil {
+		t.Fatal(err)
+	}
+	s := BuildSnapshot(markets, time.Now())
+
+	if !s.Events[0].EndDate.Equal(time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected end date %s", s.Events[0].EndDate)
+	}
+	if f := s.Stats.ParseFailures; len(f) != 1 || f["market.expiration_time"] != 1 {
+		t.Fatalf("unexpected parse failures %v", f)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
-	"woodpecker-kalshi/kalshi"
+	"woodpecker/adapters/Kalshi/kalshi"
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
 )
 
 const POLL_INTERVAL = 30 * time.Second
@@ -21,6 +24,7 @@ func main() {
 	eventTicker := "KXBTCD-25DEC3117"
 
 	client := kalshi.New(apiKey)
+	client.Verbose = true
 
 	for {
 		fmt.Println("🔍 Consultando mercados…")
@@ -34,6 +38,7 @@ func main() {
 
 		fmt.Printf("📊 markets recibidos: %d\n", len(markets))
 		saveSnapshot(eventTicker, markets)
+		saveResolutions(markets)
 
 		time.Sleep(POLL_INTERVAL)
 	}
@@ -59,3 +64,27 @@ func saveSnapshot(event string, markets any) {
 
 	fmt.Println("📸 Snapshot guardado:", filename)
 }
+
+// saveResolutions merges the settled markets into snapshots/resolutions.json
+// so the snapshots can be labeled later.
+func saveResolutions(markets []model.Market) {
+	settled := kalshi.Resolutions(markets)
+	if len(settled) == 0 {
+		return
+	}
+
+	path := filepath.Join("snapshots", polymarket.ResolutionsFile)
+	store, err := polymarket.LoadResolutions(path)
+	if err != nil {
+		fmt.Println("❌ error:", err)
+		return
+	}
+	added := store.Add(settled...)
+	if err := store.Save(path); err != nil {
+		fmt.Println("❌ error:", err)
+		return
+	}
+	if added > 0 {
+		fmt.Printf("🏁 %d resoluciones nuevas guardadas en %s\n", added, path)
+	}
+}
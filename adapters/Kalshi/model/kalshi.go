@@ -1,30 +1,57 @@
 package model
 
+import "woodpecker/adapters/timestamp"
+
 type MarketsResponse struct {
 	Markets []Market `json:"markets"`
 }
 
+type MarketResponse struct {
+	Market Market `json:"market"`
+}
+
 type Market struct {
-	Ticker         string  `json:"ticker"`
-	EventTicker    string  `json:"event_ticker"`
-	SeriesTicker   string  `json:"series_ticker,omitempty"`
-	Status         string  `json:"status"`
-	MarketType     string  `json:"market_type"`
-
-	StrikeType     string   `json:"strike_type"`
-	FloorStrike    *float64 `json:"floor_strike,omitempty"`
-	CapStrike      *float64 `json:"cap_strike,omitempty"`
-
-	YesBid         int      `json:"yes_bid"`
-	YesAsk         int      `json:"yes_ask"`
-	NoBid          int      `json:"no_bid"`
-	NoAsk          int      `json:"no_ask"`
-
-	Liquidity      int64    `json:"liquidity"`
-	Volume24h      int64    `json:"volume_24h"`
-	OpenInterest   int64    `json:"open_interest"`
-
-	OpenTime       string   `json:"open_time"`
-	CloseTime      string   `json:"close_time"`
-	ExpirationTime string   `json:"expiration_time"`
+	Ticker       string `json:"ticker"`
+	EventTicker  string `json:"event_ticker"`
+	SeriesTicker string `json:"series_ticker,omitempty"`
+	Status       string `json:"status"`
+	MarketType   string `json:"market_type"`
+
+	Title       string `json:"title,omitempty"`
+	Subtitle    string `json:"subtitle,omitempty"`
+	YesSubTitle string `json:"yes_sub_title,omitempty"`
+
+	StrikeType  string   `json:"strike_type"`
+	FloorStrike *float64 `json:"floor_strike,omitempty"`
+	CapStrike   *float64 `json:"cap_strike,omitempty"`
+
+	YesBid    int `json:"yes_bid"`
+	YesAsk    int `json:"yes_ask"`
+	NoBid     int `json:"no_bid"`
+	NoAsk     int `json:"no_ask"`
+	LastPrice int `json:"last_price"`
+
+	Liquidity    int64 `json:"liquidity"`
+	Volume24h    int64 `json:"volume_24h"`
+	OpenInterest int64 `json:"open_interest"`
+
+	// Settlement: Result is "yes" or "no" once settled; SettlementValue is
+	// what one YES contract paid, in cents.
+	Result          string `json:"result,omitempty"`
+	SettlementValue *int   `json:"settlement_value,omitempty"`
+
+	OpenTime       timestamp.Time `json:"open_time"`
+	CloseTime      timestamp.Time `json:"close_time"`
+	ExpirationTime timestamp.Time `json:"expiration_time"`
+}
+
+type OrderBookResponse struct {
+	OrderBook OrderBook `json:"orderbook"`
+}
+
+// OrderBook lists resting bids as [price in cents, contracts] pairs. Kalshi
+// only quotes bids: a NO bid at p is a YES ask at 100 − p.
+type OrderBook struct {
+	Yes [][2]int `json:"yes"`
+	No  [][2]int `json:"no"`
 }
@@ -7,6 +7,7 @@ import (
 
 type Client struct {
 	BaseURL string
+	CLOBURL string // order books
 	HTTP    *http.Client
 }
 
@@ -15,6 +16,7 @@ type Client struct {
 func NewClient() *Client {
 	return &Client{
 		BaseURL: "https://gamma-api.polymarket.com",
+		CLOBURL: "https://clob.polymarket.com",
 		HTTP: &http.Client{
 			Timeout: 15 * time.Second,
 		},
@@ -0,0 +1,120 @@
+package polymarket
+
+import (
+	"math"
+	"time"
+)
+
+// FeeFunc is the trading fee of one contract bought or sold at price, in
+// probability units (dollars per $1 contract).
+type FeeFunc func(price float64) float64
+
+// NoFee is a venue without trading fees.
+func NoFee(float64) float64 { return 0 }
+
+// CrossVenueFeatures compare a market with an equivalent market on another
+// venue. Both prices are YES probabilities (Kalshi cents already converted by
+// kalshi.ToMarketPoint).
+type CrossVenueFeatures struct {
+	Counterpart string `json:"counterpart"` // history key of the other market
+
+	// Gap is this market's mid minus the counterpart's.
+	Gap float64 `json:"gap"`
+
+	// AdjustedGap is what is left of Gap after crossing both spreads and
+	// paying both fees: selling YES where it is dear and buying it where it
+	// is cheap. It has the sign of Gap and is 0 when costs eat the gap.
+	AdjustedGap float64 `json:"adjusted_gap"`
+
+	// Persistence is the share of paired observations, in history, whose
+	// adjusted gap points the same way as the current one.
+	Persistence  float64 `json:"persistence"`
+	Observations int     `json:"observations"`
+}
+
+// AdjustedGap is the fee- and spread-adjusted gap between a and b (see
+// CrossVenueFeatures).
+func AdjustedGap(a, b MarketPoint, feeA, feeB FeeFunc) float64 {
+	if feeA == nil {
+		feeA = NoFee
+	}
+	if feeB == nil {
+		feeB = NoFee
+	}
+
+	gap := a.MidPrice - b.MidPrice
+	switch {
+	case gap > 0:
+		edge := bidOrMid(a) - askOrMid(b) - feeA(bidOrMid(a)) - feeB(askOrMid(b))
+		return math.Max(edge, 0)
+	case gap < 0:
+		edge := bidOrMid(b) - askOrMid(a) - feeB(bidOrMid(b)) - feeA(askOrMid(a))
+		return -math.Max(edge, 0)
+	default:
+		return 0
+	}
+}
+
+// ComputeCrossVenue pairs each point of history (this market, oldest first,
+// ending with the current point) with the last counterpart point at or before
+// it, no older than maxAge, and scores the current gap and its persistence.
+// It returns nil when the current point has no fresh counterpart.
+func ComputeCrossVenue(history, counterpart []MarketPoint, maxAge time.Duration, fee, counterpartFee FeeFunc) *CrossVenueFeatures {
+	if len(history) == 0 {
+		return nil
+	}
+	cur := history[len(history)-1]
+	c, ok := pairedPoint(cur.UpdatedAt, counterpart, maxAge)
+	if !ok || cur.MidPrice <= 0 || c.MidPrice <= 0 {
+		return nil
+	}
+	cv := &CrossVenueFeatures{
+		Gap:         cur.MidPrice - c.MidPrice,
+		AdjustedGap: AdjustedGap(cur, c, fee, counterpartFee),
+	}
+
+	same := 0
+	for _, h := range history {
+		c, ok := pairedPoint(h.UpdatedAt, counterpart, maxAge)
+		if !ok || h.MidPrice <= 0 || c.MidPrice <= 0 {
+			continue
+		}
+		cv.Observations++
+		g := AdjustedGap(h, c, fee, counterpartFee)
+		if g != 0 && cv.AdjustedGap != 0 && (g > 0) == (cv.AdjustedGap > 0) {
+			same++
+		}
+	}
+	cv.Persistence = float64(same) / float64(cv.Observations)
+	return cv
+}
+
+// pairedPoint is the last point of series at or before t, if at most maxAge
+// old. Points without a time are paired with anything.
+func pairedPoint(t time.Time, series []MarketPoint, maxAge time.Duration) (MarketPoint, bool) {
+	for i := len(series) - 1; i >= 0; i-- {
+		s := series[i]
+		if t.IsZero() || s.UpdatedAt.IsZero() {
+			return s, true
+		}
+		if s.UpdatedAt.After(t) {
+			continue
+		}
+		return s, maxAge <= 0 || t.Sub(s.UpdatedAt) <= maxAge
+	}
+	return MarketPoint{}, false
+}
+
+func bidOrMid(mp MarketPoint) float64 {
+	if mp.BestBid > 0 {
+		return mp.BestBid
+	}
+	return mp.MidPrice
+}
+
+func askOrMid(mp MarketPoint) float64 {
+	if mp.BestAsk > 0 {
+		return mp.BestAsk
+	}
+	return mp.MidPrice
+}
@@ -0,0 +1,58 @@
+package polymarket
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func quote(bid, ask float64, at time.Time) MarketPoint {
+	return MarketPoint{BestBid: bid, BestAsk: ask, MidPrice: (bid + ask) / 2, Spread: ask - bid, UpdatedAt: at}
+}
+
+func TestAdjustedGap(t *testing.T) {
+	flat := func(float64) float64 { return 0.01 }
+
+	a, b := quote(0.60, 0.62, time.Time{}), quote(0.50, 0.52, time.Time{})
+	if g := AdjustedGap(a, b, flat, flat); math.Abs(g-0.06) > 1e-9 {
+		t.Fatalf("expected 0.60 - 0.52 - 2 fees = 0.06, got %v", g)
+	}
+	if g := AdjustedGap(b, a, flat, flat); math.Abs(g+0.06) > 1e-9 {
+		t.Fatalf("expected the gap to keep its sign, got %v", g)
+	}
+	if g := AdjustedGap(quote(0.52, 0.58, time.Time{}), b, flat, flat); g != 0 {
+		t.Fatalf("spreads and fees must eat a small gap, got %v", g)
+	}
+}
+
+func TestComputeCrossVenue_Persistence(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+
+	history := []MarketPoint{
+		quote(0.49, 0.51, at(0)),  // no gap
+		quote(0.60, 0.62, at(10)), // above the counterpart
+		quote(0.61, 0.63, at(20)), // above, counterpart point 16 min old: stale
+		quote(0.62, 0.64, at(30)),
+	}
+	counterpart := []MarketPoint{
+		quote(0.49, 0.51, at(0)),
+		quote(0.50, 0.52, at(4)),
+		quote(0.50, 0.52, at(29)),
+	}
+
+	cv := ComputeCrossVenue(history, counterpart, 15*time.Minute, NoFee, NoFee)
+	if cv == nil {
+		t.Fatal("expected features")
+	}
+	if math.Abs(cv.AdjustedGap-0.10) > 1e-9 || math.Abs(cv.Gap-0.12) > 1e-9 {
+		t.Fatalf("unexpected gap %+v", cv)
+	}
+	if cv.Observations != 3 || math.Abs(cv.Persistence-2.0/3) > 1e-9 {
+		t.Fatalf("expected 2 of 3 paired observations above, got %+v", cv)
+	}
+
+	if ComputeCrossVenue(history[:3], counterpart, 15*time.Minute, NoFee, NoFee) != nil {
+		t.Fatal("a stale counterpart must not produce features")
+	}
+}
@@ -5,19 +5,36 @@ import "math"
 // FeatureVector contains continuous, numerical features.
 // NO thresholds. NO decisions.
 type FeatureVector struct {
-	PEvent              float64
-	LogOdds             float64
-	ProbabilityMomentum float64
-	BeliefVolatility    float64
-	ImpliedConfidence   float64
-	Dispersion          float64
+	PEvent              float64 `json:"p_event"`
+	LogOdds             float64 `json:"log_odds"`
+	ProbabilityMomentum float64 `json:"probability_momentum"`
+	BeliefVolatility    float64 `json:"belief_volatility"`
+	ImpliedConfidence   float64 `json:"implied_confidence"`
+	Dispersion          float64 `json:"dispersion"`
+
+	// DataQuality is the quality score of the current point (1 when it was
+	// not assessed); ImpliedConfidence is scaled by it.
+	DataQuality float64 `json:"data_quality"`
+
+	// PeerGroup is the peer group Dispersion was computed over; every
+	// non-empty group's dispersion is in PeerDispersion.
+	PeerGroup      string             `json:"peer_group,omitempty"`
+	PeerDispersion map[string]float64 `json:"peer_dispersion,omitempty"`
+
+	// Book holds the order book microstructure features when the market
+	// point carries a book (see ComputeBookFeatures, DefaultBookConfig).
+	Book *BookFeatures `json:"book,omitempty"`
+
+	// CrossVenue is set when the market is linked to an equivalent market
+	// on another venue (see ComputeCrossVenue).
+	CrossVenue *CrossVenueFeatures `json:"cross_venue,omitempty"`
 }
 
 func ComputeFeatures(
 	current MarketPoint,
 	previous *MarketPoint,
 	history []MarketPoint,
-	peers []MarketPoint,
+	peers PeerGroups,
 ) FeatureVector {
 
 	p := clampProb(current.MidPrice)
@@ -30,22 +47,51 @@ func ComputeFeatures(
 
 	vol := logOddsVolatility(history)
 
+	spread := current.Spread
+	var book *BookFeatures
+	if current.Book != nil {
+		if bf, ok := ComputeBookFeatures(*current.Book, DefaultBookConfig); ok {
+			book = &bf
+		}
+		if spread <= 0 {
+			spread = current.Book.boundedSpread()
+		}
+	}
+
+	quality := 1.0
+	if current.Quality != nil {
+		quality = current.Quality.Score
+	}
 	conf := impliedConfidence(
 		current.Liquidity,
 		current.Volume,
-		current.Spread,
-	)
-
-	disp := crossMarketDispersion(current, peers)
+		spread,
+	) * quality
 
-	return FeatureVector{
+	f := FeatureVector{
 		PEvent:              p,
 		LogOdds:             logOdds,
 		ProbabilityMomentum: momentum,
 		BeliefVolatility:    vol,
 		ImpliedConfidence:   conf,
-		Dispersion:          disp,
+		DataQuality:         quality,
+		Book:                book,
 	}
+
+	// Dispersion over the first non-empty peer group
+	for _, g := range peers {
+		if len(g.Markets) == 0 {
+			continue
+		}
+		disp := crossMarketDispersion(current, g.Markets)
+		if f.PeerDispersion == nil {
+			f.PeerDispersion = map[string]float64{}
+			f.PeerGroup = g.Name
+			f.Dispersion = disp
+		}
+		f.PeerDispersion[g.Name] = disp
+	}
+	return f
 }
 
 /* ---------- math helpers ---------- */
@@ -79,12 +125,19 @@ func logOddsVolatility(history []MarketPoint) float64 {
 	return math.Sqrt(sum / float64(len(vals)))
 }
 
+// missingSpread stands in for the spread of markets without a reported spread
+// or book to derive it from. It is two ticks of the one-cent grid both venues
+// quote on: such markets score like a market quoted two ticks wide, below the
+// one-tick spreads liquid markets show, without zeroing the confidence of
+// markets that merely lack the field. Markets with a book use its spread (see
+// OrderBook.boundedSpread).
+const missingSpread = 0.02
+
 // A lightweight confidence proxy: more liquidity/volume, tighter spread => higher confidence.
 func impliedConfidence(liquidity, volume, spread float64) float64 {
 	eps := 1e-6
-	// If spread missing, penalize a bit
 	if spread <= 0 {
-		spread = 0.02
+		spread = missingSpread
 	}
 	raw := (math.Log1p(liquidity) + math.Log1p(volume)) / (spread + eps)
 	return math.Tanh(raw / 10)
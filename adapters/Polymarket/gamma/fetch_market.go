@@ -0,0 +1,56 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNotFound is returned when Gamma answers 404 for an event or market.
+var ErrNotFound = errors.New("gamma: not found")
+
+// FetchEvent fetches a single event (with its markets) by ID.
+func (c *Client) FetchEvent(id string) (Event, error) {
+	var event Event
+	err := c.getJSON(fmt.Sprintf("%s/events/%s", c.BaseURL, id), &event)
+	if errors.Is(err, ErrNotFound) {
+		return event, fmt.Errorf("%w: event %s", ErrNotFound, id)
+	}
+	return event, err
+}
+
+// FetchMarket fetches a single market by ID. Gamma embeds the parent
+// event(s) in Market.Events.
+func (c *Client) FetchMarket(id string) (Market, error) {
+	var market Market
+	err := c.getJSON(fmt.Sprintf("%s/markets/%s", c.BaseURL, id), &market)
+	if errors.Is(err, ErrNotFound) {
+		return market, fmt.Errorf("%w: market %s", ErrNotFound, id)
+	}
+	return market, err
+}
+
+func (c *Client) getJSON(url string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "woodpecker/0.1 (gamma-adapter)")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gamma api error: status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
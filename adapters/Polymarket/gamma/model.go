@@ -3,6 +3,8 @@ package polymarket
 import (
 	"encoding/json"
 	"fmt"
+
+	"woodpecker/adapters/timestamp"
 )
 
 // Float64 is a helper that unmarshals from:
@@ -52,28 +54,40 @@ func (f *Float64) UnmarshalJSON(b []byte) error {
 // These MUST NOT contain business logic.
 
 type Event struct {
-	ID            string   `json:"id"`
-	Ticker        *string  `json:"ticker,omitempty"`
-	Slug          *string  `json:"slug,omitempty"`
-	Title         *string  `json:"title,omitempty"`
-	CreatedAt     *string  `json:"createdAt,omitempty"`
-	EndDate       *string  `json:"endDate,omitempty"`
-	Active        *bool    `json:"active,omitempty"`
-	Closed        *bool    `json:"closed,omitempty"`
-	Restricted    *bool    `json:"restricted,omitempty"`
-	Liquidity     Float64  `json:"liquidity"`
-	Volume        Float64  `json:"volume"`
-	Volume24hr    Float64  `json:"volume24hr"`
-	OpenInterest  Float64  `json:"openInterest"`
-	LiquidityAmm  Float64  `json:"liquidityAmm"`
-	LiquidityClob Float64  `json:"liquidityClob"`
-	Markets       []Market `json:"markets"`
+	ID            string         `json:"id"`
+	Ticker        *string        `json:"ticker,omitempty"`
+	Slug          *string        `json:"slug,omitempty"`
+	Title         *string        `json:"title,omitempty"`
+	CreatedAt     timestamp.Time `json:"createdAt"`
+	EndDate       timestamp.Time `json:"endDate"`
+	Active        *bool          `json:"active,omitempty"`
+	Closed        *bool          `json:"closed,omitempty"`
+	Restricted    *bool          `json:"restricted,omitempty"`
+	Liquidity     Float64        `json:"liquidity"`
+	Volume        Float64        `json:"volume"`
+	Volume24hr    Float64        `json:"volume24hr"`
+	OpenInterest  Float64        `json:"openInterest"`
+	LiquidityAmm  Float64        `json:"liquidityAmm"`
+	LiquidityClob Float64        `json:"liquidityClob"`
+	Tags          []Tag          `json:"tags,omitempty"`
+	Markets       []Market       `json:"markets"`
+}
+
+type Tag struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Slug  string `json:"slug"`
 }
 
 type Market struct {
 	ID          string  `json:"id"`
 	Slug        *string `json:"slug,omitempty"`
 	ConditionID *string `json:"conditionId,omitempty"`
+	Question    *string `json:"question,omitempty"`
+
+	// GroupItemTitle names the outcome of a market in a multi-outcome event
+	// (e.g. a candidate); empty for standalone markets.
+	GroupItemTitle *string `json:"groupItemTitle,omitempty"`
 
 	// These two sometimes come as numbers; keep as Float64 for safety.
 	BestBid Float64 `json:"bestBid"`
@@ -85,11 +99,21 @@ type Market struct {
 	VolumeNum    Float64 `json:"volumeNum"`
 	LiquidityNum Float64 `json:"liquidityNum"`
 
-	// Timestamps (strings or null)
-	StartDate *string `json:"startDate,omitempty"`
-	EndDate   *string `json:"endDate,omitempty"`
-	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Timestamps (strings, epochs or null)
+	StartDate timestamp.Time `json:"startDate"`
+	EndDate   timestamp.Time `json:"endDate"`
+	UpdatedAt timestamp.Time `json:"updatedAt"`
 
 	// JSON-encoded arrays in a string (Gamma does this on some fields)
-	Outcomes *string `json:"outcomes,omitempty"`
+	Outcomes      *string `json:"outcomes,omitempty"`
+	OutcomePrices *string `json:"outcomePrices,omitempty"`
+	ClobTokenIDs  *string `json:"clobTokenIds,omitempty"`
+
+	// Resolution
+	Closed              *bool          `json:"closed,omitempty"`
+	ClosedTime          timestamp.Time `json:"closedTime"`
+	UMAResolutionStatus *string        `json:"umaResolutionStatus,omitempty"`
+
+	// Parent event(s); only present when the market is fetched on its own.
+	Events []Event `json:"events,omitempty"`
 }
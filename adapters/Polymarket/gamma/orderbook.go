@@ -0,0 +1,228 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// BookLevel is one price level of an order book: YES price as a probability
+// and size in contracts (shares).
+type BookLevel struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// OrderBook is the YES side of a market: bids best (highest) first, asks
+// best (lowest) first.
+type OrderBook struct {
+	Bids []BookLevel `json:"bids"`
+	Asks []BookLevel `json:"asks"`
+}
+
+// NewOrderBook sorts the levels best first and drops empty ones.
+func NewOrderBook(bids, asks []BookLevel) OrderBook {
+	clean := func(levels []BookLevel) []BookLevel {
+		out := make([]BookLevel, 0, len(levels))
+		for _, l := range levels {
+			if l.Size > 0 && l.Price > 0 && l.Price < 1 {
+				out = append(out, l)
+			}
+		}
+		return out
+	}
+	b := OrderBook{Bids: clean(bids), Asks: clean(asks)}
+	sort.SliceStable(b.Bids, func(i, j int) bool { return b.Bids[i].Price > b.Bids[j].Price })
+	sort.SliceStable(b.Asks, func(i, j int) bool { return b.Asks[i].Price < b.Asks[j].Price })
+	return b
+}
+
+// Mid is the midpoint of the best bid and ask; 0 unless both sides quote.
+func (b OrderBook) Mid() float64 {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0
+	}
+	return (b.Bids[0].Price + b.Asks[0].Price) / 2
+}
+
+// Spread is the best ask minus the best bid; 0 unless both sides quote.
+func (b OrderBook) Spread() float64 {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0
+	}
+	return b.Asks[0].Price - b.Bids[0].Price
+}
+
+// boundedSpread is Spread, taking a side nobody quotes at its price bound (0
+// for bids, 1 for asks); 0 for an empty book.
+func (b OrderBook) boundedSpread() float64 {
+	if len(b.Bids) == 0 && len(b.Asks) == 0 {
+		return 0
+	}
+	bid, ask := 0.0, 1.0
+	if len(b.Bids) > 0 {
+		bid = b.Bids[0].Price
+	}
+	if len(b.Asks) > 0 {
+		ask = b.Asks[0].Price
+	}
+	return ask - bid
+}
+
+// BookConfig sizes the microstructure features.
+type BookConfig struct {
+	// MoveBy is the price move CostToMove prices, in probability.
+	MoveBy float64
+	// Notional is the order size Slippage is measured for, in dollars.
+	Notional float64
+	// DepthWindow is how far from mid Depth and Imbalance look.
+	DepthWindow float64
+}
+
+// DefaultBookConfig prices a 5-point move and a $1,000 order, and measures
+// depth within 2 cents of mid.
+var DefaultBookConfig = BookConfig{MoveBy: 0.05, Notional: 1000, DepthWindow: 0.02}
+
+// BookFeatures are order book microstructure features. Notionals are in
+// dollars, prices in probability.
+type BookFeatures struct {
+	// CostToMoveUp is what buying YES through the asks until the price is
+	// MoveBy above mid costs; CostToMoveDown is what selling through the
+	// bids down to MoveBy below mid raises. A thin side sums to its whole
+	// book.
+	CostToMoveUp   float64 `json:"cost_to_move_up"`
+	CostToMoveDown float64 `json:"cost_to_move_down"`
+
+	// Slippage is the average of the buy and sell slippage of a Notional
+	// order against mid: how much worse than mid the average fill is.
+	// Size the book cannot absorb fills at 1 (buys) or 0 (sells).
+	Slippage float64 `json:"slippage"`
+
+	// Depth is the notional resting within DepthWindow of mid.
+	Depth float64 `json:"depth"`
+	// Imbalance is (bid depth − ask depth) / depth within DepthWindow, in
+	// [-1, 1]; positive when buyers dominate.
+	Imbalance float64 `json:"imbalance"`
+}
+
+// ComputeBookFeatures computes the microstructure features of a book. Books
+// without both sides have none.
+func ComputeBookFeatures(b OrderBook, cfg BookConfig) (BookFeatures, bool) {
+	mid := b.Mid()
+	if mid <= 0 {
+		return BookFeatures{}, false
+	}
+
+	var f BookFeatures
+	for _, l := range b.Asks {
+		if l.Price >= mid+cfg.MoveBy {
+			break
+		}
+		f.CostToMoveUp += l.Price * l.Size
+	}
+	for _, l := range b.Bids {
+		if l.Price <= mid-cfg.MoveBy {
+			break
+		}
+		f.CostToMoveDown += l.Price * l.Size
+	}
+
+	if cfg.Notional > 0 {
+		f.Slippage = (buySlippage(b.Asks, mid, cfg.Notional) + sellSlippage(b.Bids, mid, cfg.Notional)) / 2
+	}
+
+	var bidDepth, askDepth float64
+	for _, l := range b.Bids {
+		if l.Price < mid-cfg.DepthWindow {
+			break
+		}
+		bidDepth += l.Price * l.Size
+	}
+	for _, l := range b.Asks {
+		if l.Price > mid+cfg.DepthWindow {
+			break
+		}
+		askDepth += l.Price * l.Size
+	}
+	f.Depth = bidDepth + askDepth
+	if f.Depth > 0 {
+		f.Imbalance = (bidDepth - askDepth) / f.Depth
+	}
+	return f, true
+}
+
+// buySlippage spends notional dollars on the asks.
+func buySlippage(asks []BookLevel, mid, notional float64) float64 {
+	left, shares := notional, 0.0
+	for _, l := range asks {
+		cost := math.Min(left, l.Price*l.Size)
+		shares += cost / l.Price
+		left -= cost
+		if left <= 0 {
+			break
+		}
+	}
+	shares += left // unabsorbed size fills at 1
+	return notional/shares - mid
+}
+
+// sellSlippage sells notional dollars' worth of shares (at mid) into the bids.
+func sellSlippage(bids []BookLevel, mid, notional float64) float64 {
+	left := notional / mid
+	shares, proceeds := left, 0.0
+	for _, l := range bids {
+		n := math.Min(left, l.Size)
+		proceeds += n * l.Price
+		left -= n
+		if left <= 0 {
+			break
+		}
+	}
+	return mid - proceeds/shares // unabsorbed size fills at 0
+}
+
+// clobLevel is a CLOB book level; prices and sizes are strings.
+type clobLevel struct {
+	Price Float64 `json:"price"`
+	Size  Float64 `json:"size"`
+}
+
+type clobBook struct {
+	Bids []clobLevel `json:"bids"`
+	Asks []clobLevel `json:"asks"`
+}
+
+// FetchOrderBook fetches the CLOB order book of an outcome token (see
+// Market.YesTokenID).
+func (c *Client) FetchOrderBook(tokenID string) (OrderBook, error) {
+	var raw clobBook
+	err := c.getJSON(fmt.Sprintf("%s/book?token_id=%s", c.CLOBURL, tokenID), &raw)
+	if errors.Is(err, ErrNotFound) {
+		return OrderBook{}, fmt.Errorf("%w: order book %s", ErrNotFound, tokenID)
+	}
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	levels := func(in []clobLevel) []BookLevel {
+		out := make([]BookLevel, len(in))
+		for i, l := range in {
+			out[i] = BookLevel{Price: float64(l.Price), Size: float64(l.Size)}
+		}
+		return out
+	}
+	return NewOrderBook(levels(raw.Bids), levels(raw.Asks)), nil
+}
+
+// YesTokenID is the CLOB token of the market's first (YES) outcome.
+func (m Market) YesTokenID() string {
+	if m.ClobTokenIDs == nil {
+		return ""
+	}
+	ids, err := decodeStringArray(*m.ClobTokenIDs)
+	if err != nil || len(ids) == 0 {
+		return ""
+	}
+	return ids[0]
+}
@@ -0,0 +1,102 @@
+package polymarket
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func testBook() OrderBook {
+	return NewOrderBook(
+		[]BookLevel{{Price: 0.45, Size: 2000}, {Price: 0.48, Size: 1000}, {Price: 0.30, Size: 0}},
+		[]BookLevel{{Price: 0.60, Size: 1000}, {Price: 0.52, Size: 500}, {Price: 0.55, Size: 1000}},
+	)
+}
+
+func TestComputeBookFeatures(t *testing.T) {
+	b := testBook()
+	if len(b.Bids) != 2 || b.Bids[0].Price != 0.48 || b.Asks[0].Price != 0.52 {
+		t.Fatalf("expected sorted book without empty levels, got %+v", b)
+	}
+	if math.Abs(b.Mid()-0.50) > 1e-9 || math.Abs(b.Spread()-0.04) > 1e-9 {
+		t.Fatalf("unexpected mid %v / spread %v", b.Mid(), b.Spread())
+	}
+
+	f, ok := ComputeBookFeatures(b, DefaultBookConfig)
+	if !ok {
+		t.Fatal("expected features")
+	}
+	if math.Abs(f.CostToMoveUp-260) > 1e-9 || math.Abs(f.CostToMoveDown-480) > 1e-9 {
+		t.Fatalf("unexpected cost to move %+v", f)
+	}
+	if math.Abs(f.Depth-740) > 1e-9 || math.Abs(f.Imbalance-220.0/740) > 1e-9 {
+		t.Fatalf("unexpected depth %+v", f)
+	}
+
+	// $1,000 buys 500 + 1000 + 190/0.60 shares; 2,000 shares sell for 930.
+	buy := 1000/(1500+190/0.60) - 0.50
+	sell := 0.50 - 930.0/2000
+	if math.Abs(f.Slippage-(buy+sell)/2) > 1e-9 {
+		t.Fatalf("expected slippage %v, got %v", (buy+sell)/2, f.Slippage)
+	}
+}
+
+func TestComputeBookFeatures_ThinBook(t *testing.T) {
+	thin := NewOrderBook([]BookLevel{{Price: 0.49, Size: 10}}, []BookLevel{{Price: 0.51, Size: 10}})
+	f, ok := ComputeBookFeatures(thin, DefaultBookConfig)
+	if !ok || f.Slippage < 0.25 {
+		t.Fatalf("expected unabsorbed size to fill far from mid, got %+v", f)
+	}
+	if _, ok := ComputeBookFeatures(NewOrderBook(thin.Bids, nil), DefaultBookConfig); ok {
+		t.Fatal("a one-sided book has no features")
+	}
+
+	cur := MarketPoint{MidPrice: 0.5, Liquidity: 1000, Volume: 1000, Book: &thin}
+	two := ComputeFeatures(cur, nil, nil, nil)
+	if two.Book == nil || two.ImpliedConfidence == 0 {
+		t.Fatalf("expected book features and the book spread used, got %+v", two)
+	}
+
+	// One side missing: the spread runs to the price bound.
+	oneSided := NewOrderBook(thin.Bids, nil)
+	if s := oneSided.boundedSpread(); math.Abs(s-0.51) > 1e-9 {
+		t.Fatalf("expected the bid to the 1 bound, got %v", s)
+	}
+	cur.Book = &oneSided
+	if one := ComputeFeatures(cur, nil, nil, nil); one.ImpliedConfidence >= two.ImpliedConfidence {
+		t.Fatalf("a one-sided book must lower confidence: %v >= %v", one.ImpliedConfidence, two.ImpliedConfidence)
+	}
+}
+
+func TestFetchOrderBook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/book" || r.URL.Query().Get("token_id") != "123" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"market":"0xabc","asset_id":"123",
+			"bids":[{"price":"0.01","size":"500"},{"price":"0.48","size":"1000"}],
+			"asks":[{"price":"0.99","size":"500"},{"price":"0.52","size":"250.5"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient().WithHTTP(srv.Client())
+	c.CLOBURL = srv.URL
+
+	b, err := c.FetchOrderBook("123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Bids[0] != (BookLevel{Price: 0.48, Size: 1000}) || b.Asks[0] != (BookLevel{Price: 0.52, Size: 250.5}) {
+		t.Fatalf("unexpected book %+v", b)
+	}
+	if _, err := c.FetchOrderBook("missing"); err == nil {
+		t.Fatal("expected an error for an unknown token")
+	}
+
+	ids := `["123","456"]`
+	if id := (Market{ClobTokenIDs: &ids}).YesTokenID(); id != "123" {
+		t.Fatalf("expected the first token, got %q", id)
+	}
+}
@@ -0,0 +1,214 @@
+package polymarket
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Peer group strategies.
+const (
+	// PeersEvent groups the other markets of the same event.
+	PeersEvent = "event"
+	// PeersSiblings groups the other outcomes of a multi-outcome event.
+	PeersSiblings = "siblings"
+	// PeersEndDate groups markets of other events on the same topic (a
+	// shared tag) whose end dates are within PeerConfig.EndDateWindow.
+	PeersEndDate = "end_date"
+	// PeersTags groups markets of other events sharing at least
+	// PeerConfig.MinSharedTags tags.
+	PeersTags = "tags"
+)
+
+// DefaultPeerStrategies are built when PeerConfig lists none. Siblings come
+// before event: they are a subset of it, so the other order would never pick
+// them.
+var DefaultPeerStrategies = []string{PeersSiblings, PeersEvent, PeersEndDate, PeersTags}
+
+// Defaults used when PeerConfig leaves a field unset.
+const (
+	DefaultMinSharedTags = 1
+	DefaultEndDateWindow = 24 * time.Hour
+	DefaultMaxPeers      = 20
+)
+
+// PeerConfig chooses which peer groups are built, in order of preference:
+// Dispersion is computed over the first non-empty group.
+type PeerConfig struct {
+	Strategies    []string
+	MinSharedTags int
+	EndDateWindow time.Duration
+	// MaxPeers caps each group, keeping the most liquid markets.
+	MaxPeers int
+}
+
+// ParsePeerStrategies parses a comma-separated list of strategies.
+func ParsePeerStrategies(s string) ([]string, error) {
+	var out []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		switch name {
+		case PeersEvent, PeersSiblings, PeersEndDate, PeersTags:
+			out = append(out, name)
+		default:
+			return nil, fmt.Errorf("unknown peer strategy '%s'", name)
+		}
+	}
+	return out, nil
+}
+
+func (c PeerConfig) withDefaults() PeerConfig {
+	if len(c.Strategies) == 0 {
+		c.Strategies = DefaultPeerStrategies
+	}
+	if c.MinSharedTags <= 0 {
+		c.MinSharedTags = DefaultMinSharedTags
+	}
+	if c.EndDateWindow <= 0 {
+		c.EndDateWindow = DefaultEndDateWindow
+	}
+	if c.MaxPeers <= 0 {
+		c.MaxPeers = DefaultMaxPeers
+	}
+	return c
+}
+
+// PeerGroup is a named set of markets related to the one being evaluated.
+type PeerGroup struct {
+	Name    string
+	Markets []MarketPoint
+}
+
+// PeerGroups are ordered by preference.
+type PeerGroups []PeerGroup
+
+// Get returns the markets of the named group.
+func (g PeerGroups) Get(name string) []MarketPoint {
+	for _, pg := range g {
+		if pg.Name == name {
+			return pg.Markets
+		}
+	}
+	return nil
+}
+
+// PeerIndex builds the peer groups of the markets of one snapshot.
+type PeerIndex struct {
+	cfg    PeerConfig
+	events []EventSnapshot
+	tags   []map[string]bool
+}
+
+// NewPeerIndex indexes the events of snap.
+func NewPeerIndex(snap Snapshot, cfg PeerConfig) *PeerIndex {
+	idx := &PeerIndex{cfg: cfg.withDefaults(), events: snap.Events}
+	for _, es := range snap.Events {
+		tags := make(map[string]bool, len(es.Tags))
+		for _, t := range es.Tags {
+			tags[t] = true
+		}
+		idx.tags = append(idx.tags, tags)
+	}
+	return idx
+}
+
+// Groups returns the peer groups of a market, in the configured order. Every
+// configured group is present, possibly empty; unknown events have none.
+func (x *PeerIndex) Groups(eventID, marketID string) PeerGroups {
+	self := -1
+	for i, es := range x.events {
+		if es.EventID == eventID {
+			self = i
+			break
+		}
+	}
+	if self < 0 {
+		return nil
+	}
+	own := x.events[self]
+
+	var current MarketPoint
+	for _, mp := range own.Markets {
+		if mp.MarketID == marketID {
+			current = mp
+		}
+	}
+
+	out := make(PeerGroups, 0, len(x.cfg.Strategies))
+	for _, name := range x.cfg.Strategies {
+		var peers []MarketPoint
+		switch name {
+		case PeersEvent:
+			peers = others(own.Markets, marketID, func(MarketPoint) bool { return true })
+		case PeersSiblings:
+			if current.Outcome != "" {
+				peers = others(own.Markets, marketID, func(mp MarketPoint) bool { return mp.Outcome != "" })
+			}
+		case PeersEndDate:
+			peers = x.otherEvents(self, func(i int) bool {
+				return x.sharedTags(self, i) > 0 && withinWindow(own.EndDate, x.events[i].EndDate, x.cfg.EndDateWindow)
+			})
+		case PeersTags:
+			peers = x.otherEvents(self, func(i int) bool {
+				return x.sharedTags(self, i) >= x.cfg.MinSharedTags
+			})
+		}
+		out = append(out, PeerGroup{Name: name, Markets: mostLiquid(peers, x.cfg.MaxPeers)})
+	}
+	return out
+}
+
+func (x *PeerIndex) otherEvents(self int, keep func(i int) bool) []MarketPoint {
+	var out []MarketPoint
+	for i, es := range x.events {
+		if i != self && keep(i) {
+			out = append(out, es.Markets...)
+		}
+	}
+	return out
+}
+
+func (x *PeerIndex) sharedTags(a, b int) int {
+	n := 0
+	for t := range x.tags[a] {
+		if x.tags[b][t] {
+			n++
+		}
+	}
+	return n
+}
+
+func others(markets []MarketPoint, exclude string, keep func(MarketPoint) bool) []MarketPoint {
+	var out []MarketPoint
+	for _, mp := range markets {
+		if mp.MarketID != exclude && keep(mp) {
+			out = append(out, mp)
+		}
+	}
+	return out
+}
+
+func withinWindow(a, b time.Time, window time.Duration) bool {
+	if a.IsZero() || b.IsZero() {
+		return false
+	}
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+	return d <= window
+}
+
+// mostLiquid keeps the n most liquid markets (stable for equal liquidity).
+func mostLiquid(markets []MarketPoint, n int) []MarketPoint {
+	if len(markets) <= n {
+		return markets
+	}
+	sorted := append([]MarketPoint(nil), markets...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Liquidity > sorted[j].Liquidity })
+	return sorted[:n]
+}
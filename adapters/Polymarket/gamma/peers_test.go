@@ -0,0 +1,120 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func peerSnapshot() Snapshot {
+	end := time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC)
+	return Snapshot{Events: []EventSnapshot{
+		{
+			EventID: "winner", EndDate: end, Tags: []string{"politics", "elections"},
+			Markets: []MarketPoint{
+				{MarketID: "a", Outcome: "Alice", MidPrice: 0.6},
+				{MarketID: "b", Outcome: "Bob", MidPrice: 0.3},
+				{MarketID: "turnout", MidPrice: 0.5},
+			},
+		},
+		{
+			EventID: "senate", EndDate: end.Add(6 * time.Hour), Tags: []string{"politics"},
+			Markets: []MarketPoint{{MarketID: "s1", MidPrice: 0.55, Liquidity: 10}},
+		},
+		{
+			EventID: "cabinet", EndDate: end.AddDate(0, 3, 0), Tags: []string{"politics", "elections"},
+			Markets: []MarketPoint{{MarketID: "c1", MidPrice: 0.2, Liquidity: 20}},
+		},
+		{
+			EventID: "rain", EndDate: end, Tags: []string{"weather"},
+			Markets: []MarketPoint{{MarketID: "r1", MidPrice: 0.1}},
+		},
+	}}
+}
+
+func ids(mps []MarketPoint) map[string]bool {
+	out := map[string]bool{}
+	for _, mp := range mps {
+		out[mp.MarketID] = true
+	}
+	return out
+}
+
+func TestPeerIndex_Strategies(t *testing.T) {
+	idx := NewPeerIndex(peerSnapshot(), PeerConfig{})
+	g := idx.Groups("winner", "a")
+
+	if len(g) != len(DefaultPeerStrategies) || g[0].Name != PeersSiblings {
+		t.Fatalf("expected every default group in order, got %+v", g)
+	}
+	if got := ids(g.Get(PeersEvent)); len(got) != 2 || !got["b"] || !got["turnout"] {
+		t.Fatalf("unexpected event peers %v", got)
+	}
+	if got := ids(g.Get(PeersSiblings)); len(got) != 1 || !got["b"] {
+		t.Fatalf("expected only the other outcome as sibling, got %v", got)
+	}
+	if got := ids(g.Get(PeersEndDate)); len(got) != 1 || !got["s1"] {
+		t.Fatalf("expected the same-topic event ending within a day, got %v", got)
+	}
+	if got := ids(g.Get(PeersTags)); len(got) != 2 || !got["s1"] || !got["c1"] || got["r1"] {
+		t.Fatalf("expected events sharing a tag, got %v", got)
+	}
+
+	strict := NewPeerIndex(peerSnapshot(), PeerConfig{Strategies: []string{PeersTags}, MinSharedTags: 2, MaxPeers: 1})
+	if got := ids(strict.Groups("winner", "a").Get(PeersTags)); len(got) != 1 || !got["c1"] {
+		t.Fatalf("expected only the event sharing both tags, got %v", got)
+	}
+	if len(idx.Groups("winner", "turnout").Get(PeersSiblings)) != 0 {
+		t.Fatal("a standalone market has no siblings")
+	}
+
+	// Outcomes are dispersed against their siblings, other markets against
+	// the rest of the event.
+	if f := ComputeFeatures(MarketPoint{MarketID: "a", MidPrice: 0.6}, nil, nil, g); f.PeerGroup != PeersSiblings {
+		t.Fatalf("expected dispersion over siblings, got %s", f.PeerGroup)
+	}
+	turnout := idx.Groups("winner", "turnout")
+	if f := ComputeFeatures(MarketPoint{MarketID: "turnout", MidPrice: 0.5}, nil, nil, turnout); f.PeerGroup != PeersEvent {
+		t.Fatalf("expected dispersion over the event, got %s", f.PeerGroup)
+	}
+}
+
+func TestComputeFeatures_FirstNonEmptyPeerGroup(t *testing.T) {
+	cur := MarketPoint{MarketID: "x", MidPrice: 0.5}
+	groups := PeerGroups{
+		{Name: PeersSiblings},
+		{Name: PeersTags, Markets: []MarketPoint{{MidPrice: 0.9}}},
+		{Name: PeersEvent, Markets: []MarketPoint{{MidPrice: 0.5}}},
+	}
+
+	f := ComputeFeatures(cur, nil, nil, groups)
+	if f.PeerGroup != PeersTags || f.Dispersion == 0 || f.Dispersion != f.PeerDispersion[PeersTags] {
+		t.Fatalf("expected dispersion over the tags group, got %+v", f)
+	}
+	if _, ok := f.PeerDispersion[PeersSiblings]; ok || f.PeerDispersion[PeersEvent] != 0 {
+		t.Fatalf("unexpected per-group dispersion %v", f.PeerDispersion)
+	}
+	if f := ComputeFeatures(cur, nil, nil, nil); f.Dispersion != 0 || f.PeerGroup != "" {
+		t.Fatalf("no peers means no dispersion, got %+v", f)
+	}
+}
+
+func TestBuildSnapshot_TagsAndOutcomes(t *testing.T) {
+	var events []Event
+	raw := `[{"id":"e","tags":[{"id":"2","label":"Politics","slug":"politics"},{"id":"9","label":"US Elections"}],
+		"markets":[{"id":"m","groupItemTitle":"Alice","question":"Will Alice win?"}]}]`
+	if err := json.Unmarshal([]byte(raw), &events); err != nil {
+		t.Fatal(err)
+	}
+	s := BuildSnapshot(events)
+	es := s.Events[0]
+	if len(es.Tags) != 2 || es.Tags[0] != "politics" || es.Tags[1] != "us elections" {
+		t.Fatalf("unexpected tags %v", es.Tags)
+	}
+	if mp := es.Markets[0]; mp.Outcome != "Alice" || mp.Title != "Will Alice win?" {
+		t.Fatalf("unexpected market %+v", mp)
+	}
+	if _, err := ParsePeerStrategies("event,vibes"); err == nil {
+		t.Fatal("expected unknown strategy to be rejected")
+	}
+}
@@ -0,0 +1,145 @@
+package polymarket
+
+import (
+	"sort"
+	"time"
+)
+
+// Data quality issues of a market point.
+const (
+	// IssueNoQuote: neither side is quoted and there is no mid price.
+	IssueNoQuote = "no_quote"
+	// IssueCrossedBook: the best bid is above the best ask.
+	IssueCrossedBook = "crossed_book"
+	// IssueOneSided: only one side is quoted, so MidPrice is that side.
+	IssueOneSided = "one_sided"
+	// IssueZeroLiquidity: the venue reports no liquidity.
+	IssueZeroLiquidity = "zero_liquidity"
+	// IssueStaleQuote: the market was last updated more than
+	// QualityConfig.MaxQuoteAge before the snapshot.
+	IssueStaleQuote = "stale_quote"
+	// IssueParseError: a venue field could not be parsed (see
+	// MarketPoint.ParseErrors).
+	IssueParseError = "parse_error"
+)
+
+// QualityConfig scores market points.
+type QualityConfig struct {
+	MaxQuoteAge time.Duration
+	// Factors multiply the score of a point for each issue it has.
+	Factors map[string]float64
+}
+
+// DefaultQualityConfig makes points without a usable price worthless and
+// halves the score of one-sided or stale quotes.
+var DefaultQualityConfig = QualityConfig{
+	MaxQuoteAge: 24 * time.Hour,
+	Factors: map[string]float64{
+		IssueNoQuote:       0,
+		IssueCrossedBook:   0,
+		IssueOneSided:      0.5,
+		IssueZeroLiquidity: 0.6,
+		IssueStaleQuote:    0.5,
+		IssueParseError:    0.8,
+	},
+}
+
+// Quality is the data quality of a market point: Score in [0, 1] and the
+// issues that lowered it.
+type Quality struct {
+	Score  float64  `json:"score"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// SnapshotQuality summarizes the quality of a snapshot's markets.
+type SnapshotQuality struct {
+	// Score is the mean market score.
+	Score float64 `json:"score"`
+	// Flagged counts markets with at least one issue.
+	Flagged int `json:"flagged"`
+	// Issues counts markets per issue; event fields that failed to parse
+	// count as parse errors too.
+	Issues map[string]int `json:"issues,omitempty"`
+}
+
+// ScoreMarket scores mp as observed at time at. Staleness is only checked
+// when both at and mp.UpdatedAt are known.
+func ScoreMarket(mp MarketPoint, at time.Time, cfg QualityConfig) Quality {
+	var issues []string
+	bid, ask := mp.BestBid > 0, mp.BestAsk > 0
+	switch {
+	case !bid && !ask && mp.MidPrice <= 0:
+		issues = append(issues, IssueNoQuote)
+	case bid && ask && mp.BestBid > mp.BestAsk:
+		issues = append(issues, IssueCrossedBook)
+	case bid != ask:
+		issues = append(issues, IssueOneSided)
+	}
+	if mp.Liquidity <= 0 {
+		issues = append(issues, IssueZeroLiquidity)
+	}
+	if !at.IsZero() && !mp.UpdatedAt.IsZero() && at.Sub(mp.UpdatedAt) > cfg.MaxQuoteAge {
+		issues = append(issues, IssueStaleQuote)
+	}
+	if len(mp.ParseErrors) > 0 {
+		issues = append(issues, IssueParseError)
+	}
+
+	q := Quality{Score: 1, Issues: issues}
+	for _, issue := range issues {
+		if f, ok := cfg.Factors[issue]; ok {
+			q.Score *= f
+		}
+	}
+	return q
+}
+
+// AssessQuality scores every market of s at the snapshot time and
+// summarizes them in s.Quality.
+func AssessQuality(s *Snapshot, cfg QualityConfig) {
+	sq := &SnapshotQuality{Issues: map[string]int{}}
+	var total float64
+	markets := 0
+	for i := range s.Events {
+		es := &s.Events[i]
+		if len(es.ParseErrors) > 0 {
+			sq.Issues[IssueParseError]++
+		}
+		for j := range es.Markets {
+			mp := &es.Markets[j]
+			q := ScoreMarket(*mp, s.Timestamp, cfg)
+			mp.Quality = &q
+
+			total += q.Score
+			markets++
+			if len(q.Issues) > 0 {
+				sq.Flagged++
+			}
+			for _, issue := range q.Issues {
+				sq.Issues[issue]++
+			}
+		}
+	}
+	if markets > 0 {
+		sq.Score = total / float64(markets)
+	}
+	if len(sq.Issues) == 0 {
+		sq.Issues = nil
+	}
+	s.Quality = sq
+}
+
+// Reasons lists the issues of a snapshot, most frequent first.
+func (q SnapshotQuality) Reasons() []string {
+	out := make([]string, 0, len(q.Issues))
+	for issue := range q.Issues {
+		out = append(out, issue)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if q.Issues[out[i]] != q.Issues[out[j]] {
+			return q.Issues[out[i]] > q.Issues[out[j]]
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
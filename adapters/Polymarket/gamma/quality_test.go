@@ -0,0 +1,121 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestScoreMarket(t *testing.T) {
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cfg := DefaultQualityConfig
+
+	cases := []struct {
+		name  string
+		mp    MarketPoint
+		score float64
+		issue string
+	}{
+		{"clean", MarketPoint{BestBid: 0.4, BestAsk: 0.42, MidPrice: 0.41, Liquidity: 100, UpdatedAt: at.Add(-time.Hour)}, 1, ""},
+		{"no quote", MarketPoint{Liquidity: 100}, 0, IssueNoQuote},
+		{"crossed", MarketPoint{BestBid: 0.5, BestAsk: 0.45, MidPrice: 0.475, Liquidity: 100}, 0, IssueCrossedBook},
+		{"one sided", MarketPoint{BestBid: 0.4, MidPrice: 0.4, Liquidity: 100}, 0.5, IssueOneSided},
+		{"no liquidity", MarketPoint{MidPrice: 0.4}, 0.6, IssueZeroLiquidity},
+		{"stale", MarketPoint{MidPrice: 0.4, Liquidity: 100, UpdatedAt: at.Add(-48 * time.Hour)}, 0.5, IssueStaleQuote},
+		{"parse error", MarketPoint{MidPrice: 0.4, Liquidity: 100, ParseErrors: []string{"updatedAt"}}, 0.8, IssueParseError},
+	}
+	for _, c := range cases {
+		q := ScoreMarket(c.mp, at, cfg)
+		if math.Abs(q.Score-c.score) > 1e-9 {
+			t.Fatalf("%s: expected score %v, got %+v", c.name, c.score, q)
+		}
+		if c.issue == "" && len(q.Issues) != 0 || c.issue != "" && (len(q.Issues) != 1 || q.Issues[0] != c.issue) {
+			t.Fatalf("%s: expected issue %q, got %v", c.name, c.issue, q.Issues)
+		}
+	}
+
+	stale := MarketPoint{MidPrice: 0.4, Liquidity: 100, UpdatedAt: at.Add(-48 * time.Hour)}
+	if q := ScoreMarket(stale, time.Time{}, cfg); q.Score != 1 {
+		t.Fatalf("staleness needs an observation time, got %+v", q)
+	}
+}
+
+func TestBuildSnapshot_Quality(t *testing.T) {
+	var events []Event
+	raw := `[{"id":"e","endDate":"next week","markets":[
+		{"id":"ok","bestBid":0.40,"bestAsk":0.44,"liquidityNum":100},
+		{"id":"empty","liquidityNum":100},
+		{"id":"bad","bestBid":0.50,"liquidityNum":100,"updatedAt":"yesterday"}]}]`
+	if err := json.Unmarshal([]byte(raw), &events); err != nil {
+		t.Fatal(err)
+	}
+	s := BuildSnapshot(events)
+
+	if math.Abs(s.Stats.AvgSpread-0.04) > 1e-9 {
+		t.Fatalf("expected AvgSpread over the one market with a spread, got %v", s.Stats.AvgSpread)
+	}
+	es := s.Events[0]
+	if len(es.ParseErrors) != 1 || es.Markets[2].ParseErrors[0] != "updatedAt" {
+		t.Fatalf("expected parse failures recorded, got %v / %v", es.ParseErrors, es.Markets[2].ParseErrors)
+	}
+	if es.Markets[0].Quality.Score != 1 || es.Markets[1].Quality.Score != 0 {
+		t.Fatalf("unexpected market quality %+v / %+v", es.Markets[0].Quality, es.Markets[1].Quality)
+	}
+
+	q := s.Quality
+	if q == nil || q.Flagged != 2 || q.Issues[IssueParseError] != 2 || q.Issues[IssueNoQuote] != 1 {
+		t.Fatalf("unexpected snapshot quality %+v", q)
+	}
+	if math.Abs(q.Score-(1+0+0.4)/3) > 1e-9 {
+		t.Fatalf("expected the mean market score, got %v", q.Score)
+	}
+	if r := q.Reasons(); len(r) != 3 || r[0] != IssueParseError {
+		t.Fatalf("expected the most frequent reason first, got %v", r)
+	}
+
+	f := ComputeFeatures(es.Markets[2], nil, nil, nil)
+	if math.Abs(f.DataQuality-0.4) > 1e-9 {
+		t.Fatalf("expected the point's quality in the features, got %v", f.DataQuality)
+	}
+}
+
+func TestBuildSnapshot_CrossedBookOutOfAvgSpread(t *testing.T) {
+	var events []Event
+	raw := `[{"id":"e","markets":[
+		{"id":"ok","bestBid":0.40,"bestAsk":0.44,"liquidityNum":100},
+		{"id":"crossed","bestBid":0.60,"bestAsk":0.50,"liquidityNum":100}]}]`
+	if err := json.Unmarshal([]byte(raw), &events); err != nil {
+		t.Fatal(err)
+	}
+	s := BuildSnapshot(events)
+
+	if math.Abs(s.Stats.AvgSpread-0.04) > 1e-9 {
+		t.Fatalf("expected the crossed book out of AvgSpread, got %v", s.Stats.AvgSpread)
+	}
+	if s.Quality.Issues[IssueCrossedBook] != 1 {
+		t.Fatalf("expected the crossed book flagged, got %+v", s.Quality)
+	}
+}
+
+func TestBuildSnapshot_Timestamps(t *testing.T) {
+	var events []Event
+	raw := `[{"id":"e","endDate":"2025-11-04","createdAt":1762259400000,"markets":[
+		{"id":"a","bestBid":0.4,"bestAsk":0.44,"updatedAt":"2025-11-04T12:30:00.123456Z","closedTime":"soon"},
+		{"id":"b","bestBid":0.4,"bestAsk":0.44,"updatedAt":"2025-11-04 12:30:00+00","startDate":"?"}]}]`
+	if err := json.Unmarshal([]byte(raw), &events); err != nil {
+		t.Fatalf("bad timestamps must not fail the decode: %v", err)
+	}
+	s := BuildSnapshot(events)
+
+	es := s.Events[0]
+	if !es.EndDate.Equal(time.Date(2025, 11, 4, 0, 0, 0, 0, time.UTC)) || len(es.ParseErrors) != 0 {
+		t.Fatalf("expected a date-only end date, got %s %v", es.EndDate, es.ParseErrors)
+	}
+	if es.Markets[0].UpdatedAt.Nanosecond() != 123456000 || es.Markets[1].UpdatedAt.Hour() != 12 {
+		t.Fatalf("unexpected update times %s / %s", es.Markets[0].UpdatedAt, es.Markets[1].UpdatedAt)
+	}
+	if f := s.Stats.ParseFailures; len(f) != 2 || f["market.closedTime"] != 1 || f["market.startDate"] != 1 {
+		t.Fatalf("unexpected parse failures %v", f)
+	}
+}
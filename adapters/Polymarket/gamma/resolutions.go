@@ -0,0 +1,313 @@
+package polymarket
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ResolutionsFile is the file resolutions are persisted to, in the same
+// directory as the snapshots they label.
+const ResolutionsFile = "resolutions.json"
+
+// winningPrice is the settled price at or above which an outcome won.
+const winningPrice = 0.99
+
+// Resolution is how a market settled, normalized across venues.
+type Resolution struct {
+	MarketID string `json:"market_id"`
+	EventID  string `json:"event_id,omitempty"`
+	Source   string `json:"source"` // snapshot source of the venue
+
+	// Outcome is the winning outcome label; empty when the market settled
+	// between outcomes (e.g. 50/50).
+	Outcome string `json:"outcome,omitempty"`
+	// Value is what one YES (first outcome) share settled at, 0..1.
+	Value float64 `json:"value"`
+
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// Yes reports whether the YES side won.
+func (r Resolution) Yes() bool {
+	return r.Value > 0.5
+}
+
+// ResolutionStore holds resolutions keyed by ResolutionKey, so market IDs of
+// different venues never collide. The zero value is an empty store.
+type ResolutionStore struct {
+	// ScannedUntil is the close time of the most recent closed Gamma market
+	// already scanned for resolutions; later scans stop there.
+	ScannedUntil time.Time
+
+	byKey map[string]Resolution
+}
+
+// resolutionsFile is the file shape of a ResolutionStore.
+type resolutionsFile struct {
+	ScannedUntil time.Time    `json:"scanned_until,omitempty"`
+	Resolutions  []Resolution `json:"resolutions"`
+}
+
+// ResolutionKey is the store key of a market of the venue with the given
+// snapshot source.
+func ResolutionKey(source, marketID string) string {
+	return source + ":" + marketID
+}
+
+// Key is the store key of the resolution.
+func (r Resolution) Key() string {
+	return ResolutionKey(r.Source, r.MarketID)
+}
+
+// Get returns the resolution of the market of the venue.
+func (s *ResolutionStore) Get(source, marketID string) (Resolution, bool) {
+	r, ok := s.byKey[ResolutionKey(source, marketID)]
+	return r, ok
+}
+
+// Has reports whether the market of the venue is resolved.
+func (s *ResolutionStore) Has(source, marketID string) bool {
+	_, ok := s.Get(source, marketID)
+	return ok
+}
+
+// Len is the number of resolved markets.
+func (s *ResolutionStore) Len() int {
+	return len(s.byKey)
+}
+
+// All returns the resolutions sorted by source and market ID.
+func (s *ResolutionStore) All() []Resolution {
+	list := make([]Resolution, 0, len(s.byKey))
+	for _, r := range s.byKey {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key() < list[j].Key() })
+	return list
+}
+
+// LoadResolutions reads a resolutions file. A missing file is an empty store.
+// Files written as a bare list of resolutions (before ScannedUntil) load
+// with a zero ScannedUntil.
+func LoadResolutions(p string) (*ResolutionStore, error) {
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ResolutionStore{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f resolutionsFile
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &f.Resolutions)
+	} else {
+		err = json.Unmarshal(data, &f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	s := &ResolutionStore{ScannedUntil: f.ScannedUntil}
+	for i, r := range f.Resolutions {
+		if r.MarketID == "" {
+			return nil, fmt.Errorf("%s: resolution[%d]: market_id is required", p, i)
+		}
+		s.Add(r)
+	}
+	return s, nil
+}
+
+// Add stores resolutions, replacing earlier ones for the same market. It
+// returns how many markets were not resolved before.
+func (s *ResolutionStore) Add(rs ...Resolution) int {
+	if s.byKey == nil {
+		s.byKey = make(map[string]Resolution, len(rs))
+	}
+	added := 0
+	for _, r := range rs {
+		if _, ok := s.byKey[r.Key()]; !ok {
+			added++
+		}
+		s.byKey[r.Key()] = r
+	}
+	return added
+}
+
+// Save writes the store with its resolutions sorted by source and market ID.
+// The file is replaced atomically so readers never see a partial write.
+func (s *ResolutionStore) Save(p string) error {
+	data, err := json.MarshalIndent(resolutionsFile{ScannedUntil: s.ScannedUntil, Resolutions: s.All()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".resolutions-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// ResolveMarket returns the resolution of a closed Gamma market. Markets that
+// are open, still in UMA resolution or without settled prices are not
+// resolved.
+func ResolveMarket(m Market) (Resolution, bool) {
+	if m.Closed == nil || !*m.Closed || m.OutcomePrices == nil {
+		return Resolution{}, false
+	}
+	if m.UMAResolutionStatus != nil && *m.UMAResolutionStatus != "" && *m.UMAResolutionStatus != "resolved" {
+		return Resolution{}, false
+	}
+
+	prices, err := decodeStringArray(*m.OutcomePrices)
+	if err != nil || len(prices) == 0 {
+		return Resolution{}, false
+	}
+	values := make([]float64, len(prices))
+	for i, p := range prices {
+		if values[i], err = strconv.ParseFloat(p, 64); err != nil {
+			return Resolution{}, false
+		}
+	}
+
+	var outcomes []string
+	if m.Outcomes != nil {
+		outcomes, _ = decodeStringArray(*m.Outcomes)
+	}
+
+	r := Resolution{
+		MarketID: m.ID,
+		Source:   SnapshotSource,
+		Value:    values[0],
+	}
+	if len(m.Events) > 0 {
+		r.EventID = m.Events[0].ID
+	}
+	for i, v := range values {
+		if v >= winningPrice && i < len(outcomes) {
+			r.Outcome = outcomes[i]
+		}
+	}
+
+	switch {
+	case !m.ClosedTime.IsZero():
+		r.ResolvedAt = m.ClosedTime.Time
+	case !m.EndDate.IsZero():
+		r.ResolvedAt = m.EndDate.Time
+	}
+	return r, true
+}
+
+// FetchClosedMarkets lists closed markets, most recently closed first.
+func (c *Client) FetchClosedMarkets(limit, offset int) ([]Market, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var markets []Market
+	err := c.getJSON(fmt.Sprintf(
+		"%s/markets?closed=true&order=closedTime&ascending=false&limit=%d&offset=%d",
+		c.BaseURL, limit, offset,
+	), &markets)
+	return markets, err
+}
+
+// Paging of FetchResolutions.
+const (
+	closedPageSize = 500
+	// DefaultMaxClosedPages bounds the closed markets FetchResolutions reads
+	// per call when maxPages is 0.
+	DefaultMaxClosedPages = 20
+)
+
+// FetchResolutions pages through the closed markets, most recently closed
+// first, and returns the resolutions of the given markets. It stops once every
+// market is resolved, at the first market closed before since, or after
+// maxPages pages (0 means DefaultMaxClosedPages). Markets still open are not
+// fetched one by one; they simply do not appear.
+//
+// scanned is the close time to pass as since next time: that of the most
+// recent closed market, or of the earliest requested market that closed
+// without a settled resolution yet (e.g. still in UMA resolution), so it is
+// scanned again. It is since itself when every market resolved before the scan
+// reached it, and zero when maxPages stopped the scan, as markets between the
+// last page seen and since were not.
+func (c *Client) FetchResolutions(ids []string, since time.Time, maxPages int) (rs []Resolution, scanned time.Time, err error) {
+	if maxPages <= 0 {
+		maxPages = DefaultMaxClosedPages
+	}
+	pending := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		pending[id] = true
+	}
+
+	var newest, unsettled time.Time
+	scannedUntil := func() time.Time {
+		if !unsettled.IsZero() && unsettled.Before(newest) {
+			return unsettled
+		}
+		return newest
+	}
+	for page := 0; len(pending) > 0; page++ {
+		if page == maxPages {
+			return rs, time.Time{}, nil
+		}
+		markets, err := c.FetchClosedMarkets(closedPageSize, page*closedPageSize)
+		if err != nil {
+			return rs, time.Time{}, err
+		}
+		for _, m := range markets {
+			if m.ClosedTime.After(newest) {
+				newest = m.ClosedTime.Time
+			}
+			if !since.IsZero() && !m.ClosedTime.IsZero() && m.ClosedTime.Before(since) {
+				return rs, scannedUntil(), nil
+			}
+			if !pending[m.ID] {
+				continue
+			}
+			r, ok := ResolveMarket(m)
+			if !ok {
+				if unsettled.IsZero() || m.ClosedTime.Before(unsettled) {
+					unsettled = m.ClosedTime.Time
+				}
+				continue
+			}
+			delete(pending, m.ID)
+			rs = append(rs, r)
+		}
+		if len(markets) < closedPageSize {
+			return rs, scannedUntil(), nil
+		}
+	}
+	// Every market resolved before the scan reached since: older closed
+	// markets were not seen, so the cursor stays where it was.
+	return rs, since, nil
+}
+
+// decodeStringArray decodes the JSON arrays Gamma encodes into strings,
+// e.g. "[\"Yes\", \"No\"]".
+func decodeStringArray(s string) ([]string, error) {
+	var out []string
+	err := json.Unmarshal([]byte(s), &out)
+	return out, err
+}
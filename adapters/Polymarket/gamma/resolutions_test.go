@@ -0,0 +1,168 @@
+package polymarket
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"woodpecker/adapters/timestamp"
+)
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
+
+func closedMarket(id, prices string) Market {
+	return Market{
+		ID:            id,
+		Closed:        boolPtr(true),
+		Outcomes:      strPtr(`["Yes", "No"]`),
+		OutcomePrices: strPtr(prices),
+		ClosedTime:    timestamp.FromString("2025-11-05 04:12:31+00"),
+	}
+}
+
+func TestResolveMarket(t *testing.T) {
+	r, ok := ResolveMarket(closedMarket("1", `["0", "1"]`))
+	if !ok {
+		t.Fatal("expected a closed market to resolve")
+	}
+	if r.Outcome != "No" || r.Yes() || r.Source != SnapshotSource {
+		t.Fatalf("unexpected resolution %+v", r)
+	}
+	if !r.ResolvedAt.Equal(time.Date(2025, 11, 5, 4, 12, 31, 0, time.UTC)) {
+		t.Fatalf("unexpected resolution time %s", r.ResolvedAt)
+	}
+
+	split, ok := ResolveMarket(closedMarket("2", `["0.5", "0.5"]`))
+	if !ok || split.Outcome != "" || split.Value != 0.5 {
+		t.Fatalf("expected a 50/50 settlement without winner, got %+v", split)
+	}
+
+	open := closedMarket("3", `["0.4", "0.6"]`)
+	open.Closed = boolPtr(false)
+	if _, ok := ResolveMarket(open); ok {
+		t.Fatal("an open market must not resolve")
+	}
+
+	disputed := closedMarket("4", `["1", "0"]`)
+	disputed.UMAResolutionStatus = strPtr("disputed")
+	if _, ok := ResolveMarket(disputed); ok {
+		t.Fatal("a market still in UMA resolution must not resolve")
+	}
+}
+
+func TestResolutionStore_SaveLoad(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ResolutionsFile)
+
+	empty, err := LoadResolutions(p)
+	if err != nil || empty.Len() != 0 {
+		t.Fatalf("a missing file must load as an empty store, got %v %v", empty, err)
+	}
+
+	store := ResolutionStore{ScannedUntil: time.Date(2025, 11, 5, 0, 0, 0, 0, time.UTC)}
+	if added := store.Add(Resolution{MarketID: "b", Value: 1}, Resolution{MarketID: "a"}); added != 2 {
+		t.Fatalf("expected 2 new resolutions, got %d", added)
+	}
+	if added := store.Add(Resolution{MarketID: "a", Value: 1}); added != 0 {
+		t.Fatalf("replacing a resolution must not count as new, got %d", added)
+	}
+	if added := store.Add(Resolution{MarketID: "a", Source: "kalshi"}); added != 1 {
+		t.Fatalf("the same market ID of another venue must count as new, got %d", added)
+	}
+	if err := store.Save(p); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadResolutions(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := loaded.Get("", "a")
+	kalshiA, _ := loaded.Get("kalshi", "a")
+	if loaded.Len() != 3 || !a.Yes() || kalshiA.Yes() || !loaded.ScannedUntil.Equal(store.ScannedUntil) {
+		t.Fatalf("unexpected store %+v", loaded)
+	}
+}
+
+func TestLoadResolutions_List(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ResolutionsFile)
+	if err := os.WriteFile(p, []byte(`[{"market_id":"a","source":"kalshi","value":1}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := LoadResolutions(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.Has("kalshi", "a") || !store.ScannedUntil.IsZero() {
+		t.Fatalf("expected a list of resolutions to load without cursor, got %+v", store)
+	}
+}
+
+func closedPage(markets ...string) string {
+	return "[" + strings.Join(markets, ",") + "]"
+}
+
+func TestFetchResolutions(t *testing.T) {
+	var offsets []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/markets" || r.URL.Query().Get("closed") != "true" {
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		_, _ = w.Write([]byte(closedPage(
+			`{"id":"9","closed":true,"outcomePrices":"[\"0\",\"1\"]","closedTime":"2025-11-06T00:00:00Z"}`,
+			`{"id":"2","closed":true,"umaResolutionStatus":"proposed","outcomePrices":"[\"1\",\"0\"]","closedTime":"2025-11-05T10:00:00Z"}`,
+			`{"id":"1","closed":true,"outcomes":"[\"Yes\",\"No\"]","outcomePrices":"[\"1\",\"0\"]","closedTime":"2025-11-05T04:12:31Z","events":[{"id":"e1"}]}`,
+			`{"id":"3","closed":true,"outcomePrices":"[\"1\",\"0\"]","closedTime":"2025-10-01T00:00:00Z"}`,
+		)))
+	}))
+	defer srv.Close()
+
+	c := NewClient().WithHTTP(srv.Client())
+	c.BaseURL = srv.URL
+
+	since := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	rs, scanned, err := c.FetchResolutions([]string{"1", "2", "3", "4"}, since, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || rs[0].MarketID != "1" || rs[0].EventID != "e1" || rs[0].Outcome != "Yes" {
+		t.Fatalf("unexpected resolutions %+v", rs)
+	}
+	if len(offsets) != 1 {
+		t.Fatalf("expected a single page before since, got offsets %v", offsets)
+	}
+	// Market 2 closed without a settled resolution: it is scanned again.
+	if want := time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC); !scanned.Equal(want) {
+		t.Fatalf("expected the scan to resume at the unsettled market, got %s", scanned)
+	}
+}
+
+func TestFetchResolutions_PageLimit(t *testing.T) {
+	full := make([]string, closedPageSize)
+	for i := range full {
+		full[i] = fmt.Sprintf(`{"id":"x%d","closed":true,"outcomePrices":"[\"1\",\"0\"]","closedTime":"2025-11-06T00:00:00Z"}`, i)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(closedPage(full...)))
+	}))
+	defer srv.Close()
+
+	c := NewClient().WithHTTP(srv.Client())
+	c.BaseURL = srv.URL
+
+	_, scanned, err := c.FetchResolutions([]string{"1"}, time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !scanned.IsZero() {
+		t.Fatalf("a scan cut short by the page limit must not move the cursor, got %s", scanned)
+	}
+}
@@ -6,64 +6,75 @@ import (
 	"woodpecker/planning/reasoner"
 )
 
-// BuildSignals maps continuous features into logical signals.
+// SignalThresholds are the minimum values BuildSignals requires to emit each signal.
+var SignalThresholds = map[string]float64{
+	"PROBABILITY_ACCELERATION": 0.60,
+	"CONVICTION_SPIKE":         0.60,
+	"DIVERGENCE_ALERT":         0.55,
+	"LOW_CONFIDENCE_MOVE":      0.55,
+	"REGIME_SHIFT":             0.60,
+	"CROSS_VENUE_DIVERGENCE":   0.55,
+}
+
+// BuildSignals maps continuous features into logical signals, keeping only
+// those above their threshold in SignalThresholds.
 // Thresholds live here for now; later you can move them to YAML/config.
 func BuildSignals(f FeatureVector) []reasoner.SignalInput {
 	var out []reasoner.SignalInput
+	for _, s := range BuildAllSignals(f) {
+		if s.Value > SignalThresholds[s.SignalID] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
 
+// BuildAllSignals maps continuous features into every logical signal,
+// without thresholds. Use it when the reasoner's rules apply their own
+// thresholds (and require the signals to be present).
+func BuildAllSignals(f FeatureVector) []reasoner.SignalInput {
 	// 1) PROBABILITY_ACCELERATION
 	// Momentum is in log-odds space. We squash it into [0..1].
 	// Positive large momentum => close to 1.
 	accel := squashSigned(f.ProbabilityMomentum, 0.35) // scale factor
-	if accel > 0.60 {
-		out = append(out, reasoner.SignalInput{
-			SignalID: "PROBABILITY_ACCELERATION",
-			Value:    accel,
-		})
-	}
 
 	// 2) CONVICTION_SPIKE
 	// f.ImpliedConfidence is already ~[0..1]
-	if f.ImpliedConfidence > 0.60 {
-		out = append(out, reasoner.SignalInput{
-			SignalID: "CONVICTION_SPIKE",
-			Value:    clamp01(f.ImpliedConfidence),
-		})
-	}
+	conviction := clamp01(f.ImpliedConfidence)
 
 	// 3) DIVERGENCE_ALERT
 	// Dispersion is stdev of log-odds across peers. Convert to [0..1].
 	div := squashPositive(f.Dispersion, 0.8)
-	if div > 0.55 {
-		out = append(out, reasoner.SignalInput{
-			SignalID: "DIVERGENCE_ALERT",
-			Value:    div,
-		})
-	}
 
 	// 4) LOW_CONFIDENCE_MOVE
 	// “Move” without confidence: high acceleration while confidence is low.
 	// This is your LOW_CONFIDENCE_MOVE definition.
-	lowConfMove := accel * (1.0 - clamp01(f.ImpliedConfidence))
-	if lowConfMove > 0.55 {
-		out = append(out, reasoner.SignalInput{
-			SignalID: "LOW_CONFIDENCE_MOVE",
-			Value:    clamp01(lowConfMove),
-		})
-	}
+	lowConfMove := clamp01(accel * (1.0 - conviction))
 
 	// 5) REGIME_SHIFT (composite)
 	// A regime shift is: strong acceleration + decent confidence + low volatility (stable belief)
 	// Here “low volatility” means BeliefVolatility is small.
 	volPenalty := 1.0 - squashPositive(f.BeliefVolatility, 1.2)
-	regime := clamp01(0.45*accel + 0.35*clamp01(f.ImpliedConfidence) + 0.20*volPenalty)
-	if regime > 0.60 {
+	regime := clamp01(0.45*accel + 0.35*conviction + 0.20*volPenalty)
+
+	out := []reasoner.SignalInput{
+		{SignalID: "PROBABILITY_ACCELERATION", Value: accel},
+		{SignalID: "CONVICTION_SPIKE", Value: conviction},
+		{SignalID: "DIVERGENCE_ALERT", Value: div},
+		{SignalID: "LOW_CONFIDENCE_MOVE", Value: lowConfMove},
+		{SignalID: "REGIME_SHIFT", Value: regime},
+	}
+
+	// 6) CROSS_VENUE_DIVERGENCE (only for markets linked across venues)
+	// The executable gap left after spreads and fees, squashed so that
+	// ~4 points is strong, and discounted when it has not persisted.
+	if cv := f.CrossVenue; cv != nil {
+		gap := squashPositive(math.Abs(cv.AdjustedGap), 0.04)
 		out = append(out, reasoner.SignalInput{
-			SignalID: "REGIME_SHIFT",
-			Value:    regime,
+			SignalID: "CROSS_VENUE_DIVERGENCE",
+			Value:    clamp01(gap * (0.5 + 0.5*cv.Persistence)),
 		})
 	}
-
 	return out
 }
 
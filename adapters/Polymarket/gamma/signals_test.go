@@ -0,0 +1,32 @@
+package polymarket
+
+import "testing"
+
+func TestBuildSignals_ThresholdsAllSignals(t *testing.T) {
+	f := FeatureVector{
+		ProbabilityMomentum: 0.6,
+		ImpliedConfidence:   0.9,
+		Dispersion:          0.1,
+		CrossVenue:          &CrossVenueFeatures{AdjustedGap: -0.05, Persistence: 1},
+	}
+
+	all := BuildAllSignals(f)
+	if len(all) != len(SignalThresholds) {
+		t.Fatalf("expected %d signals, got %d", len(SignalThresholds), len(all))
+	}
+
+	emitted := map[string]float64{}
+	for _, s := range BuildSignals(f) {
+		emitted[s.SignalID] = s.Value
+	}
+	for _, s := range all {
+		v, ok := emitted[s.SignalID]
+		above := s.Value > SignalThresholds[s.SignalID]
+		if ok != above || (ok && v != s.Value) {
+			t.Fatalf("%s: value %.4f, emitted=%v, threshold %.2f", s.SignalID, s.Value, ok, SignalThresholds[s.SignalID])
+		}
+	}
+	if _, ok := emitted["DIVERGENCE_ALERT"]; ok {
+		t.Fatal("low dispersion must not emit DIVERGENCE_ALERT")
+	}
+}
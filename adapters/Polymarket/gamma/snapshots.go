@@ -3,57 +3,88 @@ package polymarket
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
+
+	"woodpecker/adapters/timestamp"
 )
 
+// SnapshotSource identifies Gamma snapshots.
+const SnapshotSource = "polymarket-gamma"
+
 // Snapshot is a frozen view of Gamma at time T.
 type Snapshot struct {
-	SnapshotID string
-	Timestamp  time.Time
-	Source     string
+	SnapshotID string    `json:"snapshot_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Source     string    `json:"source"`
 
-	Events []EventSnapshot
-	Stats  SnapshotStats
+	Events []EventSnapshot `json:"events"`
+	Stats  SnapshotStats   `json:"stats"`
+
+	// Quality is set by AssessQuality.
+	Quality *SnapshotQuality `json:"quality,omitempty"`
 }
 
 type EventSnapshot struct {
-	EventID string
-	Slug    string
-	Title   string
-	EndDate time.Time
+	EventID string    `json:"event_id"`
+	Slug    string    `json:"slug,omitempty"`
+	Title   string    `json:"title,omitempty"`
+	EndDate time.Time `json:"end_date"`
+	Tags    []string  `json:"tags,omitempty"` // tag slugs
+
+	Liquidity float64 `json:"liquidity"`
+	Volume    float64 `json:"volume"`
 
-	Liquidity float64
-	Volume    float64
+	Markets []MarketPoint `json:"markets"`
 
-	Markets []MarketPoint
+	// ParseErrors names the event fields that could not be parsed.
+	ParseErrors []string `json:"parse_errors,omitempty"`
 }
 
 // MarketPoint is the normalized per-market slice used by feature/signal logic.
 type MarketPoint struct {
-	MarketID    string
-	Slug        string
-	ConditionID string
+	MarketID    string `json:"market_id"`
+	Slug        string `json:"slug,omitempty"`
+	ConditionID string `json:"condition_id,omitempty"`
+	Title       string `json:"title,omitempty"`    // market question, used for matching
+	Outcome     string `json:"outcome,omitempty"`  // outcome in a multi-outcome event
+	TokenID     string `json:"token_id,omitempty"` // CLOB token of the YES outcome
 
-	BestBid  float64
-	BestAsk  float64
-	MidPrice float64
-	Spread   float64
+	BestBid  float64 `json:"best_bid"`
+	BestAsk  float64 `json:"best_ask"`
+	MidPrice float64 `json:"mid_price"`
+	Spread   float64 `json:"spread"`
 
-	Liquidity float64
-	Volume    float64
+	Liquidity float64 `json:"liquidity"`
+	Volume    float64 `json:"volume"`
 
-	LastTrade float64
-	UpdatedAt time.Time
+	LastTrade float64   `json:"last_trade"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Book is the order book, when it was fetched.
+	Book *OrderBook `json:"book,omitempty"`
+
+	// ParseErrors names the market fields that could not be parsed; Quality
+	// is set by AssessQuality.
+	ParseErrors []string `json:"parse_errors,omitempty"`
+	Quality     *Quality `json:"quality,omitempty"`
 }
 
 type SnapshotStats struct {
-	TotalEvents    int
-	TotalMarkets   int
-	AvgLiquidity   float64
-	AvgSpread      float64
-	ExtremeMarkets int
+	TotalEvents    int     `json:"total_events"`
+	TotalMarkets   int     `json:"total_markets"`
+	AvgLiquidity   float64 `json:"avg_liquidity"`
+	AvgSpread      float64 `json:"avg_spread"`
+	ExtremeMarkets int     `json:"extreme_markets"`
+
+	// ParseFailures counts the venue timestamps that could not be parsed,
+	// by "event.<field>" or "market.<field>".
+	ParseFailures map[string]int `json:"parse_failures,omitempty"`
 }
 
 // BuildSnapshot converts Gamma events into a normalized Snapshot.
@@ -64,6 +95,7 @@ func BuildSnapshot(events []Event) Snapshot {
 		eventSnapshots []EventSnapshot
 		totalLiquidity float64
 		totalSpread    float64
+		spreadCount    int
 		totalMarkets   int
 		extremeCount   int
 	)
@@ -81,13 +113,18 @@ func BuildSnapshot(events []Event) Snapshot {
 		if e.Title != nil {
 			es.Title = *e.Title
 		}
-
-		if e.EndDate != nil && *e.EndDate != "" {
-			if t, err := time.Parse(time.RFC3339, *e.EndDate); err == nil {
-				es.EndDate = t
+		for _, t := range e.Tags {
+			if slug := tagSlug(t); slug != "" {
+				es.Tags = append(es.Tags, slug)
 			}
 		}
 
+		es.EndDate = e.EndDate.Time
+		es.ParseErrors = timestamp.Failures(map[string]timestamp.Time{
+			"createdAt": e.CreatedAt,
+			"endDate":   e.EndDate,
+		})
+
 		for _, m := range e.Markets {
 			mp := MarketPoint{
 				MarketID:  m.ID,
@@ -104,11 +141,20 @@ func BuildSnapshot(events []Event) Snapshot {
 			if m.ConditionID != nil {
 				mp.ConditionID = *m.ConditionID
 			}
-			if m.UpdatedAt != nil && *m.UpdatedAt != "" {
-				if t, err := time.Parse(time.RFC3339, *m.UpdatedAt); err == nil {
-					mp.UpdatedAt = t
-				}
+			if m.Question != nil {
+				mp.Title = *m.Question
+			}
+			if m.GroupItemTitle != nil {
+				mp.Outcome = *m.GroupItemTitle
 			}
+			mp.TokenID = m.YesTokenID()
+			mp.UpdatedAt = m.UpdatedAt.Time
+			mp.ParseErrors = timestamp.Failures(map[string]timestamp.Time{
+				"startDate":  m.StartDate,
+				"endDate":    m.EndDate,
+				"updatedAt":  m.UpdatedAt,
+				"closedTime": m.ClosedTime,
+			})
 
 			// Mid price + spread
 			if mp.BestBid > 0 || mp.BestAsk > 0 {
@@ -116,7 +162,12 @@ func BuildSnapshot(events []Event) Snapshot {
 				if mp.BestBid > 0 && mp.BestAsk > 0 {
 					mp.MidPrice = (mp.BestBid + mp.BestAsk) / 2
 					mp.Spread = mp.BestAsk - mp.BestBid
-					totalSpread += mp.Spread
+					// Crossed books (bid > ask) are flagged by quality
+					// scoring and stay out of AvgSpread.
+					if mp.Spread > 0 {
+						totalSpread += mp.Spread
+						spreadCount++
+					}
 				} else if mp.BestBid > 0 {
 					mp.MidPrice = mp.BestBid
 				} else {
@@ -142,27 +193,71 @@ func BuildSnapshot(events []Event) Snapshot {
 		TotalEvents:    len(eventSnapshots),
 		TotalMarkets:   totalMarkets,
 		ExtremeMarkets: extremeCount,
+		ParseFailures:  ParseFailures(eventSnapshots),
 	}
 	if totalMarkets > 0 {
 		stats.AvgLiquidity = totalLiquidity / float64(totalMarkets)
-		// AvgSpread should only be computed over markets where spread exists
-		if totalSpread > 0 {
-			stats.AvgSpread = totalSpread / float64(totalMarkets)
-		}
+	}
+	// AvgSpread is only computed over markets where spread exists
+	if spreadCount > 0 {
+		stats.AvgSpread = totalSpread / float64(spreadCount)
 	}
 
 	s := Snapshot{
 		Timestamp: now,
-		Source:    "polymarket-gamma",
+		Source:    SnapshotSource,
 		Events:    eventSnapshots,
 		Stats:     stats,
 	}
-	s.SnapshotID = computeSnapshotID(s)
+	s.SnapshotID = SnapshotID(s)
+	AssessQuality(&s, DefaultQualityConfig)
 
 	return s
 }
 
-func computeSnapshotID(s Snapshot) string {
+// ParseFailures counts the ParseErrors of events and their markets for
+// SnapshotStats.
+func ParseFailures(events []EventSnapshot) map[string]int {
+	out := map[string]int{}
+	for _, es := range events {
+		for _, f := range es.ParseErrors {
+			out["event."+f]++
+		}
+		for _, mp := range es.Markets {
+			for _, f := range mp.ParseErrors {
+				out["market."+f]++
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// tagSlug identifies a tag by its slug, or its lower-cased label.
+func tagSlug(t Tag) string {
+	if t.Slug != "" {
+		return t.Slug
+	}
+	return strings.ToLower(strings.TrimSpace(t.Label))
+}
+
+// SaveSnapshot writes s as indented JSON into dir, named after its ID and
+// time like the Kalshi adapter's snapshots, and returns the file path.
+func SaveSnapshot(dir string, s Snapshot) (string, error) {
+	name := fmt.Sprintf("snapshot_%s_%s.json", s.SnapshotID, s.Timestamp.UTC().Format("2006-01-02T15-04-05Z"))
+	p := filepath.Join(dir, name)
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return p, os.WriteFile(p, append(data, '\n'), 0o644)
+}
+
+// SnapshotID is a deterministic ID over source, timestamp and market mid prices.
+func SnapshotID(s Snapshot) string {
 	// Deterministic: sort by event id then market id to keep stable across map ordering.
 	type row struct {
 		eid string
@@ -0,0 +1,120 @@
+// Package timestamp decodes the timestamps venues put in their JSON: RFC3339
+// with or without fractional seconds or a zone, Gamma's space-separated
+// forms, dates, and Unix epochs in seconds or milliseconds.
+package timestamp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// layouts are tried in order. Parsing accepts fractional seconds after the
+// seconds field even when the layout has none.
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05-07",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// epochMillis is the smallest epoch read as milliseconds rather than
+// seconds (in seconds it would be past the year 33000).
+const epochMillis = 1e12
+
+// Parse parses s in any supported form. Times without a zone are UTC; the
+// result is always UTC.
+func Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty timestamp")
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return fromEpoch(f), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
+
+func fromEpoch(f float64) time.Time {
+	if math.Abs(f) >= epochMillis {
+		return time.UnixMilli(int64(f)).UTC()
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
+
+// Time is a timestamp field of a venue model. It unmarshals from any form
+// Parse accepts, as a string or a number; null and "" leave it zero.
+// Unparseable values do not fail the decode: the time stays zero and the
+// value is kept in Raw (see Failed).
+type Time struct {
+	time.Time
+	Raw string
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		*t = Time{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		// Not a string: an epoch number (or garbage)
+		s = string(b)
+	}
+	*t = FromString(s)
+	return nil
+}
+
+// FromString is the Time a JSON string s decodes to.
+func FromString(s string) Time {
+	if strings.TrimSpace(s) == "" {
+		return Time{}
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return Time{Raw: s}
+	}
+	return Time{Time: parsed}
+}
+
+// Failed reports whether the field held a value that could not be parsed.
+func (t Time) Failed() bool {
+	return t.Time.IsZero() && t.Raw != ""
+}
+
+// MarshalJSON writes the time as RFC3339, the raw value of a failed parse as
+// is, and "" for zero, so that models round-trip.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.Time.IsZero() {
+		return json.Marshal(t.Raw)
+	}
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}
+
+// Failures returns the names of the fields that failed to parse, sorted.
+func Failures(fields map[string]Time) []string {
+	var out []string
+	for name, t := range fields {
+		if t.Failed() {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,79 @@
+package timestamp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	want := time.Date(2025, 11, 4, 12, 30, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"2025-11-04T12:30:00Z":        want,
+		"2025-11-04T12:30:00.123456Z": want.Add(123456 * time.Microsecond),
+		"2025-11-04T09:30:00-03:00":   want,
+		"2025-11-04T12:30:00":         want,
+		"2025-11-04 12:30:00+00":      want,
+		"2025-11-04 12:30:00.5+00:00": want.Add(500 * time.Millisecond),
+		"2025-11-04":                  time.Date(2025, 11, 4, 0, 0, 0, 0, time.UTC),
+		"1762259400":                  want,
+		"1762259400000":               want,
+		"1762259400.25":               want.Add(250 * time.Millisecond),
+		"  2025-11-04T12:30:00Z  ":    want,
+	}
+	for in, exp := range cases {
+		got, err := Parse(in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", in, err)
+		}
+		if !got.Equal(exp) || got.Location() != time.UTC {
+			t.Fatalf("%q: expected %s, got %s", in, exp, got)
+		}
+	}
+	for _, in := range []string{"", "next week", "2025-13-01"} {
+		if _, err := Parse(in); err == nil {
+			t.Fatalf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestTime_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		A, B, C, D, E Time
+	}
+	raw := `{"A":"2025-11-04T12:30:00Z","B":1762259400,"C":null,"D":"","E":"soon"}`
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatalf("bad timestamps must not fail the decode: %v", err)
+	}
+	if v.A.Unix() != 1762259400 || !v.B.Equal(v.A.Time) {
+		t.Fatalf("unexpected times %s / %s", v.A, v.B)
+	}
+	if !v.C.IsZero() || v.C.Failed() || !v.D.IsZero() || v.D.Failed() {
+		t.Fatal("null and empty must be zero without failing")
+	}
+	if !v.E.IsZero() || !v.E.Failed() || v.E.Raw != "soon" {
+		t.Fatalf("expected the failure recorded, got %+v", v.E)
+	}
+}
+
+func TestTime_RoundTrip(t *testing.T) {
+	in := map[string]Time{
+		"ok":    FromString("2025-11-04 12:30:00.5+00"),
+		"bad":   FromString("soon"),
+		"empty": {},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]Time
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out["ok"].Equal(in["ok"].Time) || out["bad"].Raw != "soon" || !out["empty"].IsZero() {
+		t.Fatalf("unexpected round trip %s: %+v", data, out)
+	}
+	if f := Failures(out); len(f) != 1 || f[0] != "bad" {
+		t.Fatalf("expected only the bad field to fail, got %v", f)
+	}
+}
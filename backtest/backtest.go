@@ -0,0 +1,299 @@
+// Package backtest replays historical snapshots through the feature, signal
+// and rule pipeline and scores the rules that fired against how the markets
+// resolved: hit rate, calibration of the reported confidence and lead time
+// before resolution, per rule and per status.
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"woodpecker/pipeline"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+// Target is what counts as a hit for an evaluation that fired.
+type Target string
+
+const (
+	// TargetReversal: the market resolved against its lean at the time of the
+	// evaluation (p >= 0.5 resolved NO, p < 0.5 resolved YES), i.e. the rule
+	// fired ahead of an inflection.
+	TargetReversal Target = "reversal"
+	// TargetMomentum: the market resolved in the direction it was moving at
+	// the time of the evaluation.
+	TargetMomentum Target = "momentum"
+)
+
+// ParseTarget accepts "reversal", "momentum" or "" (reversal).
+func ParseTarget(s string) (Target, error) {
+	switch t := Target(s); t {
+	case "":
+		return TargetReversal, nil
+	case TargetReversal, TargetMomentum:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown target '%s'", s)
+	}
+}
+
+// hit reports whether a market point at p moving by momentum hit the target.
+func (t Target) hit(p, momentum float64, o Outcome) bool {
+	switch t {
+	case TargetMomentum:
+		return (momentum > 0 && o.Yes) || (momentum < 0 && !o.Yes)
+	default:
+		return (p >= 0.5) != o.Yes
+	}
+}
+
+// Config is one backtest run.
+type Config struct {
+	Intents  []string
+	Reasoner reasoner.IntentReasoner
+	Outcomes Outcomes
+	Target   Target
+
+	// Signals, when set, restricts each intent to the signals it declares.
+	Signals intents.SignalMap
+
+	// HistoryLen bounds the points kept per market; 0 means
+	// pipeline.DefaultHistoryLen.
+	HistoryLen int
+}
+
+// Run replays frames in order. Every market of every frame is evaluated for
+// each intent; evaluations of unresolved markets, or taken after their
+// resolution, are counted but not scored.
+func Run(cfg Config, frames []Frame) (Report, error) {
+	target := cfg.Target
+	if target == "" {
+		target = TargetReversal
+	}
+	historyLen := cfg.HistoryLen
+	if historyLen <= 0 {
+		historyLen = pipeline.DefaultHistoryLen
+	}
+
+	p := &pipeline.Pipeline{History: pipeline.NewMemoryHistory(historyLen)}
+	rep := Report{Target: target, Frames: len(frames)}
+
+	var (
+		byRule   = map[string]*accumulator{}
+		byStatus = map[string]*accumulator{}
+		markets  = map[string]bool{}
+		baseline accumulator
+	)
+
+	for _, f := range frames {
+		at := f.Snapshot.Timestamp
+		for _, m := range p.Compute(f.Venue, f.Snapshot) {
+			key := pipeline.HistoryKey(m.Venue, m.Point.MarketID)
+			markets[key] = true
+
+			outcome, resolved := cfg.Outcomes[key]
+			scored := resolved && (outcome.ResolvedAt.IsZero() || !at.After(outcome.ResolvedAt))
+			hit := scored && target.hit(m.Features.PEvent, m.Features.ProbabilityMomentum, outcome)
+			if scored {
+				baseline.add(key, at, 0, hit, outcome)
+			}
+
+			for _, intentID := range cfg.Intents {
+				out, err := cfg.Reasoner.Evaluate(intentID, nil, intentSignals(cfg.Signals, intentID, m.Signals))
+				if err != nil {
+					return Report{}, fmt.Errorf("%s %s at %s: %w", key, intentID, at.Format(time.RFC3339), err)
+				}
+
+				rep.Evaluations++
+				switch {
+				case !resolved:
+					rep.Unresolved++
+					continue
+				case !scored:
+					rep.AfterResolution++
+					continue
+				}
+				rep.Scored++
+
+				group(byStatus, intentID, string(out.Status)).add(key, at, out.Confidence, hit, outcome)
+				for _, step := range out.Reasoning.Logic {
+					if step.RuleID != "" {
+						group(byRule, intentID, step.RuleID).add(key, at, out.Confidence, hit, outcome)
+					}
+				}
+			}
+		}
+	}
+
+	rep.Markets = len(markets)
+	rep.Baseline = baseline.hitRate()
+	rep.ByRule = groups(byRule, rep.Baseline)
+	rep.ByStatus = groups(byStatus, rep.Baseline)
+	return rep, nil
+}
+
+func intentSignals(m intents.SignalMap, intentID string, signals []reasoner.SignalInput) []reasoner.SignalInput {
+	out := make([]reasoner.SignalInput, 0, len(signals))
+	for _, s := range signals {
+		if m.Accepts(intentID, s.SignalID) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func group(m map[string]*accumulator, intentID, key string) *accumulator {
+	k := intentID + "|" + key
+	a, ok := m[k]
+	if !ok {
+		a = &accumulator{intentID: intentID, key: key, first: map[string]time.Time{}}
+		m[k] = a
+	}
+	return a
+}
+
+func groups(m map[string]*accumulator, baseline float64) []Group {
+	out := make([]Group, 0, len(m))
+	for _, a := range m {
+		out = append(out, a.group(baseline))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].IntentID != out[j].IntentID {
+			return out[i].IntentID < out[j].IntentID
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// calibrationBins splits confidence into equal-width bins.
+const calibrationBins = 10
+
+// accumulator collects the scored evaluations of one rule or status.
+type accumulator struct {
+	intentID, key string
+
+	n, hits  int
+	sumConf  float64
+	sumBrier float64
+	bins     [calibrationBins]struct {
+		n, hits int
+		sumConf float64
+	}
+
+	// first evaluation per market, with its resolution time, for lead time.
+	first      map[string]time.Time
+	resolvedAt map[string]time.Time
+}
+
+func (a *accumulator) add(market string, at time.Time, confidence float64, hit bool, o Outcome) {
+	h := 0.0
+	if hit {
+		h = 1
+		a.hits++
+	}
+	a.n++
+	a.sumConf += confidence
+	a.sumBrier += (confidence - h) * (confidence - h)
+
+	b := int(confidence * calibrationBins)
+	if b >= calibrationBins {
+		b = calibrationBins - 1
+	}
+	if b < 0 {
+		b = 0
+	}
+	a.bins[b].n++
+	a.bins[b].sumConf += confidence
+	if hit {
+		a.bins[b].hits++
+	}
+
+	if a.first == nil {
+		return
+	}
+	if t, ok := a.first[market]; !ok || at.Before(t) {
+		a.first[market] = at
+	}
+	if !o.ResolvedAt.IsZero() {
+		if a.resolvedAt == nil {
+			a.resolvedAt = map[string]time.Time{}
+		}
+		a.resolvedAt[market] = o.ResolvedAt
+	}
+}
+
+func (a *accumulator) hitRate() float64 {
+	if a.n == 0 {
+		return 0
+	}
+	return float64(a.hits) / float64(a.n)
+}
+
+func (a *accumulator) group(baseline float64) Group {
+	g := Group{
+		IntentID:       a.intentID,
+		Key:            a.key,
+		Evaluations:    a.n,
+		Hits:           a.hits,
+		HitRate:        a.hitRate(),
+		MeanConfidence: a.sumConf / float64(a.n),
+		Brier:          a.sumBrier / float64(a.n),
+		Markets:        len(a.first),
+	}
+	if baseline > 0 {
+		g.Lift = g.HitRate / baseline
+	}
+
+	for i, b := range a.bins {
+		if b.n == 0 {
+			continue
+		}
+		g.Calibration = append(g.Calibration, Bin{
+			Lower:          float64(i) / calibrationBins,
+			Upper:          float64(i+1) / calibrationBins,
+			Count:          b.n,
+			MeanConfidence: b.sumConf / float64(b.n),
+			HitRate:        float64(b.hits) / float64(b.n),
+		})
+	}
+
+	var leads []float64
+	for market, first := range a.first {
+		if resolved, ok := a.resolvedAt[market]; ok {
+			leads = append(leads, resolved.Sub(first).Hours())
+		}
+	}
+	g.LeadTime = leadTime(leads)
+	return g
+}
+
+func leadTime(hours []float64) LeadTime {
+	if len(hours) == 0 {
+		return LeadTime{}
+	}
+	sort.Float64s(hours)
+
+	var sum float64
+	for _, h := range hours {
+		sum += h
+	}
+	median := hours[len(hours)/2]
+	if len(hours)%2 == 0 {
+		median = (hours[len(hours)/2-1] + hours[len(hours)/2]) / 2
+	}
+	return LeadTime{
+		Markets:     len(hours),
+		MeanHours:   round(sum / float64(len(hours))),
+		MedianHours: round(median),
+		MinHours:    round(hours[0]),
+		MaxHours:    round(hours[len(hours)-1]),
+	}
+}
+
+func round(h float64) float64 {
+	return math.Round(h*100) / 100
+}
@@ -0,0 +1,209 @@
+package backtest
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/pipeline"
+	"woodpecker/planning/reasoner"
+)
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func kalshiMarket(ticker string, bid, ask int) model.Market {
+	return model.Market{Ticker: ticker, EventTicker: "KXTEST-26JAN01", YesBid: bid, YesAsk: ask, Liquidity: 500000}
+}
+
+func writeJSON(t *testing.T, p string, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fixtureDir holds two Kalshi snapshots an hour apart, in which market A jumps
+// from 0.20 to 0.60 while B stays at 0.50, and one Polymarket snapshot.
+func fixtureDir(t *testing.T) string {
+	dir := t.TempDir()
+	writeJSON(t, filepath.Join(dir, "snapshot_KXTEST-26JAN01_2026-01-01T01-00-00Z.json"), []model.Market{
+		kalshiMarket("A", 58, 62), kalshiMarket("B", 48, 52),
+	})
+	writeJSON(t, filepath.Join(dir, "snapshot_KXTEST-26JAN01_2026-01-01T00-00-00Z.json"), []model.Market{
+		kalshiMarket("A", 18, 22), kalshiMarket("B", 48, 52),
+	})
+	writeJSON(t, filepath.Join(dir, "polymarket.json"), polymarket.Snapshot{
+		SnapshotID: "p1",
+		Timestamp:  t0.Add(2 * time.Hour),
+		Source:     "polymarket-gamma",
+		Events: []polymarket.EventSnapshot{{
+			EventID: "e1",
+			Markets: []polymarket.MarketPoint{{MarketID: "P1", BestBid: 0.29, BestAsk: 0.31, MidPrice: 0.3, Spread: 0.02}},
+		}},
+	})
+	return dir
+}
+
+func accelReasoner() reasoner.IntentReasoner {
+	return reasoner.NewRuleBasedReasoner("test", reasoner.Ruleset{Rules: []reasoner.Rule{{
+		ID:     "accel",
+		Intent: "trigger.regime_change",
+		When: reasoner.ConditionBlock{All: []reasoner.Condition{
+			{Signal: "PROBABILITY_ACCELERATION", Op: "gte", Value: 0.8},
+		}},
+		Then: reasoner.RuleAction{Status: "strong_signal", ConfidenceBoost: 0.8},
+	}}})
+}
+
+func TestLoadFrames_SortedAndTyped(t *testing.T) {
+	frames, err := LoadFrames(fixtureDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	if !frames[0].Snapshot.Timestamp.Equal(t0) || frames[0].Venue != pipeline.VenueKalshi {
+		t.Fatalf("first frame must be the t0 Kalshi snapshot, got %s %s", frames[0].Venue, frames[0].Snapshot.Timestamp)
+	}
+	if frames[2].Venue != pipeline.VenuePolymarket {
+		t.Fatalf("expected the Polymarket snapshot last, got %s", frames[2].Venue)
+	}
+}
+
+func TestLoadFrame_StoredKalshiSnapshot(t *testing.T) {
+	f, err := LoadFrame("../adapters/Kalshi/snapshots/snapshot_KXBTCD-25DEC3117_2025-12-31T04-15-58Z.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 12, 31, 4, 15, 58, 0, time.UTC)
+	if !f.Snapshot.Timestamp.Equal(want) || f.Snapshot.Stats.TotalMarkets == 0 {
+		t.Fatalf("unexpected frame: ts=%s markets=%d", f.Snapshot.Timestamp, f.Snapshot.Stats.TotalMarkets)
+	}
+}
+
+func TestRun_ScoresRulesAgainstOutcomes(t *testing.T) {
+	frames, err := LoadFrames(fixtureDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolvedAt := t0.Add(25 * time.Hour)
+	outcomes := Outcomes{
+		"kalshi:A": {Venue: pipeline.VenueKalshi, MarketID: "A", Yes: false, ResolvedAt: resolvedAt},
+		"kalshi:B": {Venue: pipeline.VenueKalshi, MarketID: "B", Yes: true, ResolvedAt: resolvedAt},
+	}
+
+	rep, err := Run(Config{
+		Intents:  []string{"trigger.regime_change"},
+		Reasoner: accelReasoner(),
+		Outcomes: outcomes,
+	}, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rep.Evaluations != 5 || rep.Scored != 4 || rep.Unresolved != 1 {
+		t.Fatalf("unexpected counts %+v", rep)
+	}
+	// Only A at 0.60 resolving NO is a reversal.
+	if math.Abs(rep.Baseline-0.25) > 1e-9 {
+		t.Fatalf("expected baseline 0.25, got %v", rep.Baseline)
+	}
+
+	if len(rep.ByRule) != 1 {
+		t.Fatalf("expected one rule group, got %+v", rep.ByRule)
+	}
+	g := rep.ByRule[0]
+	if g.Key != "accel" || g.Evaluations != 1 || g.Hits != 1 || g.Lift != 4 {
+		t.Fatalf("unexpected rule group %+v", g)
+	}
+	if g.LeadTime.Markets != 1 || g.LeadTime.MedianHours != 24 {
+		t.Fatalf("expected a 24h lead time, got %+v", g.LeadTime)
+	}
+	if len(g.Calibration) != 1 || g.Calibration[0].Count != 1 {
+		t.Fatalf("unexpected calibration %+v", g.Calibration)
+	}
+
+	statuses := map[string]Group{}
+	for _, s := range rep.ByStatus {
+		statuses[s.Key] = s
+	}
+	if statuses["strong_signal"].Evaluations != 1 || statuses["low_confidence"].Evaluations != 3 || statuses["low_confidence"].Hits != 0 {
+		t.Fatalf("unexpected status groups %+v", rep.ByStatus)
+	}
+}
+
+func TestRun_SkipsEvaluationsAfterResolution(t *testing.T) {
+	frames, err := LoadFrames(fixtureDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomes := Outcomes{
+		"kalshi:A": {Venue: pipeline.VenueKalshi, MarketID: "A", ResolvedAt: t0.Add(30 * time.Minute)},
+	}
+
+	rep, err := Run(Config{Intents: []string{"trigger.regime_change"}, Reasoner: accelReasoner(), Outcomes: outcomes}, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Scored != 1 || rep.AfterResolution != 1 || len(rep.ByRule) != 0 {
+		t.Fatalf("evaluations after resolution must not be scored: %+v", rep)
+	}
+}
+
+func TestTarget_Momentum(t *testing.T) {
+	if !TargetMomentum.hit(0.6, 0.3, Outcome{Yes: true}) || TargetMomentum.hit(0.6, 0, Outcome{Yes: true}) {
+		t.Fatal("momentum hits need a move in the resolved direction")
+	}
+	if _, err := ParseTarget("vibes"); err == nil {
+		t.Fatal("expected unknown target to be rejected")
+	}
+}
+
+func TestLoadOutcomes_Duplicate(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "outcomes.json")
+	writeJSON(t, p, []Outcome{
+		{Venue: pipeline.VenueKalshi, MarketID: "A"},
+		{Venue: pipeline.VenueKalshi, MarketID: "A", Yes: true},
+	})
+	if _, err := LoadOutcomes(p); err == nil {
+		t.Fatal("expected duplicate outcome to be rejected")
+	}
+}
+
+func TestLoadResolutions_NextToSnapshots(t *testing.T) {
+	dir := fixtureDir(t)
+	store := polymarket.ResolutionStore{}
+	store.Add(
+		polymarket.Resolution{MarketID: "A", Source: "kalshi", Outcome: "No", ResolvedAt: t0.Add(25 * time.Hour)},
+		polymarket.Resolution{MarketID: "P1", Source: polymarket.SnapshotSource, Outcome: "Yes", Value: 1},
+	)
+	if err := store.Save(filepath.Join(dir, polymarket.ResolutionsFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	frames, err := LoadFrames(dir)
+	if err != nil || len(frames) != 3 {
+		t.Fatalf("resolutions must not be loaded as a snapshot: %d frames, %v", len(frames), err)
+	}
+
+	outcomes, err := LoadResolutions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o, ok := outcomes["kalshi:A"]; !ok || o.Yes || !o.ResolvedAt.Equal(t0.Add(25*time.Hour)) {
+		t.Fatalf("unexpected Kalshi outcome %+v", o)
+	}
+	if o, ok := outcomes["polymarket:P1"]; !ok || !o.Yes {
+		t.Fatalf("unexpected Polymarket outcome %+v", o)
+	}
+}
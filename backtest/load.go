@@ -0,0 +1,196 @@
+package backtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"woodpecker/adapters/Kalshi/kalshi"
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/pipeline"
+)
+
+// Frame is one historical snapshot.
+type Frame struct {
+	Venue    pipeline.Venue
+	Path     string
+	Snapshot polymarket.Snapshot
+}
+
+// kalshiFileTime matches the timestamp adapters/Kalshi writes into snapshot
+// file names (snapshot_<EVENT>_2006-01-02T15-04-05Z.json).
+var kalshiFileTime = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}Z`)
+
+const kalshiFileTimeLayout = "2006-01-02T15-04-05Z"
+
+// LoadFrames reads every *.json snapshot under the given files or directories
+// and returns them sorted by snapshot time.
+func LoadFrames(paths ...string) ([]Frame, error) {
+	var frames []Frame
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		files := []string{p}
+		if info.IsDir() {
+			if files, err = filepath.Glob(filepath.Join(p, "*.json")); err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range files {
+			if info.IsDir() && skipFile(filepath.Base(f)) {
+				continue
+			}
+			frame, err := LoadFrame(f)
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, frame)
+		}
+	}
+
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Snapshot.Timestamp.Before(frames[j].Snapshot.Timestamp)
+	})
+	return frames, nil
+}
+
+// skipFile reports whether a directory entry is not a snapshot: the
+// resolutions stored next to the snapshots and hidden (temporary) files.
+func skipFile(name string) bool {
+	return name == polymarket.ResolutionsFile || strings.HasPrefix(name, ".")
+}
+
+// LoadFrame reads one snapshot file. A JSON array is a list of Kalshi markets
+// as written by adapters/Kalshi, timestamped from the file name; a JSON
+// object is a polymarket.Snapshot (Kalshi when its source says so).
+func LoadFrame(p string) (Frame, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return Frame{}, err
+	}
+
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		var markets []model.Market
+		if err := json.Unmarshal(data, &markets); err != nil {
+			return Frame{}, fmt.Errorf("%s: %w", p, err)
+		}
+		ts := kalshiFileTime.FindString(filepath.Base(p))
+		if ts == "" {
+			return Frame{}, fmt.Errorf("%s: no snapshot time in file name", p)
+		}
+		t, err := time.Parse(kalshiFileTimeLayout, ts)
+		if err != nil {
+			return Frame{}, fmt.Errorf("%s: %w", p, err)
+		}
+		return Frame{Venue: pipeline.VenueKalshi, Path: p, Snapshot: kalshi.BuildSnapshot(markets, t)}, nil
+
+	case len(data) > 0 && data[0] == '{':
+		var snap polymarket.Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return Frame{}, fmt.Errorf("%s: %w", p, err)
+		}
+		if snap.Timestamp.IsZero() {
+			return Frame{}, fmt.Errorf("%s: snapshot has no timestamp", p)
+		}
+		venue := pipeline.VenuePolymarket
+		if strings.HasPrefix(snap.Source, kalshi.SnapshotSource) {
+			venue = pipeline.VenueKalshi
+		}
+		return Frame{Venue: venue, Path: p, Snapshot: snap}, nil
+
+	default:
+		return Frame{}, fmt.Errorf("%s: not a snapshot file", p)
+	}
+}
+
+// Outcome is how a market resolved.
+type Outcome struct {
+	Venue      pipeline.Venue `json:"venue"`
+	MarketID   string         `json:"market_id"`
+	Yes        bool           `json:"yes"`
+	ResolvedAt time.Time      `json:"resolved_at"`
+}
+
+// Outcomes are keyed by pipeline.HistoryKey.
+type Outcomes map[string]Outcome
+
+// FromResolutions labels markets with their venue resolutions.
+func FromResolutions(store *polymarket.ResolutionStore) Outcomes {
+	out := make(Outcomes, store.Len())
+	for _, r := range store.All() {
+		venue := pipeline.VenuePolymarket
+		if strings.HasPrefix(r.Source, kalshi.SnapshotSource) {
+			venue = pipeline.VenueKalshi
+		}
+		out[pipeline.HistoryKey(venue, r.MarketID)] = Outcome{
+			Venue:      venue,
+			MarketID:   r.MarketID,
+			Yes:        r.Yes(),
+			ResolvedAt: r.ResolvedAt,
+		}
+	}
+	return out
+}
+
+// LoadResolutions reads the resolutions stored next to the snapshots in the
+// given directories (files are skipped) and labels their markets.
+func LoadResolutions(paths ...string) (Outcomes, error) {
+	store := &polymarket.ResolutionStore{}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			continue
+		}
+		rs, err := polymarket.LoadResolutions(filepath.Join(p, polymarket.ResolutionsFile))
+		if err != nil {
+			return nil, err
+		}
+		store.Add(rs.All()...)
+	}
+	return FromResolutions(store), nil
+}
+
+// LoadOutcomes reads a JSON list of outcomes.
+func LoadOutcomes(p string) (Outcomes, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Outcome
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+
+	out := make(Outcomes, len(list))
+	for i, o := range list {
+		ref := pipeline.MarketRef{Venue: o.Venue, MarketID: o.MarketID}
+		if o.MarketID == "" {
+			return nil, fmt.Errorf("%s: outcome[%d]: market_id is required", p, i)
+		}
+		if err := ref.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: outcome[%d]: %w", p, i, err)
+		}
+		key := pipeline.HistoryKey(o.Venue, o.MarketID)
+		if _, dup := out[key]; dup {
+			return nil, fmt.Errorf("%s: duplicate outcome for %s", p, key)
+		}
+		out[key] = o
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package backtest
+
+import (
+	"fmt"
+	"io"
+)
+
+// Report is the result of a backtest run.
+type Report struct {
+	Target Target `json:"target"`
+
+	Frames      int `json:"frames"`
+	Markets     int `json:"markets"`
+	Evaluations int `json:"evaluations"`
+
+	// Scored evaluations were taken on resolved markets before resolution.
+	Scored          int `json:"scored"`
+	Unresolved      int `json:"unresolved"`
+	AfterResolution int `json:"after_resolution"`
+
+	// Baseline is the target hit rate over every scored market point,
+	// whether or not a rule fired.
+	Baseline float64 `json:"baseline_hit_rate"`
+
+	ByRule   []Group `json:"by_rule"`
+	ByStatus []Group `json:"by_status"`
+}
+
+// Group scores the evaluations in which a rule contributed, or that ended
+// in a status.
+type Group struct {
+	IntentID string `json:"intent_id"`
+	Key      string `json:"key"` // rule ID or status
+
+	Evaluations int     `json:"evaluations"`
+	Hits        int     `json:"hits"`
+	HitRate     float64 `json:"hit_rate"`
+	Lift        float64 `json:"lift"` // HitRate / Baseline
+
+	// MeanConfidence and Brier treat the output confidence as the
+	// probability of a hit.
+	MeanConfidence float64 `json:"mean_confidence"`
+	Brier          float64 `json:"brier"`
+	Calibration    []Bin   `json:"calibration"`
+
+	Markets  int      `json:"markets"`
+	LeadTime LeadTime `json:"lead_time"`
+}
+
+// Bin is one confidence bucket of a calibration curve.
+type Bin struct {
+	Lower          float64 `json:"lower"`
+	Upper          float64 `json:"upper"`
+	Count          int     `json:"count"`
+	MeanConfidence float64 `json:"mean_confidence"`
+	HitRate        float64 `json:"hit_rate"`
+}
+
+// LeadTime is the time from the first evaluation of a market in the group to
+// the market's resolution.
+type LeadTime struct {
+	Markets     int     `json:"markets"`
+	MeanHours   float64 `json:"mean_hours"`
+	MedianHours float64 `json:"median_hours"`
+	MinHours    float64 `json:"min_hours"`
+	MaxHours    float64 `json:"max_hours"`
+}
+
+// Write prints a human-readable report.
+func (r Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "frames=%d markets=%d evaluations=%d scored=%d unresolved=%d after_resolution=%d\n",
+		r.Frames, r.Markets, r.Evaluations, r.Scored, r.Unresolved, r.AfterResolution)
+	fmt.Fprintf(w, "target=%s baseline_hit_rate=%.3f\n", r.Target, r.Baseline)
+
+	fmt.Fprintln(w, "\n== by rule")
+	writeGroups(w, r.ByRule)
+	fmt.Fprintln(w, "\n== by status")
+	writeGroups(w, r.ByStatus)
+}
+
+func writeGroups(w io.Writer, groups []Group) {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "(none)")
+		return
+	}
+	fmt.Fprintf(w, "%-28s %-28s %6s %8s %6s %6s %6s %8s %10s\n",
+		"intent", "key", "n", "hit_rate", "lift", "conf", "brier", "markets", "lead_h_med")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%-28s %-28s %6d %8.3f %6.2f %6.3f %6.3f %8d %10.1f\n",
+			g.IntentID, g.Key, g.Evaluations, g.HitRate, g.Lift, g.MeanConfidence, g.Brier, g.Markets, g.LeadTime.MedianHours)
+		for _, b := range g.Calibration {
+			fmt.Fprintf(w, "    conf [%.1f,%.1f) n=%d mean_conf=%.3f hit_rate=%.3f\n",
+				b.Lower, b.Upper, b.Count, b.MeanConfidence, b.HitRate)
+		}
+	}
+}
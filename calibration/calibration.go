@@ -0,0 +1,295 @@
+// Package calibration measures how well market prices (MarketPoint.MidPrice)
+// forecast resolutions: reliability diagram data, Brier score, log loss and
+// sharpness at fixed horizons before resolution, per venue, category and
+// liquidity bucket.
+package calibration
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/backtest"
+	"woodpecker/pipeline"
+)
+
+// Defaults used when Config leaves a field unset.
+var (
+	DefaultHorizons         = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour, 72 * time.Hour, 168 * time.Hour}
+	DefaultLiquidityBuckets = []float64{1_000, 10_000, 100_000}
+)
+
+// DefaultBins is the number of reliability bins.
+const DefaultBins = 10
+
+// Dimensions a report is broken down by.
+const (
+	DimensionAll       = "all"
+	DimensionVenue     = "venue"
+	DimensionCategory  = "category"
+	DimensionLiquidity = "liquidity"
+)
+
+var dimensionOrder = map[string]int{
+	DimensionAll:       0,
+	DimensionVenue:     1,
+	DimensionCategory:  2,
+	DimensionLiquidity: 3,
+}
+
+// Config is one calibration run.
+type Config struct {
+	// Horizons are how long before resolution prices are sampled.
+	Horizons []time.Duration
+
+	// Tolerance is how much older than resolution − horizon a sample may be;
+	// 0 means a quarter of the horizon.
+	Tolerance time.Duration
+
+	Bins int
+
+	// LiquidityBuckets are ascending upper bounds, in dollars.
+	LiquidityBuckets []float64
+
+	// Categories maps event IDs to categories; unmapped events fall back to
+	// DefaultCategory.
+	Categories map[string]string
+}
+
+// DefaultCategory is the Kalshi series of the event (the event ticker before
+// the first '-'), the first tag of a Polymarket event, or "uncategorized".
+func DefaultCategory(venue pipeline.Venue, es polymarket.EventSnapshot) string {
+	switch {
+	case venue == pipeline.VenueKalshi && es.EventID != "":
+		series, _, _ := strings.Cut(es.EventID, "-")
+		return series
+	case venue == pipeline.VenuePolymarket && len(es.Tags) > 0:
+		return es.Tags[0]
+	}
+	return "uncategorized"
+}
+
+// sample is a market price observed before resolution.
+type sample struct {
+	at       time.Time
+	venue    pipeline.Venue
+	point    polymarket.MarketPoint
+	category string
+}
+
+// Run samples every resolved market at each horizon and scores the sampled
+// prices against the outcome. Markets without a price (mid 0) are ignored.
+func Run(cfg Config, frames []backtest.Frame, outcomes backtest.Outcomes) Report {
+	horizons := cfg.Horizons
+	if len(horizons) == 0 {
+		horizons = DefaultHorizons
+	}
+	bins := cfg.Bins
+	if bins <= 0 {
+		bins = DefaultBins
+	}
+	buckets := cfg.LiquidityBuckets
+	if buckets == nil {
+		buckets = DefaultLiquidityBuckets
+	}
+
+	// 1️⃣ Price series per resolved market
+	series := map[string][]sample{}
+	for _, f := range frames {
+		for _, es := range f.Snapshot.Events {
+			category, ok := cfg.Categories[es.EventID]
+			if !ok {
+				category = DefaultCategory(f.Venue, es)
+			}
+			for _, mp := range es.Markets {
+				key := pipeline.HistoryKey(f.Venue, mp.MarketID)
+				if _, resolved := outcomes[key]; !resolved || mp.MidPrice <= 0 {
+					continue
+				}
+				series[key] = append(series[key], sample{
+					at:       f.Snapshot.Timestamp,
+					venue:    f.Venue,
+					point:    mp,
+					category: category,
+				})
+			}
+		}
+	}
+
+	// 2️⃣ Sample each horizon and accumulate per dimension
+	rep := Report{Markets: len(series)}
+	acc := map[string]*accumulator{}
+	add := func(dim, value string, h time.Duration, p float64, yes bool) {
+		k := fmt.Sprintf("%s|%s|%d", dim, value, h)
+		a, ok := acc[k]
+		if !ok {
+			a = &accumulator{dimension: dim, value: value, horizon: h, bins: make([]binAcc, bins)}
+			acc[k] = a
+		}
+		a.add(p, yes)
+	}
+
+	for key, points := range series {
+		o := outcomes[key]
+		if o.ResolvedAt.IsZero() {
+			continue
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].at.Before(points[j].at) })
+
+		for _, h := range horizons {
+			tolerance := cfg.Tolerance
+			if tolerance <= 0 {
+				tolerance = h / 4
+			}
+			s, ok := sampleAt(points, o.ResolvedAt.Add(-h), tolerance)
+			if !ok {
+				continue
+			}
+			rep.Observations++
+
+			p := s.point.MidPrice
+			add(DimensionAll, DimensionAll, h, p, o.Yes)
+			add(DimensionVenue, string(s.venue), h, p, o.Yes)
+			add(DimensionCategory, s.category, h, p, o.Yes)
+			add(DimensionLiquidity, LiquidityBucket(s.point.Liquidity, buckets), h, p, o.Yes)
+		}
+	}
+
+	// 3️⃣ Metrics
+	for _, a := range acc {
+		rep.Groups = append(rep.Groups, a.group())
+	}
+	sort.Slice(rep.Groups, func(i, j int) bool {
+		a, b := rep.Groups[i], rep.Groups[j]
+		if a.HorizonHours != b.HorizonHours {
+			return a.HorizonHours < b.HorizonHours
+		}
+		if a.Dimension != b.Dimension {
+			return dimensionOrder[a.Dimension] < dimensionOrder[b.Dimension]
+		}
+		return a.Value < b.Value
+	})
+	return rep
+}
+
+// sampleAt returns the last point at or before t, if it is within tolerance.
+func sampleAt(points []sample, t time.Time, tolerance time.Duration) (sample, bool) {
+	i := sort.Search(len(points), func(i int) bool { return points[i].at.After(t) })
+	if i == 0 {
+		return sample{}, false
+	}
+	s := points[i-1]
+	if t.Sub(s.at) > tolerance {
+		return sample{}, false
+	}
+	return s, true
+}
+
+// LiquidityBucket labels liquidity with the bucket bounds it falls in,
+// e.g. "<1k", "1k-10k", ">=100k".
+func LiquidityBucket(liquidity float64, bounds []float64) string {
+	if len(bounds) == 0 {
+		return "all"
+	}
+	for i, b := range bounds {
+		if liquidity < b {
+			if i == 0 {
+				return "<" + formatAmount(b)
+			}
+			return formatAmount(bounds[i-1]) + "-" + formatAmount(b)
+		}
+	}
+	return ">=" + formatAmount(bounds[len(bounds)-1])
+}
+
+func formatAmount(v float64) string {
+	switch {
+	case v >= 1e6 && math.Mod(v, 1e6) == 0:
+		return fmt.Sprintf("%gM", v/1e6)
+	case v >= 1e3 && math.Mod(v, 1e3) == 0:
+		return fmt.Sprintf("%gk", v/1e3)
+	default:
+		return fmt.Sprintf("%g", v)
+	}
+}
+
+// probEpsilon bounds predictions away from 0 and 1 for log loss.
+const probEpsilon = 1e-6
+
+type binAcc struct {
+	n       int
+	sumPred float64
+	yes     int
+}
+
+type accumulator struct {
+	dimension, value string
+	horizon          time.Duration
+
+	n        int
+	yes      int
+	sumPred  float64
+	sumPred2 float64
+	sumBrier float64
+	sumLog   float64
+	bins     []binAcc
+}
+
+func (a *accumulator) add(p float64, yes bool) {
+	y := 0.0
+	if yes {
+		y = 1
+		a.yes++
+	}
+	a.n++
+	a.sumPred += p
+	a.sumPred2 += p * p
+	a.sumBrier += (p - y) * (p - y)
+
+	q := math.Min(math.Max(p, probEpsilon), 1-probEpsilon)
+	a.sumLog -= y*math.Log(q) + (1-y)*math.Log(1-q)
+
+	b := int(p * float64(len(a.bins)))
+	if b >= len(a.bins) {
+		b = len(a.bins) - 1
+	}
+	a.bins[b].n++
+	a.bins[b].sumPred += p
+	if yes {
+		a.bins[b].yes++
+	}
+}
+
+func (a *accumulator) group() Group {
+	n := float64(a.n)
+	mean := a.sumPred / n
+	g := Group{
+		Dimension:     a.dimension,
+		Value:         a.value,
+		HorizonHours:  a.horizon.Hours(),
+		Count:         a.n,
+		BaseRate:      float64(a.yes) / n,
+		MeanPredicted: mean,
+		Brier:         a.sumBrier / n,
+		LogLoss:       a.sumLog / n,
+		Sharpness:     math.Max(a.sumPred2/n-mean*mean, 0),
+	}
+
+	width := 1 / float64(len(a.bins))
+	for i, b := range a.bins {
+		if b.n == 0 {
+			continue
+		}
+		g.Reliability = append(g.Reliability, Bin{
+			Lower:         float64(i) * width,
+			Upper:         float64(i+1) * width,
+			Count:         b.n,
+			MeanPredicted: b.sumPred / float64(b.n),
+			Realized:      float64(b.yes) / float64(b.n),
+		})
+	}
+	return g
+}
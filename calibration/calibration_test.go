@@ -0,0 +1,159 @@
+package calibration
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+	"time"
+
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/backtest"
+	"woodpecker/pipeline"
+)
+
+var resolvedAt = time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+
+// frame is a Kalshi snapshot of event KXTEST-26JAN10 taken before resolution.
+func frame(before time.Duration, points ...polymarket.MarketPoint) backtest.Frame {
+	return backtest.Frame{
+		Venue: pipeline.VenueKalshi,
+		Snapshot: polymarket.Snapshot{
+			Timestamp: resolvedAt.Add(-before),
+			Events:    []polymarket.EventSnapshot{{EventID: "KXTEST-26JAN10", Markets: points}},
+		},
+	}
+}
+
+func point(id string, mid, liquidity float64) polymarket.MarketPoint {
+	return polymarket.MarketPoint{MarketID: id, MidPrice: mid, Liquidity: liquidity}
+}
+
+func fixture() ([]backtest.Frame, backtest.Outcomes) {
+	frames := []backtest.Frame{
+		frame(25*time.Hour, point("A", 0.8, 500), point("B", 0.3, 50_000)),
+		frame(2*time.Hour, point("A", 0.9, 500), point("B", 0.2, 50_000), point("C", 0.5, 0)),
+	}
+	outcomes := backtest.Outcomes{
+		"kalshi:A": {Venue: pipeline.VenueKalshi, MarketID: "A", Yes: true, ResolvedAt: resolvedAt},
+		"kalshi:B": {Venue: pipeline.VenueKalshi, MarketID: "B", Yes: false, ResolvedAt: resolvedAt},
+	}
+	return frames, outcomes
+}
+
+func find(t *testing.T, rep Report, dim, value string, hours float64) Group {
+	t.Helper()
+	for _, g := range rep.Groups {
+		if g.Dimension == dim && g.Value == value && g.HorizonHours == hours {
+			return g
+		}
+	}
+	t.Fatalf("no group %s=%s at %vh in %+v", dim, value, hours, rep.Groups)
+	return Group{}
+}
+
+func TestRun_MetricsPerHorizon(t *testing.T) {
+	frames, outcomes := fixture()
+	rep := Run(Config{Horizons: []time.Duration{time.Hour, 24 * time.Hour}}, frames, outcomes)
+
+	// C is unresolved. The 2h-old snapshot is too stale for the 1h horizon
+	// (default tolerance 15m), so each market is sampled once, at 24h.
+	if rep.Markets != 2 || rep.Observations != 2 {
+		t.Fatalf("expected 2 resolved markets sampled once, got %+v", rep)
+	}
+	for _, g := range rep.Groups {
+		if g.HorizonHours == 1 {
+			t.Fatalf("2h-old snapshot must be too stale for the 1h horizon: %+v", g)
+		}
+	}
+
+	all := find(t, rep, DimensionAll, DimensionAll, 24)
+	// 24h samples the 25h snapshot: A 0.8 → YES, B 0.3 → NO.
+	wantBrier := (0.2*0.2 + 0.3*0.3) / 2
+	if all.Count != 2 || math.Abs(all.Brier-wantBrier) > 1e-9 || all.BaseRate != 0.5 {
+		t.Fatalf("unexpected metrics %+v", all)
+	}
+	wantLogLoss := -(math.Log(0.8) + math.Log(0.7)) / 2
+	if math.Abs(all.LogLoss-wantLogLoss) > 1e-9 {
+		t.Fatalf("expected log loss %v, got %v", wantLogLoss, all.LogLoss)
+	}
+	if math.Abs(all.Sharpness-0.0625) > 1e-9 {
+		t.Fatalf("expected sharpness 0.0625, got %v", all.Sharpness)
+	}
+	if len(all.Reliability) != 2 || all.Reliability[0].Realized != 0 || all.Reliability[1].Realized != 1 {
+		t.Fatalf("unexpected reliability bins %+v", all.Reliability)
+	}
+
+	find(t, rep, DimensionVenue, "kalshi", 24)
+	find(t, rep, DimensionCategory, "KXTEST", 24)
+	if g := find(t, rep, DimensionLiquidity, "<1k", 24); g.Count != 1 {
+		t.Fatalf("expected A alone in <1k, got %+v", g)
+	}
+	find(t, rep, DimensionLiquidity, "10k-100k", 24)
+}
+
+func TestRun_WiderToleranceAndCategories(t *testing.T) {
+	frames, outcomes := fixture()
+	rep := Run(Config{
+		Horizons:   []time.Duration{time.Hour},
+		Tolerance:  2 * time.Hour,
+		Categories: map[string]string{"KXTEST-26JAN10": "crypto"},
+	}, frames, outcomes)
+
+	g := find(t, rep, DimensionCategory, "crypto", 1)
+	if g.Count != 2 || math.Abs(g.MeanPredicted-0.55) > 1e-9 {
+		t.Fatalf("expected the 2h snapshot to be sampled, got %+v", g)
+	}
+}
+
+func TestDefaultCategory(t *testing.T) {
+	cases := []struct {
+		venue pipeline.Venue
+		es    polymarket.EventSnapshot
+		want  string
+	}{
+		{pipeline.VenueKalshi, polymarket.EventSnapshot{EventID: "KXBTCD-25DEC31"}, "KXBTCD"},
+		{pipeline.VenuePolymarket, polymarket.EventSnapshot{EventID: "123", Tags: []string{"crypto", "bitcoin"}}, "crypto"},
+		{pipeline.VenuePolymarket, polymarket.EventSnapshot{EventID: "123"}, "uncategorized"},
+	}
+	for _, c := range cases {
+		if got := DefaultCategory(c.venue, c.es); got != c.want {
+			t.Fatalf("DefaultCategory(%s, %s) = %s, want %s", c.venue, c.es.EventID, got, c.want)
+		}
+	}
+}
+
+func TestLiquidityBucket(t *testing.T) {
+	bounds := []float64{1_000, 10_000, 1_000_000}
+	cases := map[float64]string{0: "<1k", 999: "<1k", 1_000: "1k-10k", 50_000: "10k-1M", 2e6: ">=1M"}
+	for v, want := range cases {
+		if got := LiquidityBucket(v, bounds); got != want {
+			t.Fatalf("LiquidityBucket(%v) = %s, want %s", v, got, want)
+		}
+	}
+}
+
+func TestReport_CSV(t *testing.T) {
+	frames, outcomes := fixture()
+	rep := Run(Config{Horizons: []time.Duration{24 * time.Hour}}, frames, outcomes)
+
+	var buf bytes.Buffer
+	if err := rep.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(rep.Groups)+1 || rows[0][0] != "dimension" || rows[1][0] != DimensionAll {
+		t.Fatalf("unexpected CSV %v", rows)
+	}
+
+	buf.Reset()
+	if err := rep.WriteReliabilityCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if rows, err = csv.NewReader(&buf).ReadAll(); err != nil || len(rows) < 2 {
+		t.Fatalf("unexpected reliability CSV %v (%v)", rows, err)
+	}
+}
@@ -0,0 +1,87 @@
+package calibration
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// Report is the result of a calibration run.
+type Report struct {
+	Markets      int     `json:"markets"`
+	Observations int     `json:"observations"`
+	Groups       []Group `json:"groups"`
+}
+
+// Group scores the prices sampled at one horizon for one dimension value
+// (e.g. venue=kalshi at 24h).
+type Group struct {
+	Dimension    string  `json:"dimension"`
+	Value        string  `json:"value"`
+	HorizonHours float64 `json:"horizon_hours"`
+
+	Count         int     `json:"count"`
+	BaseRate      float64 `json:"base_rate"` // realized YES frequency
+	MeanPredicted float64 `json:"mean_predicted"`
+
+	Brier   float64 `json:"brier"`
+	LogLoss float64 `json:"log_loss"`
+	// Sharpness is the variance of the predictions: 0 when the market always
+	// says the same thing, 0.25 when it only says 0 or 1.
+	Sharpness float64 `json:"sharpness"`
+
+	Reliability []Bin `json:"reliability"`
+}
+
+// Bin is one point of a reliability diagram: predicted vs realized frequency.
+type Bin struct {
+	Lower         float64 `json:"lower"`
+	Upper         float64 `json:"upper"`
+	Count         int     `json:"count"`
+	MeanPredicted float64 `json:"mean_predicted"`
+	Realized      float64 `json:"realized"`
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one row of metrics per group.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"dimension", "value", "horizon_hours", "count", "base_rate", "mean_predicted", "brier", "log_loss", "sharpness"})
+	for _, g := range r.Groups {
+		_ = cw.Write([]string{
+			g.Dimension, g.Value, formatFloat(g.HorizonHours), strconv.Itoa(g.Count),
+			formatFloat(g.BaseRate), formatFloat(g.MeanPredicted),
+			formatFloat(g.Brier), formatFloat(g.LogLoss), formatFloat(g.Sharpness),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteReliabilityCSV writes one row per reliability bin of every group.
+func (r Report) WriteReliabilityCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"dimension", "value", "horizon_hours", "bin_lower", "bin_upper", "count", "mean_predicted", "realized"})
+	for _, g := range r.Groups {
+		for _, b := range g.Reliability {
+			_ = cw.Write([]string{
+				g.Dimension, g.Value, formatFloat(g.HorizonHours),
+				formatFloat(b.Lower), formatFloat(b.Upper), strconv.Itoa(b.Count),
+				formatFloat(b.MeanPredicted), formatFloat(b.Realized),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
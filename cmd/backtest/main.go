@@ -0,0 +1,133 @@
+// Command backtest replays historical snapshots through features, signals and
+// the rulesets and scores the rules against how the markets resolved.
+//
+//	backtest -snapshots adapters/Kalshi/snapshots
+//
+// Snapshots are Kalshi market lists written by adapters/Kalshi or saved
+// polymarket.Snapshot JSON; -snapshots takes a comma-separated list of files
+// or directories. Markets are labeled with the resolutions.json stored next to
+// the snapshots, or with -outcomes, a JSON list of
+// {"venue", "market_id", "yes", "resolved_at"}.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"woodpecker/backtest"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+	snapshotsPath := fs.String("snapshots", "adapters/Kalshi/snapshots", "comma-separated snapshot files or directories")
+	outcomesPath := fs.String("outcomes", "", "market outcomes (JSON); default: resolutions.json next to the snapshots")
+	rulesPath := fs.String("rules", "planning/rules", "ruleset file or directory of rulesets")
+	signalMapPath := fs.String("signals", "planning/intents/signal_intent_map.json", "per-intent signal contract (empty to pass every signal)")
+	intentList := fs.String("intents", "", "comma-separated intents to evaluate (default: every intent with rules)")
+	targetName := fs.String("target", "reversal", "what counts as a hit: reversal or momentum")
+	historyLen := fs.Int("history", 0, "market points kept per market (default pipeline.DefaultHistoryLen)")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	_ = fs.Parse(args)
+
+	target, err := backtest.ParseTarget(*targetName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	// 1️⃣ Rulesets
+	rulesets, err := reasoner.LoadRulesets(*rulesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load rules: %v\n", err)
+		return 2
+	}
+	router, err := reasoner.NewRulesetRouter("backtest", rulesets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load rules: %v\n", err)
+		return 2
+	}
+
+	var signalMap intents.SignalMap
+	if *signalMapPath != "" {
+		if signalMap, err = intents.LoadSignalMap(*signalMapPath); err != nil {
+			fmt.Fprintf(os.Stderr, "load signals: %v\n", err)
+			return 2
+		}
+	}
+
+	// 2️⃣ Snapshots and outcomes
+	snapshotPaths := strings.Split(*snapshotsPath, ",")
+	frames, err := backtest.LoadFrames(snapshotPaths...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load snapshots: %v\n", err)
+		return 2
+	}
+	var outcomes backtest.Outcomes
+	if *outcomesPath != "" {
+		outcomes, err = backtest.LoadOutcomes(*outcomesPath)
+	} else {
+		outcomes, err = backtest.LoadResolutions(snapshotPaths...)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load outcomes: %v\n", err)
+		return 2
+	}
+	if len(outcomes) == 0 {
+		fmt.Fprintln(os.Stderr, "no outcomes: pass -outcomes or store resolutions.json next to the snapshots")
+		return 2
+	}
+
+	// 3️⃣ Replay
+	report, err := backtest.Run(backtest.Config{
+		Intents:    selectIntents(*intentList, rulesets),
+		Reasoner:   router,
+		Signals:    signalMap,
+		Outcomes:   outcomes,
+		Target:     target,
+		HistoryLen: *historyLen,
+	}, frames)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backtest: %v\n", err)
+		return 1
+	}
+
+	// 4️⃣ Report
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	} else {
+		report.Write(os.Stdout)
+	}
+	return 0
+}
+
+// selectIntents returns the -intents list, or every intent the rulesets define.
+func selectIntents(list string, rulesets []reasoner.Ruleset) []string {
+	if list != "" {
+		return strings.Split(list, ",")
+	}
+
+	seen := map[string]bool{}
+	var out []string
+	for _, rs := range rulesets {
+		for _, r := range rs.Rules {
+			if !seen[r.Intent] {
+				seen[r.Intent] = true
+				out = append(out, r.Intent)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,104 @@
+// Command calibration scores market prices as forecasts of their resolution.
+//
+//	calibration -snapshots adapters/Kalshi/snapshots -format csv
+//
+// Prices are sampled at each -horizons before resolution and reported per
+// venue, category and liquidity bucket as JSON (default), a metrics CSV
+// (-format csv) or reliability diagram bins (-format reliability).
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"woodpecker/backtest"
+	"woodpecker/calibration"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("calibration", flag.ExitOnError)
+	snapshotsPath := fs.String("snapshots", "adapters/Kalshi/snapshots", "comma-separated snapshot files or directories")
+	outcomesPath := fs.String("outcomes", "", "market outcomes (JSON); default: resolutions.json next to the snapshots")
+	categoriesPath := fs.String("categories", "", "optional JSON object mapping event IDs to categories")
+	horizonList := fs.String("horizons", "1h,6h,24h,72h,168h", "comma-separated horizons before resolution")
+	tolerance := fs.Duration("tolerance", 0, "how stale a sample may be (default: a quarter of the horizon)")
+	bins := fs.Int("bins", calibration.DefaultBins, "reliability bins")
+	liquidityList := fs.String("liquidity", "1000,10000,100000", "comma-separated liquidity bucket bounds in dollars")
+	format := fs.String("format", "json", "output: json, csv or reliability")
+	_ = fs.Parse(args)
+
+	// 1️⃣ Config
+	cfg := calibration.Config{Tolerance: *tolerance, Bins: *bins}
+	for _, s := range strings.Split(*horizonList, ",") {
+		h, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil || h <= 0 {
+			fmt.Fprintf(os.Stderr, "bad horizon %q\n", s)
+			return 2
+		}
+		cfg.Horizons = append(cfg.Horizons, h)
+	}
+	for _, s := range strings.Split(*liquidityList, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bad liquidity bound %q\n", s)
+			return 2
+		}
+		cfg.LiquidityBuckets = append(cfg.LiquidityBuckets, v)
+	}
+	if *categoriesPath != "" {
+		data, err := os.ReadFile(*categoriesPath)
+		if err == nil {
+			err = json.Unmarshal(data, &cfg.Categories)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "load categories: %v\n", err)
+			return 2
+		}
+	}
+
+	// 2️⃣ Snapshots and outcomes
+	snapshotPaths := strings.Split(*snapshotsPath, ",")
+	frames, err := backtest.LoadFrames(snapshotPaths...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load snapshots: %v\n", err)
+		return 2
+	}
+	var outcomes backtest.Outcomes
+	if *outcomesPath != "" {
+		outcomes, err = backtest.LoadOutcomes(*outcomesPath)
+	} else {
+		outcomes, err = backtest.LoadResolutions(snapshotPaths...)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load outcomes: %v\n", err)
+		return 2
+	}
+
+	// 3️⃣ Report
+	report := calibration.Run(cfg, frames, outcomes)
+	switch *format {
+	case "json":
+		err = report.WriteJSON(os.Stdout)
+	case "csv":
+		err = report.WriteCSV(os.Stdout)
+	case "reliability":
+		err = report.WriteReliabilityCSV(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
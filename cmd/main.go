@@ -1,11 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"sort"
+	"path/filepath"
+	"strings"
 	"time"
 
 	polymarket "woodpecker/adapters/Polymarket/gamma"
@@ -17,8 +19,15 @@ func main() {
 	maxMarkets := flag.Int("maxMarkets", 50, "máximo de markets a imprimir/procesar (total)")
 	perEvent := flag.Int("perEvent", 10, "máximo de markets por evento a imprimir/procesar")
 	verbose := flag.Bool("v", false, "modo verbose")
+	peerStrategies := flag.String("peers", strings.Join(polymarket.DefaultPeerStrategies, ","), "grupos de peers para la dispersión, en orden de preferencia (siblings, event, end_date, tags)")
+	outDir := flag.String("out", "", "directorio donde guardar el snapshot y actualizar resolutions.json (vacío = no guardar)")
 	flag.Parse()
 
+	strategies, err := polymarket.ParsePeerStrategies(*peerStrategies)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	client := polymarket.NewClient()
 
 	start := time.Now()
@@ -68,20 +77,19 @@ func main() {
 		snapshot.Stats.AvgSpread,
 		snapshot.Stats.ExtremeMarkets,
 	)
-
-	// Recolectamos todos los MarketPoint para poder calcular peers (opcional)
-	all := make([]polymarket.MarketPoint, 0, snapshot.Stats.TotalMarkets)
-	for _, es := range snapshot.Events {
-		all = append(all, es.Markets...)
+	if q := snapshot.Quality; q != nil {
+		fmt.Printf("Calidad de datos: score=%.3f markets_con_problemas=%d motivos=%s\n",
+			q.Score, q.Flagged, orNone(strings.Join(q.Reasons(), ",")))
 	}
 
-	// Orden determinístico para debug
-	sort.Slice(all, func(i, j int) bool {
-		if all[i].MarketID == all[j].MarketID {
-			return all[i].UpdatedAt.Before(all[j].UpdatedAt)
+	if *outDir != "" {
+		if err := persist(client, *outDir, snapshot); err != nil {
+			log.Fatalf("persist snapshot failed: %v", err)
 		}
-		return all[i].MarketID < all[j].MarketID
-	})
+	}
+
+	// Peers semánticos: mismo evento, outcomes hermanos, mismo tema y fecha de cierre
+	peerIndex := polymarket.NewPeerIndex(snapshot, polymarket.PeerConfig{Strategies: strategies})
 
 	processed := 0
 	perEventProcessed := map[string]int{}
@@ -106,27 +114,26 @@ func main() {
 				prev = &pp
 			}
 
-			// peers (muy básico): 5 peers “cercanos” por liquidez, excluyendo el market actual
-			peers := pickPeers(all, mp.MarketID, 5)
+			peers := peerIndex.Groups(es.EventID, mp.MarketID)
 
 			features := polymarket.ComputeFeatures(
 				mp,
 				prev,
 				nil,   // history (en este probe no la tenemos; para volatilidad real necesitás series temporales)
-				peers, // peers cross-market
+				peers, // grupos de peers (la dispersión usa el primero no vacío)
 			)
 
 			signals := polymarket.BuildSignals(features)
 
-			// Diagnóstico de datos crudos que suelen romper features:
-			// MidPrice=0 puede pasar si bid/ask vienen 0 o vacíos.
+			// Diagnóstico de datos crudos que suelen romper features
+			// (sin cotización, book cruzado, sin liquidez, fechas inválidas...).
 			dataWarn := ""
-			if mp.MidPrice <= 0 {
-				dataWarn = " [WARN: midPrice=0 (bid/ask missing?)]"
+			if mp.Quality != nil && len(mp.Quality.Issues) > 0 {
+				dataWarn = fmt.Sprintf(" [WARN: calidad=%.2f %s]", mp.Quality.Score, strings.Join(mp.Quality.Issues, ","))
 			}
 
 			fmt.Printf(
-				"event=%s market=%s p=%.4f logOdds=%.4f mom=%.4f vol=%.4f conf=%.4f disp=%.4f bid=%.4f ask=%.4f spread=%.4f liq=%.2f vol=%.2f%s\n",
+				"event=%s market=%s p=%.4f logOdds=%.4f mom=%.4f vol=%.4f conf=%.4f disp=%.4f peers=%s bid=%.4f ask=%.4f spread=%.4f liq=%.2f vol=%.2f%s\n",
 				es.EventID,
 				mp.MarketID,
 				features.PEvent,
@@ -135,6 +142,7 @@ func main() {
 				features.BeliefVolatility,
 				features.ImpliedConfidence,
 				features.Dispersion,
+				orNone(features.PeerGroup),
 				mp.BestBid,
 				mp.BestAsk,
 				mp.Spread,
@@ -167,6 +175,71 @@ func main() {
 	}
 }
 
+// persist guarda el snapshot en dir y etiqueta con su resolución los markets
+// de snapshots anteriores en dir que ya cerraron (dir/resolutions.json). Las
+// resoluciones se leen en bloque de los markets cerrados desde la última
+// revisión (o desde el snapshot más antiguo), sin pedir cada market abierto por
+// separado.
+func persist(client *polymarket.Client, dir string, snapshot polymarket.Snapshot) error {
+	path, err := polymarket.SaveSnapshot(dir, snapshot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📸 Snapshot guardado: %s\n", path)
+
+	resPath := filepath.Join(dir, polymarket.ResolutionsFile)
+	store, err := polymarket.LoadResolutions(resPath)
+	if err != nil {
+		return err
+	}
+
+	// Markets vistos en snapshots guardados que todavía no tienen resolución
+	files, err := filepath.Glob(filepath.Join(dir, "snapshot_*.json"))
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	var pending []string
+	var since time.Time
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		var s polymarket.Snapshot
+		if err := json.Unmarshal(data, &s); err != nil || s.Source != polymarket.SnapshotSource {
+			continue // snapshots de otra venue
+		}
+		if since.IsZero() || s.Timestamp.Before(since) {
+			since = s.Timestamp
+		}
+		for _, es := range s.Events {
+			for _, mp := range es.Markets {
+				if !store.Has(s.Source, mp.MarketID) && !seen[mp.MarketID] {
+					seen[mp.MarketID] = true
+					pending = append(pending, mp.MarketID)
+				}
+			}
+		}
+	}
+
+	// Los markets cerrados ya revisados en una ejecución anterior no se vuelven a leer
+	if !store.ScannedUntil.IsZero() {
+		since = store.ScannedUntil
+	}
+	resolutions, scanned, err := client.FetchResolutions(pending, since, 0)
+	if err != nil {
+		return err
+	}
+	if scanned.After(store.ScannedUntil) {
+		store.ScannedUntil = scanned
+	}
+	if added := store.Add(resolutions...); added > 0 {
+		fmt.Printf("🏁 %d resoluciones nuevas (de %d markets abiertos)\n", added, len(pending))
+	}
+	return store.Save(resPath)
+}
+
 func boolPtr(b *bool) bool {
 	if b == nil {
 		return false
@@ -174,27 +247,9 @@ func boolPtr(b *bool) bool {
 	return *b
 }
 
-// pickPeers elige hasta n peers con mayor liquidez, excluyendo marketID.
-// Esto es SOLO para debug de crossMarketDispersion; en producción vas a definir peers por “mismo evento/tema”.
-func pickPeers(all []polymarket.MarketPoint, excludeMarketID string, n int) []polymarket.MarketPoint {
-	type row struct {
-		mp  polymarket.MarketPoint
-		liq float64
-	}
-	rows := make([]row, 0, len(all))
-	for _, m := range all {
-		if m.MarketID == excludeMarketID {
-			continue
-		}
-		rows = append(rows, row{mp: m, liq: m.Liquidity})
-	}
-	sort.Slice(rows, func(i, j int) bool { return rows[i].liq > rows[j].liq })
-	if n > len(rows) {
-		n = len(rows)
-	}
-	out := make([]polymarket.MarketPoint, 0, n)
-	for i := 0; i < n; i++ {
-		out = append(out, rows[i].mp)
+func orNone(s string) string {
+	if s == "" {
+		return "none"
 	}
-	return out
+	return s
 }
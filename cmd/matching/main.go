@@ -0,0 +1,203 @@
+// Command matching maintains the cross-venue mapping file.
+//
+//	matching propose -snapshots adapters/Kalshi/snapshots,snapshots/polymarket
+//
+// fuzzy-matches the latest Polymarket and Kalshi markets found in the
+// snapshots and adds the pairs as pending links (-related also proposes
+// related markets within each venue).
+//
+//	matching list [-status pending]
+//	matching approve [-note "..."] <id>...
+//	matching reject [-note "..."] <id>...
+//
+// is the review workflow: only approved links are used downstream, and
+// rejected ones are never proposed again. Curated links are added to the
+// mapping file by hand and are approved equivalences by default:
+//
+//	links:
+//	- a: {venue: polymarket, market_id: "<gamma market id>"}
+//	  b: {venue: kalshi, market_id: "<kalshi market ticker>"}
+//	  note: why both resolve the same way
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"woodpecker/backtest"
+	"woodpecker/matching"
+	"woodpecker/pipeline"
+)
+
+const defaultMapping = "matching/mappings.yaml"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "propose":
+		os.Exit(runPropose(os.Args[2:]))
+	case "list":
+		os.Exit(runList(os.Args[2:]))
+	case "approve":
+		os.Exit(runReview(os.Args[2:], matching.StatusApproved))
+	case "reject":
+		os.Exit(runReview(os.Args[2:], matching.StatusRejected))
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: matching <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  propose  fuzzy-match snapshot markets into pending links")
+	fmt.Fprintln(os.Stderr, "  list     print links for review")
+	fmt.Fprintln(os.Stderr, "  approve  approve links by ID")
+	fmt.Fprintln(os.Stderr, "  reject   reject links by ID")
+}
+
+func runPropose(args []string) int {
+	fs := flag.NewFlagSet("propose", flag.ExitOnError)
+	snapshotsPath := fs.String("snapshots", "adapters/Kalshi/snapshots", "comma-separated snapshot files or directories (both venues)")
+	mappingPath := fs.String("mapping", defaultMapping, "mapping file to update")
+	minConfidence := fs.Float64("min", matching.DefaultMinConfidence, "lowest confidence proposed")
+	window := fs.Duration("window", matching.DefaultDateWindow, "largest end date difference of a match")
+	related := fs.Bool("related", false, "also propose related markets within each venue")
+	maxRelated := fs.Int("max-related", 5, "related markets proposed per market")
+	_ = fs.Parse(args)
+
+	m, err := matching.LoadMapping(*mappingPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load mapping: %v\n", err)
+		return 2
+	}
+
+	// 1️⃣ Latest view of every market
+	frames, err := backtest.LoadFrames(strings.Split(*snapshotsPath, ",")...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load snapshots: %v\n", err)
+		return 2
+	}
+	byVenue := map[pipeline.Venue][]matching.Candidate{}
+	index := map[string]int{}
+	for _, f := range frames {
+		for _, c := range matching.Candidates(f.Venue, f.Snapshot) {
+			if c.Title == "" {
+				continue
+			}
+			if i, ok := index[c.Key()]; ok {
+				byVenue[c.Venue][i] = c
+				continue
+			}
+			index[c.Key()] = len(byVenue[c.Venue])
+			byVenue[c.Venue] = append(byVenue[c.Venue], c)
+		}
+	}
+	poly, kal := byVenue[pipeline.VenuePolymarket], byVenue[pipeline.VenueKalshi]
+	fmt.Printf("📥 %d Polymarket y %d Kalshi markets con título\n", len(poly), len(kal))
+
+	// 2️⃣ Proposals
+	cfg := matching.Config{MinConfidence: *minConfidence, DateWindow: *window}
+	now := time.Now()
+
+	pairs := matching.Match(cfg, poly, kal)
+	added := m.Propose(pairs, matching.RelationEquivalent, now)
+	fmt.Printf("🔗 %d equivalencias propuestas (%d nuevas)\n", len(pairs), added)
+
+	if *related {
+		relatedAdded := 0
+		for _, cands := range byVenue {
+			for _, c := range cands {
+				rel := matching.Related(cfg, c, cands)
+				if len(rel) > *maxRelated {
+					rel = rel[:*maxRelated]
+				}
+				relatedAdded += m.Propose(rel, matching.RelationRelated, now)
+			}
+		}
+		fmt.Printf("🧩 %d relaciones nuevas\n", relatedAdded)
+	}
+
+	// 3️⃣ Save for review
+	if err := m.Save(*mappingPath); err != nil {
+		fmt.Fprintf(os.Stderr, "save mapping: %v\n", err)
+		return 1
+	}
+	fmt.Printf("💾 %s (revisar con: matching list)\n", *mappingPath)
+	return 0
+}
+
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	mappingPath := fs.String("mapping", defaultMapping, "mapping file")
+	status := fs.String("status", string(matching.StatusPending), "status to list (empty for all)")
+	_ = fs.Parse(args)
+
+	m, err := matching.LoadMapping(*mappingPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load mapping: %v\n", err)
+		return 2
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tRELATION\tCONF\tA\tB")
+	for _, l := range m.Links {
+		if *status != "" && string(l.Status) != *status {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.2f\t%s\t%s\n",
+			l.ID, l.Status, l.Relation, l.Confidence,
+			describe(l.A, l.TitleA), describe(l.B, l.TitleB))
+	}
+	_ = tw.Flush()
+	return 0
+}
+
+func describe(ref pipeline.MarketRef, title string) string {
+	key := pipeline.HistoryKey(ref.Venue, ref.MarketID)
+	if title == "" {
+		return key
+	}
+	return fmt.Sprintf("%s %q", key, title)
+}
+
+func runReview(args []string, status matching.Status) int {
+	fs := flag.NewFlagSet(string(status), flag.ExitOnError)
+	mappingPath := fs.String("mapping", defaultMapping, "mapping file")
+	note := fs.String("note", "", "review note stored on the links")
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "at least one link ID is required")
+		return 2
+	}
+
+	m, err := matching.LoadMapping(*mappingPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load mapping: %v\n", err)
+		return 2
+	}
+	now := time.Now()
+	for _, id := range fs.Args() {
+		if err := m.Review(id, status, *note, now); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if err := m.Save(*mappingPath); err != nil {
+		fmt.Fprintf(os.Stderr, "save mapping: %v\n", err)
+		return 1
+	}
+	fmt.Printf("✅ %d links %s\n", fs.NArg(), status)
+	return 0
+}
@@ -0,0 +1,162 @@
+// Command rules is a toolbox for rule authors.
+//
+//	rules test -rules planning/rules -fixtures planning/rules/fixtures
+//
+// runs YAML fixtures through the rule engine and reports every mismatch.
+//
+//	rules report -rules planning/rules [-intents planning/intents/intents.json] [-fixtures planning/rules/fixtures]
+//
+// prints registered intents without rules, dead, shadowed and overlapping
+// rules plus unreachable statuses and, when fixtures are given, how often
+// each rule matched while replaying them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "test":
+		os.Exit(runTest(os.Args[2:]))
+	case "report":
+		os.Exit(runReport(os.Args[2:]))
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: rules <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  test    run YAML fixtures against a ruleset")
+	fmt.Fprintln(os.Stderr, "  report  static coverage analysis and fixture match counts")
+}
+
+func runTest(args []string) int {
+	fs := flag.NewFlagSet("test", flag.ExitOnError)
+	rulesPath := fs.String("rules", "planning/rules", "ruleset file or directory of rulesets")
+	fixturesPath := fs.String("fixtures", "planning/rules/fixtures", "fixture file or directory of fixture files")
+	signalsPath := fs.String("signals", "planning/intents/signal_intent_map.json", "signal contract the rules must respect (empty to skip)")
+	_ = fs.Parse(args)
+
+	rulesets, err := reasoner.LoadRulesets(*rulesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load rules: %v\n", err)
+		return 2
+	}
+	if err := checkSignals(*signalsPath, rulesets); err != nil {
+		fmt.Fprintf(os.Stderr, "signal contract: %v\n", err)
+		return 1
+	}
+
+	cases, err := reasoner.LoadFixtures(*fixturesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load fixtures: %v\n", err)
+		return 2
+	}
+
+	report, err := reasoner.RunFixtures(rulesets, cases)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "run fixtures: %v\n", err)
+		return 2
+	}
+
+	report.Write(os.Stdout)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	rulesPath := fs.String("rules", "planning/rules", "ruleset file or directory of rulesets")
+	intentsPath := fs.String("intents", "planning/intents/intents.json", "intent registry checked for intents without rules (empty to skip)")
+	fixturesPath := fs.String("fixtures", "", "optional fixture file or directory replayed for match counts")
+	_ = fs.Parse(args)
+
+	rulesets, err := reasoner.LoadRulesets(*rulesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load rules: %v\n", err)
+		return 2
+	}
+
+	if *intentsPath != "" {
+		registry, err := intents.LoadRegistry(*intentsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "load intents: %v\n", err)
+			return 2
+		}
+		if err := reasoner.ValidateIntentCoverage(registry, rulesets); err != nil {
+			fmt.Fprintf(os.Stderr, "intent coverage: %v\n", err)
+			return 1
+		}
+		if uncovered := reasoner.UncoveredIntents(registry, rulesets); len(uncovered) > 0 {
+			fmt.Printf("intents without rules: %v\n\n", uncovered)
+		}
+	}
+
+	findings := 0
+	var all []reasoner.Rule
+	for _, rs := range rulesets {
+		a := reasoner.AnalyzeRuleset(rs)
+		findings += len(a.Findings)
+		all = append(all, rs.Rules...)
+
+		fmt.Printf("== %s (version %s, strategy %s, %d rules)\n",
+			rs.RulesetID, rs.Version, a.Strategy, len(rs.Rules))
+		a.Write(os.Stdout)
+		fmt.Println()
+	}
+
+	if *fixturesPath == "" {
+		return 0
+	}
+
+	cases, err := reasoner.LoadFixtures(*fixturesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load fixtures: %v\n", err)
+		return 2
+	}
+	router, err := reasoner.NewRulesetRouter("report", rulesets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load rules: %v\n", err)
+		return 2
+	}
+	counters := reasoner.NewRuleCounters()
+	router.SetCounters(counters)
+	reasoner.RunFixturesWith(router, cases)
+
+	snap := counters.Snapshot()
+	fmt.Printf("== match counts over %d fixture cases\n", len(cases))
+	snap.Write(os.Stdout, all)
+
+	if never := snap.NeverMatched(all); len(never) > 0 {
+		fmt.Printf("\nnever matched: %v\n", never)
+	}
+	return 0
+}
+
+func checkSignals(path string, rulesets []reasoner.Ruleset) error {
+	if path == "" {
+		return nil
+	}
+	signals, err := intents.LoadSignalMap(path)
+	if err != nil {
+		return err
+	}
+	return reasoner.ValidateRuleSignals(signals, rulesets)
+}
@@ -1,35 +1,153 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"net/http"
+	"os"
 
+	"woodpecker/adapters/Kalshi/kalshi"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/matching"
+	"woodpecker/pipeline"
 	"woodpecker/planning/api"
+	"woodpecker/planning/guardrails"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/notify"
 	"woodpecker/planning/reasoner"
+	"woodpecker/planning/stream"
 )
 
 func main() {
-	// 1️⃣ Load rules
-	rules, err := reasoner.LoadRulesFromFile("planning/reasoner/rules.yaml")
+	rulesPath := flag.String("rules", "planning/rules", "ruleset file or directory of rulesets")
+	intentsPath := flag.String("intents", "planning/intents/intents.json", "intent registry")
+	signalMapPath := flag.String("signals", "planning/intents/signal_intent_map.json", "per-intent signal contract")
+	guardrailsPath := flag.String("guardrails", "planning/guardrails/policies.yaml", "guardrail policies")
+	validation := flag.String("validate", "log", "output schema validation: log, strict or off")
+	watchlistPath := flag.String("watchlist", "", "markets/intents evaluated periodically for /planning/stream (e.g. planning/stream/watchlist.yaml)")
+	notifyPath := flag.String("notify", "", "notifications for watchlist intents, requires -watchlist (e.g. planning/notify/notify.yaml)")
+	mappingPath := flag.String("mapping", "matching/mappings.yaml", "approved cross-venue links for CROSS_VENUE_DIVERGENCE (empty to disable)")
+	books := flag.Bool("books", false, "fetch order books for the liquidity/slippage features (one extra request per market)")
+	minQuality := flag.Float64("min-quality", 0, "skip markets whose data quality score is below this (0..1, 0 = evaluate all)")
+	historyLen := flag.Int("history", pipeline.DefaultHistoryLen, "market points kept per market for momentum/volatility")
+	flag.Parse()
+
+	validationMode, err := api.ParseValidationMode(*validation)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 1️⃣ Load intent registry and signal contract
+	registry, err := intents.LoadRegistry(*intentsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	signalMap, err := intents.LoadSignalMap(*signalMapPath)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := signalMap.Validate(registry); err != nil {
+		log.Fatal(err)
+	}
 
-	// 2️⃣ Initialize Rule-Based Reasoner
-	r := &reasoner.RuleBasedReasoner{
-		Version: "v1",
-		Rules:   rules,
+	// 2️⃣ Load rulesets (hot-reloaded from disk, validated against registry and signal contract)
+	counters := reasoner.NewRuleCounters()
+	r, err := reasoner.NewRulesetWatcher(*rulesPath, "v1",
+		reasoner.WithIntentCoverage(registry),
+		reasoner.WithChecks(reasoner.CheckRuleSignals(signalMap)),
+		reasoner.WithSignalWeights(signalMap),
+		reasoner.WithCounters(counters),
+	)
+	if err != nil {
+		log.Fatal(err)
 	}
+	go r.Watch(context.Background())
 
-	// 3️⃣ Wire handler
+	// 3️⃣ Guardrail policies run after the reasoner
+	policies, err := guardrails.LoadConfig(*guardrailsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	guarded := &guardrails.Reasoner{
+		Next:   r,
+		Engine: &guardrails.Engine{Config: policies},
+	}
+
+	// 4️⃣ Market pipeline (Kalshi only with KALSHI_API_KEY)
+	markets := &pipeline.Pipeline{
+		Gamma:      polymarket.NewClient(),
+		History:    pipeline.NewMemoryHistory(*historyLen),
+		Books:      *books,
+		MinQuality: *minQuality,
+	}
+	if key := os.Getenv("KALSHI_API_KEY"); key != "" {
+		markets.Kalshi = kalshi.New(key)
+	}
+	if *mappingPath != "" {
+		links, err := matching.LoadMapping(*mappingPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		markets.Counterparts = links.Index().EquivalentRefs
+	}
+
+	// 5️⃣ Evaluation loop → SSE stream (only with -watchlist)
+	var hub *stream.Hub
+	if *watchlistPath != "" {
+		watchlist, err := stream.LoadConfig(*watchlistPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := watchlist.ValidateIntents(registry, r.Rulesets()); err != nil {
+			log.Fatal(err)
+		}
+		hub = stream.NewHub(stream.DefaultBufferSize)
+		loop := &stream.Loop{
+			Config:   watchlist,
+			Pipeline: markets,
+			Reasoner: guarded,
+			Hub:      hub,
+			Signals:  signalMap,
+		}
+		go loop.Run(context.Background())
+	}
+
+	// 6️⃣ Notifications when trigger intents cross their level (only with -notify)
+	if *notifyPath != "" {
+		if hub == nil {
+			log.Fatal("-notify requires -watchlist")
+		}
+		notifyCfg, err := notify.LoadConfig(*notifyPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		notifier, err := notify.New(notifyCfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go notifier.Run(context.Background(), hub)
+	}
+
+	// 7️⃣ Wire handlers
 	handler := &api.PlanningHandler{
-		Reasoner: r,
+		Reasoner: guarded,
+		Intents:  registry,
+		Signals:  signalMap,
+		Markets:  markets,
+		Stream:   hub,
+
+		Validation: validationMode,
+	}
+	admin := &api.AdminHandler{
+		Rules:    r,
+		Counters: counters,
 	}
 
-	// 4️⃣ Routes
-	http.HandleFunc("/planning/intent/evaluate", handler.EvaluateIntent)
+	// 8️⃣ Routes (/v1/ + legacy)
+	router := api.NewRouter(handler, admin)
 
-	// 5️⃣ Start server
+	// 9️⃣ Start server
 	log.Println("🪵🐦 Woodpecker Planning Layer listening on :8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	log.Fatal(http.ListenAndServe(":8080", router))
 }
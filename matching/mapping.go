@@ -0,0 +1,313 @@
+package matching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"woodpecker/pipeline"
+)
+
+// Link relations.
+const (
+	// RelationEquivalent links markets that resolve on the same question.
+	RelationEquivalent = "equivalent"
+	// RelationRelated links markets about the same topic and date.
+	RelationRelated = "related"
+)
+
+// Link sources.
+const (
+	SourceCurated = "curated"
+	SourceFuzzy   = "fuzzy"
+)
+
+// Status is where a link is in review.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// Link connects two markets. Hand-written links default to approved curated
+// equivalences; fuzzy proposals start pending and only count once approved.
+// Rejected links stay in the file so the pair is never proposed again.
+type Link struct {
+	ID       string             `yaml:"id,omitempty"`
+	A        pipeline.MarketRef `yaml:"a"`
+	B        pipeline.MarketRef `yaml:"b"`
+	Relation string             `yaml:"relation,omitempty"`
+	Source   string             `yaml:"source,omitempty"`
+	Status   Status             `yaml:"status,omitempty"`
+
+	Confidence float64 `yaml:"confidence,omitempty"`
+	Score      *Score  `yaml:"score,omitempty"` // fuzzy breakdown
+
+	// Titles at proposal time, for reviewers.
+	TitleA string `yaml:"title_a,omitempty"`
+	TitleB string `yaml:"title_b,omitempty"`
+
+	Note       string    `yaml:"note,omitempty"`
+	ProposedAt time.Time `yaml:"proposed_at,omitempty"`
+	ReviewedAt time.Time `yaml:"reviewed_at,omitempty"`
+}
+
+// LinkID is a short deterministic ID of the pair a-b, independent of order.
+func LinkID(a, b pipeline.MarketRef) string {
+	ka, kb := pipeline.HistoryKey(a.Venue, a.MarketID), pipeline.HistoryKey(b.Venue, b.MarketID)
+	if kb < ka {
+		ka, kb = kb, ka
+	}
+	sum := sha256.Sum256([]byte(ka + "|" + kb))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// fileHeader starts every saved mapping file.
+const fileHeader = "# Cross-venue market mapping. Hand-written links are approved equivalences;\n" +
+	"# review fuzzy proposals with cmd/matching (list, approve, reject).\n"
+
+// Mapping is a mapping file.
+type Mapping struct {
+	Links []Link `yaml:"links"`
+}
+
+// LoadMapping reads and validates a mapping file. A missing file is an empty
+// mapping.
+func LoadMapping(p string) (*Mapping, error) {
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Mapping{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Mapping
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	return &m, nil
+}
+
+// Validate fills defaults and checks every link.
+func (m *Mapping) Validate() error {
+	seen := map[string]bool{}
+	for i := range m.Links {
+		l := &m.Links[i]
+
+		for _, ref := range []pipeline.MarketRef{l.A, l.B} {
+			if ref.MarketID == "" {
+				return fmt.Errorf("link[%d]: market_id is required", i)
+			}
+			if err := ref.Validate(); err != nil {
+				return fmt.Errorf("link[%d]: %w", i, err)
+			}
+		}
+		if l.A.Venue == l.B.Venue && l.A.MarketID == l.B.MarketID {
+			return fmt.Errorf("link[%d]: market linked to itself", i)
+		}
+
+		if l.Relation == "" {
+			l.Relation = RelationEquivalent
+		}
+		if l.Source == "" {
+			l.Source = SourceCurated
+		}
+		if l.Status == "" {
+			l.Status = StatusApproved
+			if l.Source == SourceFuzzy {
+				l.Status = StatusPending
+			}
+		}
+		if l.Confidence == 0 && l.Source == SourceCurated {
+			l.Confidence = 1
+		}
+
+		switch l.Relation {
+		case RelationEquivalent, RelationRelated:
+		default:
+			return fmt.Errorf("link[%d]: unknown relation '%s'", i, l.Relation)
+		}
+		switch l.Status {
+		case StatusPending, StatusApproved, StatusRejected:
+		default:
+			return fmt.Errorf("link[%d]: unknown status '%s'", i, l.Status)
+		}
+		if l.Confidence < 0 || l.Confidence > 1 {
+			return fmt.Errorf("link[%d]: confidence must be in [0,1]", i)
+		}
+
+		id := LinkID(l.A, l.B)
+		if l.ID == "" {
+			l.ID = id
+		}
+		if seen[id] {
+			return fmt.Errorf("link[%d]: duplicate link %s", i, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Save writes the mapping sorted by status (pending first) and ID, replacing
+// the file atomically.
+func (m *Mapping) Save(p string) error {
+	order := map[Status]int{StatusPending: 0, StatusApproved: 1, StatusRejected: 2}
+	sort.SliceStable(m.Links, func(i, j int) bool {
+		a, b := m.Links[i], m.Links[j]
+		if order[a.Status] != order[b.Status] {
+			return order[a.Status] < order[b.Status]
+		}
+		return a.ID < b.ID
+	})
+
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".mapping-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append([]byte(fileHeader), data...)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Propose adds pairs as pending fuzzy links with the given relation and
+// returns how many are new. Pairs already in the file keep their review;
+// pending ones get the new score. Equivalences are one-to-one, so markets
+// with an approved equivalence are not proposed again.
+func (m *Mapping) Propose(pairs []Pair, relation string, now time.Time) int {
+	byID := map[string]int{}
+	linked := map[string]bool{}
+	for i, l := range m.Links {
+		byID[l.ID] = i
+		if l.Status == StatusApproved && l.Relation == RelationEquivalent {
+			linked[pipeline.HistoryKey(l.A.Venue, l.A.MarketID)] = true
+			linked[pipeline.HistoryKey(l.B.Venue, l.B.MarketID)] = true
+		}
+	}
+
+	added := 0
+	for _, p := range pairs {
+		score := p.Score
+		id := LinkID(p.A.Ref(), p.B.Ref())
+		if i, ok := byID[id]; ok {
+			if l := &m.Links[i]; l.Status == StatusPending && l.Source == SourceFuzzy {
+				l.Confidence, l.Score = score.Confidence, &score
+			}
+			continue
+		}
+		if relation == RelationEquivalent && (linked[p.A.Key()] || linked[p.B.Key()]) {
+			continue
+		}
+
+		byID[id] = len(m.Links)
+		m.Links = append(m.Links, Link{
+			ID:         id,
+			A:          p.A.Ref(),
+			B:          p.B.Ref(),
+			Relation:   relation,
+			Source:     SourceFuzzy,
+			Status:     StatusPending,
+			Confidence: score.Confidence,
+			Score:      &score,
+			TitleA:     p.A.Title,
+			TitleB:     p.B.Title,
+			ProposedAt: now.UTC(),
+		})
+		added++
+	}
+	return added
+}
+
+// Review sets the status of the link with the given ID.
+func (m *Mapping) Review(id string, status Status, note string, now time.Time) error {
+	switch status {
+	case StatusApproved, StatusRejected, StatusPending:
+	default:
+		return fmt.Errorf("unknown status '%s'", status)
+	}
+	for i := range m.Links {
+		if m.Links[i].ID != id {
+			continue
+		}
+		l := &m.Links[i]
+		l.Status = status
+		l.ReviewedAt = now.UTC()
+		if note != "" {
+			l.Note = note
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown link '%s'", id)
+}
+
+// Counterpart is a market linked to another one.
+type Counterpart struct {
+	Ref        pipeline.MarketRef
+	Relation   string
+	Confidence float64
+}
+
+// Index looks up the approved links of a market.
+type Index map[string][]Counterpart
+
+// Index returns the approved links, in both directions, keyed by
+// pipeline.HistoryKey.
+func (m *Mapping) Index() Index {
+	idx := Index{}
+	for _, l := range m.Links {
+		if l.Status != StatusApproved {
+			continue
+		}
+		ka := pipeline.HistoryKey(l.A.Venue, l.A.MarketID)
+		kb := pipeline.HistoryKey(l.B.Venue, l.B.MarketID)
+		idx[ka] = append(idx[ka], Counterpart{Ref: l.B, Relation: l.Relation, Confidence: l.Confidence})
+		idx[kb] = append(idx[kb], Counterpart{Ref: l.A, Relation: l.Relation, Confidence: l.Confidence})
+	}
+	return idx
+}
+
+// Equivalent returns the markets that ask the same question as the market
+// with the given key.
+func (idx Index) Equivalent(key string) []Counterpart {
+	var out []Counterpart
+	for _, c := range idx[key] {
+		if c.Relation == RelationEquivalent {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// EquivalentRefs is Equivalent as market references, in the shape of
+// pipeline.Pipeline.Counterparts.
+func (idx Index) EquivalentRefs(key string) []pipeline.MarketRef {
+	var out []pipeline.MarketRef
+	for _, c := range idx.Equivalent(key) {
+		out = append(out, c.Ref)
+	}
+	return out
+}
@@ -0,0 +1,376 @@
+// Package matching links equivalent questions across Polymarket and Kalshi,
+// and related markets within a venue. Links come from a curated mapping file
+// (see Mapping) and from fuzzy title/date/strike matching, whose proposals
+// are reviewed before they are trusted.
+package matching
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/pipeline"
+)
+
+// Defaults used when Config leaves a field unset.
+const (
+	DefaultMinConfidence   = 0.6
+	DefaultDateWindow      = 72 * time.Hour
+	DefaultStrikeTolerance = 0.005
+)
+
+// DefaultWeights weigh title similarity above dates and strikes.
+var DefaultWeights = Weights{Title: 0.6, Date: 0.25, Strike: 0.15}
+
+// Config tunes fuzzy matching.
+type Config struct {
+	// MinConfidence is the lowest confidence a proposal may have.
+	MinConfidence float64
+
+	// DateWindow is how far apart end dates may be; beyond it two markets
+	// are different questions. End dates within a day score 1.
+	DateWindow time.Duration
+
+	// StrikeTolerance is the relative difference under which two strikes
+	// are the same number (e.g. 105,000 vs 104,999.99).
+	StrikeTolerance float64
+
+	Weights Weights
+}
+
+// Weights of the component scores in the confidence.
+type Weights struct {
+	Title  float64 `json:"title" yaml:"title"`
+	Date   float64 `json:"date" yaml:"date"`
+	Strike float64 `json:"strike" yaml:"strike"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.MinConfidence <= 0 {
+		c.MinConfidence = DefaultMinConfidence
+	}
+	if c.DateWindow <= 0 {
+		c.DateWindow = DefaultDateWindow
+	}
+	if c.StrikeTolerance <= 0 {
+		c.StrikeTolerance = DefaultStrikeTolerance
+	}
+	if c.Weights == (Weights{}) {
+		c.Weights = DefaultWeights
+	}
+	return c
+}
+
+// Candidate is a market that can be matched.
+type Candidate struct {
+	Venue    pipeline.Venue `json:"venue"`
+	EventID  string         `json:"event_id,omitempty"`
+	MarketID string         `json:"market_id"`
+	Title    string         `json:"title"`
+	EndDate  time.Time      `json:"end_date,omitempty"`
+}
+
+// Key is the pipeline.HistoryKey of the candidate.
+func (c Candidate) Key() string {
+	return pipeline.HistoryKey(c.Venue, c.MarketID)
+}
+
+// Ref is the market reference of the candidate.
+func (c Candidate) Ref() pipeline.MarketRef {
+	return pipeline.MarketRef{Venue: c.Venue, MarketID: c.MarketID, EventID: c.EventID}
+}
+
+// Candidates lists the markets of a snapshot. Markets without a title fall
+// back to their event title.
+func Candidates(venue pipeline.Venue, snap polymarket.Snapshot) []Candidate {
+	var out []Candidate
+	for _, es := range snap.Events {
+		for _, mp := range es.Markets {
+			title := mp.Title
+			if title == "" {
+				title = es.Title
+			}
+			out = append(out, Candidate{
+				Venue:    venue,
+				EventID:  es.EventID,
+				MarketID: mp.MarketID,
+				Title:    title,
+				EndDate:  es.EndDate,
+			})
+		}
+	}
+	return out
+}
+
+// Score is how alike two markets are. Component scores are 0..1.
+type Score struct {
+	Title  float64 `json:"title" yaml:"title"`
+	Date   float64 `json:"date" yaml:"date"`
+	Strike float64 `json:"strike" yaml:"strike"`
+
+	// Confidence that both markets ask the same question: the weighted
+	// mean of the components, or 0 when the end dates or strikes disagree.
+	Confidence float64 `json:"confidence" yaml:"confidence"`
+}
+
+// Pair is a scored pair of markets.
+type Pair struct {
+	A     Candidate `json:"a"`
+	B     Candidate `json:"b"`
+	Score Score     `json:"score"`
+}
+
+// Compare scores a against b.
+func Compare(cfg Config, a, b Candidate) Score {
+	cfg = cfg.withDefaults()
+	return compare(cfg, prepare(a), prepare(b))
+}
+
+// Match proposes one-to-one links between left and right: every pair at or
+// above MinConfidence, best first, skipping markets that are already paired.
+func Match(cfg Config, left, right []Candidate) []Pair {
+	cfg = cfg.withDefaults()
+	l, r := prepareAll(left), prepareAll(right)
+
+	var pairs []Pair
+	for _, a := range l {
+		for _, b := range r {
+			if a.Key() == b.Key() {
+				continue
+			}
+			if s := compare(cfg, a, b); s.Confidence >= cfg.MinConfidence {
+				pairs = append(pairs, Pair{A: a.Candidate, B: b.Candidate, Score: s})
+			}
+		}
+	}
+	sortPairs(pairs)
+
+	usedA, usedB := map[string]bool{}, map[string]bool{}
+	out := pairs[:0]
+	for _, p := range pairs {
+		if usedA[p.A.Key()] || usedB[p.B.Key()] {
+			continue
+		}
+		usedA[p.A.Key()], usedB[p.B.Key()] = true, true
+		out = append(out, p)
+	}
+	return out
+}
+
+// Related lists the markets of all that are about the same topic and date
+// as c, best first. Unlike Match, strikes may differ (e.g. the other
+// brackets of a price ladder) and c may relate to many markets.
+func Related(cfg Config, c Candidate, all []Candidate) []Pair {
+	cfg = cfg.withDefaults()
+	pc := prepare(c)
+	w := cfg.Weights
+
+	var out []Pair
+	for _, o := range prepareAll(all) {
+		if o.Key() == c.Key() {
+			continue
+		}
+		s := compare(cfg, pc, o)
+		if s.Date == 0 || w.Title+w.Date == 0 {
+			continue
+		}
+		s.Confidence = (w.Title*s.Title + w.Date*s.Date) / (w.Title + w.Date)
+		if s.Confidence >= cfg.MinConfidence {
+			out = append(out, Pair{A: c, B: o.Candidate, Score: s})
+		}
+	}
+	sortPairs(out)
+	return out
+}
+
+func sortPairs(pairs []Pair) {
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Score.Confidence != pairs[j].Score.Confidence {
+			return pairs[i].Score.Confidence > pairs[j].Score.Confidence
+		}
+		if pairs[i].A.Key() != pairs[j].A.Key() {
+			return pairs[i].A.Key() < pairs[j].A.Key()
+		}
+		return pairs[i].B.Key() < pairs[j].B.Key()
+	})
+}
+
+// prepared is a candidate with its title tokenized.
+type prepared struct {
+	Candidate
+	words   map[string]bool
+	strikes []float64
+}
+
+func prepare(c Candidate) prepared {
+	words, strikes := tokenize(c.Title)
+	return prepared{Candidate: c, words: words, strikes: strikes}
+}
+
+func prepareAll(cs []Candidate) []prepared {
+	out := make([]prepared, len(cs))
+	for i, c := range cs {
+		out[i] = prepare(c)
+	}
+	return out
+}
+
+func compare(cfg Config, a, b prepared) Score {
+	s := Score{
+		Title:  jaccard(a.words, b.words),
+		Date:   dateScore(a.EndDate, b.EndDate, cfg.DateWindow),
+		Strike: strikeScore(a.strikes, b.strikes, cfg.StrikeTolerance),
+	}
+	w := cfg.Weights
+	if s.Title == 0 || s.Date == 0 || s.Strike == 0 || w.Title+w.Date+w.Strike == 0 {
+		return s
+	}
+	s.Confidence = (w.Title*s.Title + w.Date*s.Date + w.Strike*s.Strike) / (w.Title + w.Date + w.Strike)
+	return s
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// dateScore is 1 for end dates within a day, falling linearly to 0 at
+// window. An unknown end date scores 0.5.
+func dateScore(a, b time.Time, window time.Duration) float64 {
+	if a.IsZero() || b.IsZero() {
+		return 0.5
+	}
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+	switch {
+	case d <= 24*time.Hour:
+		return 1
+	case d >= window:
+		return 0
+	default:
+		return 1 - float64(d-24*time.Hour)/float64(window-24*time.Hour)
+	}
+}
+
+// strikeScore is the share of the strikes of the title with fewer strikes
+// that the other title also names. Titles without strikes agree (1); a
+// strike on one side only is unknown (0.5).
+func strikeScore(a, b []float64, tolerance float64) float64 {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 1
+	case len(a) == 0 || len(b) == 0:
+		return 0.5
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	matched := 0
+	for _, x := range a {
+		for _, y := range b {
+			if math.Abs(x-y) <= tolerance*math.Max(math.Abs(x), math.Abs(y)) {
+				matched++
+				break
+			}
+		}
+	}
+	return float64(matched) / float64(len(a))
+}
+
+var (
+	// Day of month and clock times are part of the date, not strikes.
+	monthDay  = regexp.MustCompile(`\b(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s+\d{1,2}(st|nd|rd|th)?\b`)
+	clockTime = regexp.MustCompile(`\b\d{1,2}(:\d{2})?\s*(am|pm)\b|\b\d{1,2}:\d{2}\b`)
+	number    = regexp.MustCompile(`\$?\d[\d,]*(\.\d+)?\s*(k|m|b|bn|%)?\b`)
+	word      = regexp.MustCompile(`[a-z]+`)
+)
+
+// stopwords carry no meaning for matching.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "will": true, "be": true, "is": true,
+	"of": true, "on": true, "in": true, "at": true, "by": true, "to": true,
+	"for": true, "or": true, "and": true, "this": true, "that": true,
+	"et": true, "est": true, "edt": true, "utc": true, "pm": true, "am": true,
+	"what": true, "which": true, "who": true, "how": true, "price": true,
+}
+
+// synonyms map venue wording onto one term.
+var synonyms = map[string]string{
+	"btc": "bitcoin", "eth": "ethereum", "sol": "solana",
+	"over": "above", "higher": "above", "exceed": "above", "exceeds": "above",
+	"under": "below", "lower": "below",
+	"fed": "fomc", "federal": "fomc",
+	"january": "jan", "february": "feb", "march": "mar", "april": "apr",
+	"june": "jun", "july": "jul", "august": "aug", "september": "sep",
+	"sept": "sep", "october": "oct", "november": "nov", "december": "dec",
+}
+
+// tokenize splits a title into normalized words and the numbers it names.
+// Years are kept as words since they date the question.
+func tokenize(title string) (map[string]bool, []float64) {
+	s := strings.ToLower(title)
+	words := map[string]bool{}
+
+	s = monthDay.ReplaceAllStringFunc(s, func(m string) string {
+		return word.FindString(m)
+	})
+	s = clockTime.ReplaceAllString(s, " ")
+
+	var strikes []float64
+	s = number.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := parseNumber(m); ok {
+			if !strings.ContainsAny(m, "$,.%") && v >= 1900 && v <= 2100 && v == math.Trunc(v) {
+				words[strconv.Itoa(int(v))] = true
+			} else {
+				strikes = append(strikes, v)
+			}
+		}
+		return " "
+	})
+
+	for _, w := range word.FindAllString(s, -1) {
+		if syn, ok := synonyms[w]; ok {
+			w = syn
+		}
+		if !stopwords[w] && len(w) > 1 {
+			words[w] = true
+		}
+	}
+	return words, strikes
+}
+
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(strings.TrimPrefix(s, "$"))
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "bn"):
+		mult, s = 1e9, strings.TrimSuffix(s, "bn")
+	case strings.HasSuffix(s, "b"):
+		mult, s = 1e9, strings.TrimSuffix(s, "b")
+	case strings.HasSuffix(s, "m"):
+		mult, s = 1e6, strings.TrimSuffix(s, "m")
+	case strings.HasSuffix(s, "k"):
+		mult, s = 1e3, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "%"):
+		s = strings.TrimSuffix(s, "%")
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v * mult, true
+}
@@ -0,0 +1,188 @@
+package matching
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"woodpecker/adapters/Kalshi/kalshi"
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/adapters/timestamp"
+	"woodpecker/pipeline"
+)
+
+var end = time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC)
+
+func poly(id, title string, endDate time.Time) Candidate {
+	return Candidate{Venue: pipeline.VenuePolymarket, MarketID: id, Title: title, EndDate: endDate}
+}
+
+func kal(ticker, title string, endDate time.Time) Candidate {
+	return Candidate{Venue: pipeline.VenueKalshi, MarketID: ticker, Title: title, EndDate: endDate}
+}
+
+func TestTokenize(t *testing.T) {
+	words, strikes := tokenize("Will BTC be above $100k on December 31, 2025 at 5pm ET?")
+	for _, w := range []string{"bitcoin", "above", "dec", "2025"} {
+		if !words[w] {
+			t.Fatalf("expected word %q in %v", w, words)
+		}
+	}
+	if len(strikes) != 1 || strikes[0] != 100_000 {
+		t.Fatalf("expected the single strike 100000, got %v", strikes)
+	}
+}
+
+func TestMatch_CrossVenueStrikeLadder(t *testing.T) {
+	left := []Candidate{
+		poly("p100", "Will Bitcoin be above $100,000 on December 31?", end),
+		poly("p110", "Will Bitcoin be above $110,000 on December 31?", end),
+		poly("rain", "Will it rain in NYC on December 31?", end),
+	}
+	right := []Candidate{
+		kal("KXBTCD-25DEC31-T110000", "Bitcoin price on Dec 31, 2025? $110,000 or above", end.Add(-5*time.Hour)),
+		kal("KXBTCD-25DEC31-T100000", "Bitcoin price on Dec 31, 2025? $100,000 or above", end.Add(-5*time.Hour)),
+	}
+
+	pairs := Match(Config{}, left, right)
+	if len(pairs) != 2 {
+		t.Fatalf("expected 2 matches, got %+v", pairs)
+	}
+	got := map[string]string{}
+	for _, p := range pairs {
+		got[p.A.MarketID] = p.B.MarketID
+		if p.Score.Strike != 1 || p.Score.Date != 1 {
+			t.Fatalf("unexpected score %+v", p.Score)
+		}
+	}
+	if got["p100"] != "KXBTCD-25DEC31-T100000" || got["p110"] != "KXBTCD-25DEC31-T110000" {
+		t.Fatalf("strikes must pair up, got %v", got)
+	}
+}
+
+func TestCompare_VetoesDistantDatesAndStrikes(t *testing.T) {
+	a := poly("p", "Will Bitcoin be above $100,000 on December 31?", end)
+
+	if s := Compare(Config{}, a, kal("K", "Bitcoin above $100,000 on Dec 31", end.Add(10*24*time.Hour))); s.Confidence != 0 {
+		t.Fatalf("end dates 10 days apart must not match: %+v", s)
+	}
+	if s := Compare(Config{}, a, kal("K", "Bitcoin above $120,000 on Dec 31", end)); s.Confidence != 0 {
+		t.Fatalf("different strikes must not match: %+v", s)
+	}
+	if s := Compare(Config{}, a, kal("K", "Bitcoin above $100,000 on Dec 31", time.Time{})); s.Date != 0.5 || s.Confidence == 0 {
+		t.Fatalf("an unknown end date must only weaken the match: %+v", s)
+	}
+}
+
+func TestRelated_IgnoresStrikes(t *testing.T) {
+	all := []Candidate{
+		kal("T100", "Bitcoin price on Dec 31? $100,000 or above", end),
+		kal("T110", "Bitcoin price on Dec 31? $110,000 or above", end),
+		kal("RAIN", "Will it rain in NYC on Dec 31?", end),
+	}
+	rel := Related(Config{}, all[0], all)
+	if len(rel) != 1 || rel[0].B.MarketID != "T110" {
+		t.Fatalf("expected only the other bracket, got %+v", rel)
+	}
+}
+
+func TestCandidates_FromSnapshots(t *testing.T) {
+	snap := kalshi.BuildSnapshot([]model.Market{{
+		Ticker:      "KXBTCD-25DEC31-T100000",
+		EventTicker: "KXBTCD-25DEC31",
+		Title:       "Bitcoin price on Dec 31, 2025?",
+		YesSubTitle: "$100,000 or above",
+		CloseTime:   timestamp.FromString("2025-12-31T22:00:00Z"),
+	}}, end)
+
+	cs := Candidates(pipeline.VenueKalshi, snap)
+	if len(cs) != 1 || cs[0].Title != "Bitcoin price on Dec 31, 2025? $100,000 or above" || !cs[0].EndDate.Equal(end) {
+		t.Fatalf("unexpected candidates %+v", cs)
+	}
+
+	fallback := Candidates(pipeline.VenuePolymarket, polymarket.Snapshot{Events: []polymarket.EventSnapshot{{
+		EventID: "e", Title: "Event title", Markets: []polymarket.MarketPoint{{MarketID: "m"}},
+	}}})
+	if fallback[0].Title != "Event title" {
+		t.Fatalf("expected the event title fallback, got %+v", fallback[0])
+	}
+}
+
+func TestMapping_ReviewWorkflow(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "mappings.yaml")
+	curated := `links:
+  - a: {venue: polymarket, market_id: "p100"}
+    b: {venue: kalshi, market_id: "KXBTCD-25DEC31-T100000"}
+`
+	if err := os.WriteFile(p, []byte(curated), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := LoadMapping(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := m.Links[0]; l.Status != StatusApproved || l.Source != SourceCurated || l.Confidence != 1 || l.ID == "" {
+		t.Fatalf("unexpected curated defaults %+v", l)
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pairs := []Pair{
+		{A: poly("p100", "t", end), B: kal("KXOTHER", "t", end), Score: Score{Confidence: 0.9}},
+		{A: poly("p110", "t", end), B: kal("KXBTCD-25DEC31-T110000", "t", end), Score: Score{Confidence: 0.8}},
+	}
+	if added := m.Propose(pairs, RelationEquivalent, now); added != 1 {
+		t.Fatalf("p100 is already linked, expected 1 new proposal, got %d", added)
+	}
+	id := LinkID(pairs[1].A.Ref(), pairs[1].B.Ref())
+
+	if len(m.Index().Equivalent("polymarket:p110")) != 0 {
+		t.Fatal("pending links must not be indexed")
+	}
+	if err := m.Review(id, StatusRejected, "different settlement source", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(p); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = LoadMapping(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added := m.Propose(pairs[1:], RelationEquivalent, now); added != 0 {
+		t.Fatal("rejected pairs must not be proposed again")
+	}
+	eq := m.Index().Equivalent("kalshi:KXBTCD-25DEC31-T100000")
+	if len(eq) != 1 || eq[0].Ref.MarketID != "p100" {
+		t.Fatalf("expected the curated counterpart, got %+v", eq)
+	}
+
+	data, _ := os.ReadFile(p)
+	if !strings.HasPrefix(string(data), "#") || !strings.Contains(string(data), "different settlement source") {
+		t.Fatalf("unexpected saved mapping:\n%s", data)
+	}
+	if err := m.Review("nope", StatusApproved, "", now); err == nil {
+		t.Fatal("expected unknown link to be rejected")
+	}
+}
+
+func TestLoadMapping_Invalid(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "mappings.yaml")
+	bad := `links:
+  - a: {venue: polymarket, market_id: "p"}
+    b: {venue: polymarket, market_id: "p"}
+`
+	if err := os.WriteFile(p, []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMapping(p); err == nil {
+		t.Fatal("expected a self link to be rejected")
+	}
+	if m, err := LoadMapping(filepath.Join(t.TempDir(), "missing.yaml")); err != nil || len(m.Links) != 0 {
+		t.Fatalf("a missing file is an empty mapping, got %v %v", m, err)
+	}
+}
@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"sync"
+
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+)
+
+// DefaultHistoryLen is how many points MemoryHistory keeps per market.
+const DefaultHistoryLen = 120
+
+// HistoryStore keeps the recent MarketPoints of each market, oldest first.
+// Keys are "<venue>:<market id>".
+type HistoryStore interface {
+	History(key string) []polymarket.MarketPoint
+	Append(key string, mp polymarket.MarketPoint)
+}
+
+// MemoryHistory is an in-process HistoryStore bounded per market.
+type MemoryHistory struct {
+	max int
+
+	mu     sync.Mutex
+	points map[string][]polymarket.MarketPoint
+}
+
+// NewMemoryHistory keeps up to max points per market (DefaultHistoryLen if max <= 0).
+func NewMemoryHistory(max int) *MemoryHistory {
+	if max <= 0 {
+		max = DefaultHistoryLen
+	}
+	return &MemoryHistory{
+		max:    max,
+		points: make(map[string][]polymarket.MarketPoint),
+	}
+}
+
+// History returns a copy of the stored points for key.
+func (h *MemoryHistory) History(key string) []polymarket.MarketPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]polymarket.MarketPoint(nil), h.points[key]...)
+}
+
+// Append stores mp, dropping the oldest point when the market is full.
+func (h *MemoryHistory) Append(key string, mp polymarket.MarketPoint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pts := append(h.points[key], mp)
+	if len(pts) > h.max {
+		pts = append([]polymarket.MarketPoint(nil), pts[len(pts)-h.max:]...)
+	}
+	h.points[key] = pts
+}
@@ -0,0 +1,477 @@
+// Package pipeline turns a venue market reference into reasoner inputs:
+// snapshot → MarketPoint → FeatureVector → signals.
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"woodpecker/adapters/Kalshi/kalshi"
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/planning/reasoner"
+)
+
+// Venue is a prediction market venue.
+type Venue string
+
+const (
+	VenuePolymarket Venue = "polymarket"
+	VenueKalshi     Venue = "kalshi"
+)
+
+var (
+	// ErrMarketNotFound means the venue does not know the market or event.
+	ErrMarketNotFound = errors.New("market not found")
+	// ErrInvalidRef means the MarketRef cannot be resolved as given.
+	ErrInvalidRef = errors.New("invalid market reference")
+	// ErrVenueUnavailable means the pipeline has no source for the venue.
+	ErrVenueUnavailable = errors.New("venue not configured")
+)
+
+// MarketRef identifies what to evaluate: one market (MarketID, with EventID
+// as an optional hint) or every market of an event (EventID only).
+// For Kalshi, MarketID is the market ticker and EventID the event ticker.
+type MarketRef struct {
+	Venue    Venue  `json:"venue" yaml:"venue"`
+	MarketID string `json:"market_id,omitempty" yaml:"market_id,omitempty"`
+	EventID  string `json:"event_id,omitempty" yaml:"event_id,omitempty"`
+}
+
+// Validate checks the venue and that at least one ID is set.
+func (r MarketRef) Validate() error {
+	switch r.Venue {
+	case VenuePolymarket, VenueKalshi:
+	default:
+		return fmt.Errorf("%w: unknown venue '%s'", ErrInvalidRef, r.Venue)
+	}
+	if r.MarketID == "" && r.EventID == "" {
+		return fmt.Errorf("%w: market_id or event_id is required", ErrInvalidRef)
+	}
+	return nil
+}
+
+// HistoryKey is the HistoryStore key of a market.
+func HistoryKey(venue Venue, marketID string) string {
+	return string(venue) + ":" + marketID
+}
+
+// GammaSource is the subset of the Gamma client the pipeline needs.
+type GammaSource interface {
+	FetchEvent(id string) (polymarket.Event, error)
+	FetchMarket(id string) (polymarket.Market, error)
+}
+
+// KalshiSource is the subset of the Kalshi client the pipeline needs.
+type KalshiSource interface {
+	GetMarket(ticker string) (model.Market, error)
+	GetMarketsByEvent(eventTicker string) ([]model.Market, error)
+}
+
+// GammaBookSource is implemented by Gamma sources that can fetch CLOB order
+// books (polymarket.Client).
+type GammaBookSource interface {
+	FetchOrderBook(tokenID string) (polymarket.OrderBook, error)
+}
+
+// KalshiBookSource is implemented by Kalshi sources that can fetch order
+// books (kalshi.Client).
+type KalshiBookSource interface {
+	GetOrderBook(ticker string) (model.OrderBook, error)
+}
+
+// Pipeline fetches markets from their venue and computes features and
+// signals. Sources may be nil when a venue is not configured.
+type Pipeline struct {
+	Gamma   GammaSource
+	Kalshi  KalshiSource
+	History HistoryStore
+
+	// Now stamps Kalshi snapshots; defaults to time.Now.
+	Now func() time.Time
+
+	// MinHistoryInterval is the minimum time between two points stored for
+	// a market. Points observed sooner after the last stored one (or not
+	// after it) are evaluated but not stored, so that bursts of requests do
+	// not collapse the series momentum and volatility are computed over.
+	// DefaultMinHistoryInterval when 0.
+	MinHistoryInterval time.Duration
+
+	// Books fetches the order book of each evaluated market, when its
+	// source implements GammaBookSource or KalshiBookSource, for the
+	// FeatureVector.Book features. Markets whose book cannot be fetched are
+	// evaluated without one; the first failure of each venue is logged.
+	Books bool
+	// bookErrs records the venues whose book failures were logged.
+	bookErrs sync.Map
+
+	// MinQuality skips markets whose data quality score (see
+	// polymarket.ScoreMarket) is below it: they get no signals and are not
+	// added to History. Points above it are down-weighted through
+	// FeatureVector.DataQuality. 0 skips nothing.
+	MinQuality float64
+
+	// Peers chooses the peer groups Dispersion is computed over.
+	Peers polymarket.PeerConfig
+
+	// Counterparts returns the markets equivalent to the one with the given
+	// HistoryKey, e.g. matching.Index.EquivalentRefs. When set (with a
+	// History), markets are compared with the latest point of their first
+	// fresh counterpart for the cross-venue features; counterparts missing
+	// from History or stale are fetched from their venue source.
+	Counterparts func(key string) []MarketRef
+	// Fees are per-venue trading fees; DefaultFees when nil.
+	Fees map[Venue]polymarket.FeeFunc
+	// MaxCounterpartAge is how stale a counterpart price may be;
+	// DefaultMaxCounterpartAge when 0.
+	MaxCounterpartAge time.Duration
+}
+
+// DefaultMinHistoryInterval spaces stored points; below the stream loop's
+// usual interval so that each of its ticks is kept.
+const DefaultMinHistoryInterval = 30 * time.Second
+
+// DefaultMaxCounterpartAge bounds how old a counterpart price may be.
+const DefaultMaxCounterpartAge = 15 * time.Minute
+
+// DefaultFees are the taker fees of each venue.
+var DefaultFees = map[Venue]polymarket.FeeFunc{
+	VenuePolymarket: polymarket.NoFee,
+	VenueKalshi:     kalshi.TakerFee,
+}
+
+// MarketSignals are the reasoner inputs computed for one market.
+type MarketSignals struct {
+	Venue   Venue                  `json:"venue"`
+	EventID string                 `json:"event_id"`
+	Point   polymarket.MarketPoint `json:"market"`
+
+	Features polymarket.FeatureVector `json:"features"`
+	Signals  []reasoner.SignalInput   `json:"-"`
+
+	// Skipped is set when the point's data quality is below
+	// Pipeline.MinQuality; Point.Quality says why.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// DataQuality is the data quality score of the market point, 1 when it was
+// not assessed.
+func (m MarketSignals) DataQuality() float64 {
+	if m.Point.Quality == nil {
+		return 1
+	}
+	return m.Point.Quality.Score
+}
+
+// Result is one pipeline run: the snapshot it was computed from and the
+// requested markets.
+type Result struct {
+	Snapshot polymarket.Snapshot
+	Markets  []MarketSignals
+}
+
+// Run snapshots the referenced market(s) and computes their features and
+// signals. Peers come from the snapshot (see Peers); each evaluated market is
+// appended to History.
+func (p *Pipeline) Run(ref MarketRef) (Result, error) {
+	if err := ref.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	snap, err := p.snapshot(ref)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Snapshot: snap}
+	peers := polymarket.NewPeerIndex(snap, p.Peers)
+	for _, es := range snap.Events {
+		for _, mp := range es.Markets {
+			if ref.MarketID != "" && mp.MarketID != ref.MarketID {
+				continue
+			}
+			if p.Books {
+				mp.Book = p.orderBook(ref.Venue, mp)
+			}
+			res.Markets = append(res.Markets, p.compute(ref.Venue, snap, es, mp, peers))
+		}
+	}
+	if len(res.Markets) == 0 {
+		return Result{}, fmt.Errorf("%w: %s market %s in event %s", ErrMarketNotFound, ref.Venue, ref.MarketID, ref.EventID)
+	}
+	return res, nil
+}
+
+// Compute computes features and signals for every market of an already built
+// snapshot (e.g. one loaded from disk). Snapshots of the same markets must be
+// passed in time order for History-based features to be meaningful.
+func (p *Pipeline) Compute(venue Venue, snap polymarket.Snapshot) []MarketSignals {
+	var out []MarketSignals
+	peers := polymarket.NewPeerIndex(snap, p.Peers)
+	for _, es := range snap.Events {
+		for _, mp := range es.Markets {
+			out = append(out, p.compute(venue, snap, es, mp, peers))
+		}
+	}
+	return out
+}
+
+func (p *Pipeline) compute(venue Venue, snap polymarket.Snapshot, es polymarket.EventSnapshot, mp polymarket.MarketPoint, peers *polymarket.PeerIndex) MarketSignals {
+	key := HistoryKey(venue, mp.MarketID)
+
+	// Snapshots loaded from disk may predate quality scoring.
+	if mp.Quality == nil {
+		q := polymarket.ScoreMarket(mp, snap.Timestamp, polymarket.DefaultQualityConfig)
+		mp.Quality = &q
+	}
+	if mp.Quality.Score < p.MinQuality {
+		return MarketSignals{Venue: venue, EventID: es.EventID, Point: mp, Skipped: true}
+	}
+
+	var (
+		history []polymarket.MarketPoint
+		prev    *polymarket.MarketPoint
+	)
+	if p.History != nil {
+		history = p.History.History(key)
+		if n := len(history); n > 0 {
+			last := history[n-1]
+			prev = &last
+		}
+	}
+
+	features := polymarket.ComputeFeatures(mp, prev, append(history, mp), peers.Groups(es.EventID, mp.MarketID))
+
+	observedAt := mp.UpdatedAt
+	if observedAt.IsZero() {
+		observedAt = snap.Timestamp
+	}
+
+	// Stored points carry the time they were observed at, so that
+	// counterparts on other venues can be paired by time. Books are not
+	// kept.
+	stored := mp
+	stored.UpdatedAt = observedAt
+	stored.Book = nil
+	if p.History != nil {
+		features.CrossVenue = p.crossVenue(venue, append(history, stored))
+		if p.newer(stored, prev) {
+			p.History.Append(key, stored)
+		}
+	}
+	signals := polymarket.BuildAllSignals(features)
+	for i := range signals {
+		signals[i].ObservedAt = observedAt
+	}
+
+	return MarketSignals{
+		Venue:    venue,
+		EventID:  es.EventID,
+		Point:    mp,
+		Features: features,
+		Signals:  signals,
+	}
+}
+
+// newer reports whether mp was observed at least MinHistoryInterval after
+// the last stored point.
+func (p *Pipeline) newer(mp polymarket.MarketPoint, last *polymarket.MarketPoint) bool {
+	if last == nil {
+		return true
+	}
+	interval := p.MinHistoryInterval
+	if interval <= 0 {
+		interval = DefaultMinHistoryInterval
+	}
+	return mp.UpdatedAt.Sub(last.UpdatedAt) >= interval
+}
+
+// crossVenue compares the market (history ends with its current point) with
+// its first counterpart that has a fresh price. A counterpart never seen, or
+// only with stale points, is snapshot from its venue (when configured) and
+// added to History.
+func (p *Pipeline) crossVenue(venue Venue, history []polymarket.MarketPoint) *polymarket.CrossVenueFeatures {
+	if p.Counterparts == nil {
+		return nil
+	}
+	maxAge := p.MaxCounterpartAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxCounterpartAge
+	}
+	fees := p.Fees
+	if fees == nil {
+		fees = DefaultFees
+	}
+
+	key := HistoryKey(venue, history[len(history)-1].MarketID)
+	for _, ref := range p.Counterparts(key) {
+		otherKey := HistoryKey(ref.Venue, ref.MarketID)
+		other := p.History.History(otherKey)
+		cv := polymarket.ComputeCrossVenue(history, other, maxAge, fees[venue], fees[ref.Venue])
+		if cv == nil {
+			if mp, ok := p.counterpartPoint(ref); ok {
+				var last *polymarket.MarketPoint
+				if n := len(other); n > 0 {
+					last = &other[n-1]
+				}
+				if p.newer(mp, last) {
+					p.History.Append(otherKey, mp)
+				}
+				// Fetched while the market is evaluated, so it pairs with
+				// the current point even if stamped slightly after it.
+				if cur := history[len(history)-1].UpdatedAt; mp.UpdatedAt.After(cur) {
+					mp.UpdatedAt = cur
+				}
+				cv = polymarket.ComputeCrossVenue(history, append(other, mp), maxAge, fees[venue], fees[ref.Venue])
+			}
+		}
+		if cv != nil {
+			cv.Counterpart = otherKey
+			return cv
+		}
+	}
+	return nil
+}
+
+// counterpartPoint snapshots ref from its venue and returns its point, stamped
+// with the time it was observed at. Venues without a source are skipped.
+func (p *Pipeline) counterpartPoint(ref MarketRef) (polymarket.MarketPoint, bool) {
+	snap, err := p.snapshot(ref)
+	if err != nil {
+		if !errors.Is(err, ErrVenueUnavailable) {
+			log.Printf("⚠️ pipeline: counterpart %s: %v", HistoryKey(ref.Venue, ref.MarketID), err)
+		}
+		return polymarket.MarketPoint{}, false
+	}
+	for _, es := range snap.Events {
+		for _, mp := range es.Markets {
+			if mp.MarketID != ref.MarketID {
+				continue
+			}
+			if mp.UpdatedAt.IsZero() {
+				mp.UpdatedAt = snap.Timestamp
+			}
+			mp.Book = nil
+			return mp, true
+		}
+	}
+	return polymarket.MarketPoint{}, false
+}
+
+// orderBook fetches the book of mp from its venue, or nil.
+func (p *Pipeline) orderBook(venue Venue, mp polymarket.MarketPoint) *polymarket.OrderBook {
+	var (
+		book polymarket.OrderBook
+		err  error
+	)
+	switch venue {
+	case VenuePolymarket:
+		src, ok := p.Gamma.(GammaBookSource)
+		if !ok || mp.TokenID == "" {
+			return nil
+		}
+		book, err = src.FetchOrderBook(mp.TokenID)
+	case VenueKalshi:
+		src, ok := p.Kalshi.(KalshiBookSource)
+		if !ok {
+			return nil
+		}
+		var raw model.OrderBook
+		raw, err = src.GetOrderBook(mp.MarketID)
+		book = kalshi.ToOrderBook(raw)
+	}
+	if err != nil {
+		if _, logged := p.bookErrs.LoadOrStore(venue, true); !logged {
+			log.Printf("⚠️ pipeline: %s order book %s: %v (further %s book errors are not logged)", venue, mp.MarketID, err, venue)
+		}
+		return nil
+	}
+	return &book
+}
+
+// snapshot fetches the event of ref from its venue.
+func (p *Pipeline) snapshot(ref MarketRef) (polymarket.Snapshot, error) {
+	switch ref.Venue {
+	case VenuePolymarket:
+		return p.polymarketSnapshot(ref)
+	case VenueKalshi:
+		return p.kalshiSnapshot(ref)
+	}
+	return polymarket.Snapshot{}, fmt.Errorf("%w: unknown venue '%s'", ErrInvalidRef, ref.Venue)
+}
+
+func (p *Pipeline) polymarketSnapshot(ref MarketRef) (polymarket.Snapshot, error) {
+	if p.Gamma == nil {
+		return polymarket.Snapshot{}, fmt.Errorf("%w: %s", ErrVenueUnavailable, VenuePolymarket)
+	}
+
+	eventID := ref.EventID
+	var market *polymarket.Market
+	if eventID == "" {
+		m, err := p.Gamma.FetchMarket(ref.MarketID)
+		if err != nil {
+			return polymarket.Snapshot{}, notFound(err, polymarket.ErrNotFound)
+		}
+		if len(m.Events) == 0 {
+			// No parent event: snapshot the market on its own.
+			return polymarket.BuildSnapshot([]polymarket.Event{{Markets: []polymarket.Market{m}}}), nil
+		}
+		eventID = m.Events[0].ID
+		market = &m
+	}
+
+	event, err := p.Gamma.FetchEvent(eventID)
+	if err != nil {
+		return polymarket.Snapshot{}, notFound(err, polymarket.ErrNotFound)
+	}
+	if market != nil && !hasMarket(event, market.ID) {
+		event.Markets = append(event.Markets, *market)
+	}
+	return polymarket.BuildSnapshot([]polymarket.Event{event}), nil
+}
+
+func (p *Pipeline) kalshiSnapshot(ref MarketRef) (polymarket.Snapshot, error) {
+	if p.Kalshi == nil {
+		return polymarket.Snapshot{}, fmt.Errorf("%w: %s", ErrVenueUnavailable, VenueKalshi)
+	}
+
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+
+	eventTicker := ref.EventID
+	if eventTicker == "" {
+		m, err := p.Kalshi.GetMarket(ref.MarketID)
+		if err != nil {
+			return polymarket.Snapshot{}, notFound(err, kalshi.ErrNotFound)
+		}
+		eventTicker = m.EventTicker
+	}
+
+	markets, err := p.Kalshi.GetMarketsByEvent(eventTicker)
+	if err != nil {
+		return polymarket.Snapshot{}, notFound(err, kalshi.ErrNotFound)
+	}
+	return kalshi.BuildSnapshot(markets, now()), nil
+}
+
+// notFound maps a venue's not-found error to ErrMarketNotFound.
+func notFound(err, venueNotFound error) error {
+	if errors.Is(err, venueNotFound) {
+		return fmt.Errorf("%w: %v", ErrMarketNotFound, err)
+	}
+	return err
+}
+
+func hasMarket(e polymarket.Event, marketID string) bool {
+	for _, m := range e.Markets {
+		if m.ID == marketID {
+			return true
+		}
+	}
+	return false
+}
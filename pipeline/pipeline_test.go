@@ -0,0 +1,326 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"woodpecker/adapters/Kalshi/kalshi"
+	"woodpecker/adapters/Kalshi/model"
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+)
+
+type fakeGamma struct {
+	events  map[string]polymarket.Event
+	markets map[string]polymarket.Market
+}
+
+func (f fakeGamma) FetchEvent(id string) (polymarket.Event, error) {
+	e, ok := f.events[id]
+	if !ok {
+		return e, fmt.Errorf("%w: event %s", polymarket.ErrNotFound, id)
+	}
+	return e, nil
+}
+
+func (f fakeGamma) FetchMarket(id string) (polymarket.Market, error) {
+	m, ok := f.markets[id]
+	if !ok {
+		return m, fmt.Errorf("%w: market %s", polymarket.ErrNotFound, id)
+	}
+	return m, nil
+}
+
+type fakeKalshi struct {
+	markets []model.Market
+	books   map[string]model.OrderBook
+}
+
+func (f *fakeKalshi) GetMarket(ticker string) (model.Market, error) {
+	for _, m := range f.markets {
+		if m.Ticker == ticker {
+			return m, nil
+		}
+	}
+	return model.Market{}, kalshi.ErrNotFound
+}
+
+func (f *fakeKalshi) GetMarketsByEvent(eventTicker string) ([]model.Market, error) {
+	var out []model.Market
+	for _, m := range f.markets {
+		if m.EventTicker == eventTicker {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeKalshi) GetOrderBook(ticker string) (model.OrderBook, error) {
+	b, ok := f.books[ticker]
+	if !ok {
+		return b, kalshi.ErrNotFound
+	}
+	return b, nil
+}
+
+func gammaFixture() fakeGamma {
+	event := polymarket.Event{
+		ID: "e1",
+		Markets: []polymarket.Market{
+			{ID: "m1", BestBid: 0.40, BestAsk: 0.44, LiquidityNum: 5000, VolumeNum: 20000},
+			{ID: "m2", BestBid: 0.70, BestAsk: 0.72, LiquidityNum: 8000, VolumeNum: 10000},
+		},
+	}
+	m1 := event.Markets[0]
+	m1.Events = []polymarket.Event{{ID: "e1"}}
+	return fakeGamma{
+		events:  map[string]polymarket.Event{"e1": event},
+		markets: map[string]polymarket.Market{"m1": m1},
+	}
+}
+
+func TestPipeline_PolymarketMarket(t *testing.T) {
+	p := &Pipeline{Gamma: gammaFixture(), History: NewMemoryHistory(10)}
+
+	res, err := p.Run(MarketRef{Venue: VenuePolymarket, MarketID: "m1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Markets) != 1 || res.Markets[0].Point.MarketID != "m1" || res.Markets[0].EventID != "e1" {
+		t.Fatalf("unexpected markets: %+v", res.Markets)
+	}
+	m := res.Markets[0]
+	if math.Abs(m.Features.PEvent-0.42) > 1e-9 || m.Features.Dispersion == 0 {
+		t.Fatalf("expected features with event peers, got %+v", m.Features)
+	}
+	// Every signal but CROSS_VENUE_DIVERGENCE, which needs a linked market.
+	if len(m.Signals) != len(polymarket.SignalThresholds)-1 {
+		t.Fatalf("expected every per-market signal to be emitted, got %d", len(m.Signals))
+	}
+	for _, s := range m.Signals {
+		if s.ObservedAt.IsZero() {
+			t.Fatalf("signal %s has no observation time", s.SignalID)
+		}
+	}
+
+	// An immediate second run is evaluated but not stored.
+	if _, err := p.Run(MarketRef{Venue: VenuePolymarket, MarketID: "m1"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(p.History.History(HistoryKey(VenuePolymarket, "m1"))); n != 1 {
+		t.Fatalf("expected 1 history point, got %d", n)
+	}
+}
+
+func TestPipeline_HistorySpacing(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &Pipeline{
+		Kalshi:  &fakeKalshi{markets: []model.Market{{Ticker: "EV-T1", EventTicker: "EV", YesBid: 30, YesAsk: 34}}},
+		History: NewMemoryHistory(10),
+		Now:     func() time.Time { return now },
+	}
+	ref := MarketRef{Venue: VenueKalshi, MarketID: "EV-T1"}
+
+	for _, step := range []time.Duration{0, time.Second, 5 * time.Second, time.Minute, 0} {
+		now = now.Add(step)
+		if _, err := p.Run(ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := p.History.History(HistoryKey(VenueKalshi, "EV-T1"))
+	if len(h) != 2 || h[1].UpdatedAt.Sub(h[0].UpdatedAt) != time.Minute+6*time.Second {
+		t.Fatalf("expected a burst to store one point per interval, got %+v", h)
+	}
+}
+
+func TestPipeline_PolymarketEvent(t *testing.T) {
+	p := &Pipeline{Gamma: gammaFixture()}
+
+	res, err := p.Run(MarketRef{Venue: VenuePolymarket, EventID: "e1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Markets) != 2 {
+		t.Fatalf("expected every market of the event, got %d", len(res.Markets))
+	}
+}
+
+func TestPipeline_Kalshi(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &Pipeline{
+		Kalshi: &fakeKalshi{markets: []model.Market{
+			{Ticker: "EV-T1", EventTicker: "EV", YesBid: 30, YesAsk: 34},
+			{Ticker: "EV-T2", EventTicker: "EV", YesBid: 60, YesAsk: 62},
+		}},
+		Now: func() time.Time { return now },
+	}
+
+	res, err := p.Run(MarketRef{Venue: VenueKalshi, MarketID: "EV-T1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Snapshot.Source != kalshi.SnapshotSource || !res.Snapshot.Timestamp.Equal(now) {
+		t.Fatalf("unexpected snapshot: %s %s", res.Snapshot.Source, res.Snapshot.Timestamp)
+	}
+	if got := res.Markets[0].Features.PEvent; math.Abs(got-0.32) > 1e-9 {
+		t.Fatalf("expected PEvent 0.32 from cents, got %v", got)
+	}
+}
+
+func TestPipeline_Books(t *testing.T) {
+	src := &fakeKalshi{
+		markets: []model.Market{
+			{Ticker: "EV-T1", EventTicker: "EV", YesBid: 30, YesAsk: 34},
+			{Ticker: "EV-T2", EventTicker: "EV", YesBid: 60, YesAsk: 62},
+		},
+		books: map[string]model.OrderBook{
+			"EV-T1": {Yes: [][2]int{{30, 1000}}, No: [][2]int{{66, 400}}},
+		},
+	}
+	p := &Pipeline{Kalshi: src, History: NewMemoryHistory(10), Books: true}
+
+	res, err := p.Run(MarketRef{Venue: VenueKalshi, EventID: "EV"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, m := range res.Markets {
+		switch m.Point.MarketID {
+		case "EV-T1":
+			if m.Features.Book == nil || m.Features.Book.Imbalance <= 0 {
+				t.Fatalf("expected book features with bids dominating, got %+v", m.Features.Book)
+			}
+		case "EV-T2":
+			if m.Features.Book != nil {
+				t.Fatalf("a market without a book is evaluated without one, got %+v", m.Features.Book)
+			}
+		}
+	}
+	if h := p.History.History(HistoryKey(VenueKalshi, "EV-T1")); len(h) != 1 || h[0].Book != nil {
+		t.Fatalf("books must not be kept in history, got %+v", h)
+	}
+	if _, logged := p.bookErrs.Load(VenueKalshi); !logged {
+		t.Fatal("expected the missing EV-T2 book to be logged")
+	}
+}
+
+func TestPipeline_MinQuality(t *testing.T) {
+	g := gammaFixture()
+	e := g.events["e1"]
+	e.Markets = append(e.Markets, polymarket.Market{ID: "m3", LiquidityNum: 100})
+	g.events["e1"] = e
+	p := &Pipeline{Gamma: g, History: NewMemoryHistory(10), MinQuality: 0.3}
+
+	res, err := p.Run(MarketRef{Venue: VenuePolymarket, EventID: "e1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, m := range res.Markets {
+		skip := m.Point.MarketID == "m3"
+		if m.Skipped != skip || skip != (len(m.Signals) == 0) {
+			t.Fatalf("%s: expected only the unquoted market skipped, got %+v", m.Point.MarketID, m)
+		}
+		if skip != (m.DataQuality() < p.MinQuality) {
+			t.Fatalf("%s: unexpected data quality %v", m.Point.MarketID, m.DataQuality())
+		}
+	}
+	if len(p.History.History(HistoryKey(VenuePolymarket, "m3"))) != 0 {
+		t.Fatal("skipped points must not be added to history")
+	}
+}
+
+func TestPipeline_CrossVenue(t *testing.T) {
+	links := map[string][]MarketRef{
+		"polymarket:m1": {{Venue: VenueKalshi, MarketID: "EV-T1"}},
+		"kalshi:EV-T1":  {{Venue: VenuePolymarket, MarketID: "m1"}},
+	}
+	p := &Pipeline{
+		Gamma: gammaFixture(),
+		Kalshi: &fakeKalshi{markets: []model.Market{
+			{Ticker: "EV-T1", EventTicker: "EV", YesBid: 30, YesAsk: 34},
+		}},
+		History:      NewMemoryHistory(10),
+		Counterparts: func(key string) []MarketRef { return links[key] },
+	}
+
+	// m1 has never been seen: it is snapshot from Gamma and stored.
+	k, err := p.Run(MarketRef{Venue: VenueKalshi, MarketID: "EV-T1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv := k.Markets[0].Features.CrossVenue; cv == nil || cv.Counterpart != "polymarket:m1" || math.Abs(cv.Gap+0.10) > 1e-9 {
+		t.Fatalf("expected the unseen counterpart to be fetched, got %+v", cv)
+	}
+	if n := len(p.History.History("polymarket:m1")); n != 1 {
+		t.Fatalf("expected the fetched counterpart to be stored, got %d points", n)
+	}
+
+	res, err := p.Run(MarketRef{Venue: VenuePolymarket, MarketID: "m1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cv := res.Markets[0].Features.CrossVenue
+	// Sell at 0.40 on Polymarket, buy at 0.34 on Kalshi paying its fee.
+	want := 0.40 - 0.34 - kalshi.TakerFee(0.34)
+	if cv == nil || cv.Counterpart != "kalshi:EV-T1" || math.Abs(cv.Gap-0.10) > 1e-9 || math.Abs(cv.AdjustedGap-want) > 1e-9 {
+		t.Fatalf("unexpected cross-venue features %+v", cv)
+	}
+	found := false
+	for _, s := range res.Markets[0].Signals {
+		found = found || (s.SignalID == "CROSS_VENUE_DIVERGENCE" && s.Value > 0)
+	}
+	if !found {
+		t.Fatalf("expected CROSS_VENUE_DIVERGENCE, got %+v", res.Markets[0].Signals)
+	}
+}
+
+func TestPipeline_CrossVenueWithoutSource(t *testing.T) {
+	p := &Pipeline{
+		Kalshi: &fakeKalshi{markets: []model.Market{
+			{Ticker: "EV-T1", EventTicker: "EV", YesBid: 30, YesAsk: 34},
+		}},
+		History: NewMemoryHistory(10),
+		Counterparts: func(string) []MarketRef {
+			return []MarketRef{{Venue: VenuePolymarket, MarketID: "m1"}}
+		},
+	}
+	k, err := p.Run(MarketRef{Venue: VenueKalshi, MarketID: "EV-T1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Markets[0].Features.CrossVenue != nil {
+		t.Fatal("a counterpart on an unconfigured venue cannot be compared")
+	}
+}
+
+func TestPipeline_Errors(t *testing.T) {
+	p := &Pipeline{Gamma: gammaFixture()}
+
+	cases := []struct {
+		ref  MarketRef
+		want error
+	}{
+		{MarketRef{Venue: "manifold", MarketID: "x"}, ErrInvalidRef},
+		{MarketRef{Venue: VenuePolymarket}, ErrInvalidRef},
+		{MarketRef{Venue: VenuePolymarket, MarketID: "missing"}, ErrMarketNotFound},
+		{MarketRef{Venue: VenuePolymarket, EventID: "e1", MarketID: "m9"}, ErrMarketNotFound},
+		{MarketRef{Venue: VenueKalshi, MarketID: "EV-T1"}, ErrVenueUnavailable},
+	}
+	for _, c := range cases {
+		if _, err := p.Run(c.ref); !errors.Is(err, c.want) {
+			t.Fatalf("%+v: expected %v, got %v", c.ref, c.want, err)
+		}
+	}
+}
+
+func TestMemoryHistory_Bounded(t *testing.T) {
+	h := NewMemoryHistory(3)
+	for i := 0; i < 5; i++ {
+		h.Append("k", polymarket.MarketPoint{MidPrice: float64(i)})
+	}
+	got := h.History("k")
+	if len(got) != 3 || got[0].MidPrice != 2 || got[2].MidPrice != 4 {
+		t.Fatalf("expected the 3 most recent points, got %+v", got)
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const (
+	// DefaultBatchWorkers bounds concurrent evaluations of one batch when
+	// PlanningHandler.BatchWorkers is unset.
+	DefaultBatchWorkers = 8
+
+	// DefaultMaxBatchItems bounds the items of one batch when
+	// PlanningHandler.MaxBatchItems is unset.
+	DefaultMaxBatchItems = 1000
+)
+
+// BatchEvaluateRequest is either a list of independent items, or one signal
+// snapshot (Params + Signals) evaluated against every intent in IntentIDs.
+type BatchEvaluateRequest struct {
+	Items []IntentEvaluateRequest `json:"items,omitempty"`
+
+	IntentIDs []string         `json:"intent_ids,omitempty"`
+	Params    map[string]any   `json:"params,omitempty"`
+	Signals   []SignalSnapshot `json:"signals,omitempty"`
+}
+
+// BatchEvaluateResponse holds one result per item, in request order.
+type BatchEvaluateResponse struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// BatchItemResult is either the IntentOutput of an item or its error.
+type BatchItemResult struct {
+	Index    int             `json:"index"`
+	IntentID string          `json:"intent_id"`
+	Output   json.RawMessage `json:"output,omitempty"`
+	Error    *APIError       `json:"error,omitempty"`
+}
+
+// items expands the request into the evaluations it describes.
+func (b BatchEvaluateRequest) items() ([]IntentEvaluateRequest, *APIError) {
+	snapshot := len(b.IntentIDs) > 0 || len(b.Signals) > 0 || b.Params != nil
+
+	switch {
+	case len(b.Items) > 0 && snapshot:
+		return nil, newAPIError(http.StatusBadRequest, CodeInvalidRequest,
+			"use either items or intent_ids with signals, not both")
+	case len(b.Items) > 0:
+		return b.Items, nil
+	case len(b.IntentIDs) > 0:
+		items := make([]IntentEvaluateRequest, 0, len(b.IntentIDs))
+		for _, id := range b.IntentIDs {
+			items = append(items, IntentEvaluateRequest{IntentID: id, Params: b.Params, Signals: b.Signals})
+		}
+		return items, nil
+	default:
+		return nil, newAPIError(http.StatusBadRequest, CodeInvalidRequest,
+			"batch must contain items or intent_ids")
+	}
+}
+
+// EvaluateBatchV1 is POST /v1/planning/intent/evaluate/batch. Items are
+// evaluated concurrently by at most BatchWorkers workers; a failing item does
+// not fail the batch, its error is reported in its result.
+func (h *PlanningHandler) EvaluateBatchV1(w http.ResponseWriter, r *http.Request) {
+	if e := negotiate(r, http.MethodPost); e != nil {
+		writeError(w, r, e)
+		return
+	}
+
+	var req BatchEvaluateRequest
+	if e := h.decodeBody(w, r, true, &req); e != nil {
+		writeError(w, r, e)
+		return
+	}
+
+	items, e := req.items()
+	if e != nil {
+		writeError(w, r, e)
+		return
+	}
+
+	maxItems := h.MaxBatchItems
+	if maxItems <= 0 {
+		maxItems = DefaultMaxBatchItems
+	}
+	if len(items) > maxItems {
+		writeError(w, r, newAPIError(http.StatusRequestEntityTooLarge, CodeBodyTooLarge,
+			fmt.Sprintf("batch has %d items, limit is %d", len(items), maxItems)))
+		return
+	}
+
+	// Params may be shared by every item of a snapshot batch: set explain
+	// before fanning out so workers only read them.
+	for i := range items {
+		items[i].Params = withExplain(r, items[i].Params)
+	}
+
+	resp := BatchEvaluateResponse{Results: h.evaluateBatch(r, items)}
+	for _, res := range resp.Results {
+		if res.Error != nil {
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *PlanningHandler) evaluateBatch(r *http.Request, items []IntentEvaluateRequest) []BatchItemResult {
+	results := make([]BatchItemResult, len(items))
+
+	workers := h.BatchWorkers
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = h.evaluateItem(r, i, items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func (h *PlanningHandler) evaluateItem(r *http.Request, i int, item IntentEvaluateRequest) BatchItemResult {
+	res := BatchItemResult{Index: i, IntentID: item.IntentID}
+
+	// The client went away: don't spend reasoner time on the rest.
+	if err := r.Context().Err(); err != nil {
+		res.Error = newAPIError(http.StatusServiceUnavailable, CodeCancelled, "batch cancelled")
+		return res
+	}
+
+	e := validateRequest(item)
+	if e == nil {
+		res.Output, e = h.evaluate(r, item)
+	}
+	if e != nil {
+		res.Error = e
+		res.Output = nil
+	}
+	return res
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+func postBatch(t *testing.T, router http.Handler, body string) BatchEvaluateResponse {
+	t.Helper()
+	req := newJSONRequest("/v1/planning/intent/evaluate/batch", body)
+	w := serve(router, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp BatchEvaluateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	return resp
+}
+
+func TestBatch_Items(t *testing.T) {
+	router := v1Router(t)
+
+	resp := postBatch(t, router, `{"items":[
+		{"intent_id":"interpret.regime_state","signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]},
+		{"intent_id":"interpret.weather","signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]},
+		{"intent_id":"interpret.regime_state","signals":[{"signal_id":"CONVICTION_SPIKE","value":0.8}]}
+	]}`)
+
+	if resp.Succeeded != 1 || resp.Failed != 2 || len(resp.Results) != 3 {
+		t.Fatalf("unexpected counts: %+v", resp)
+	}
+	for i, res := range resp.Results {
+		if res.Index != i {
+			t.Fatalf("results must keep request order, got index %d at %d", res.Index, i)
+		}
+	}
+	if resp.Results[0].Output == nil {
+		t.Fatal("expected output for first item")
+	}
+	if e := resp.Results[1].Error; e == nil || e.Code != CodeUnknownIntent {
+		t.Fatalf("expected unknown_intent, got %+v", e)
+	}
+	if e := resp.Results[2].Error; e == nil || e.Code != CodeMissingSignals {
+		t.Fatalf("expected missing_signals, got %+v", e)
+	}
+}
+
+func TestBatch_Snapshot(t *testing.T) {
+	resp := postBatch(t, v1Router(t), `{
+		"intent_ids":["interpret.regime_state","interpret.regime_state"],
+		"signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]
+	}`)
+
+	if resp.Succeeded != 2 {
+		t.Fatalf("expected both intents evaluated, got %+v", resp)
+	}
+	var out intents.IntentOutput
+	if err := json.Unmarshal(resp.Results[1].Output, &out); err != nil || out.Meta.IntentID != "interpret.regime_state" {
+		t.Fatalf("unexpected output: %s (%v)", resp.Results[1].Output, err)
+	}
+}
+
+func TestBatch_InvalidShape(t *testing.T) {
+	router := v1Router(t)
+	for _, body := range []string{
+		`{}`,
+		`{"items":[{"intent_id":"a.b","signals":[]}],"intent_ids":["a.b"]}`,
+	} {
+		w := serve(router, newJSONRequest("/v1/planning/intent/evaluate/batch", body))
+		if w.Code != http.StatusBadRequest || decodeError(t, w).Code != CodeInvalidRequest {
+			t.Fatalf("%s: expected invalid_request, got %d", body, w.Code)
+		}
+	}
+}
+
+// concurrencyReasoner records the highest number of concurrent evaluations.
+type concurrencyReasoner struct {
+	mu      sync.Mutex
+	current int
+	max     int
+	calls   atomic.Int64
+}
+
+func (c *concurrencyReasoner) Evaluate(id string, p map[string]any, s []reasoner.SignalInput) (intents.IntentOutput, error) {
+	c.mu.Lock()
+	c.current++
+	if c.current > c.max {
+		c.max = c.current
+	}
+	c.mu.Unlock()
+
+	time.Sleep(2 * time.Millisecond)
+	c.calls.Add(1)
+
+	c.mu.Lock()
+	c.current--
+	c.mu.Unlock()
+	return (&reasoner.SimpleReasoner{Version: "v1"}).Evaluate(id, p, s)
+}
+
+func TestBatch_BoundedWorkers(t *testing.T) {
+	r := &concurrencyReasoner{}
+	h := &PlanningHandler{Reasoner: r, BatchWorkers: 3}
+
+	ids := make([]string, 30)
+	for i := range ids {
+		ids[i] = `"interpret.regime_state"`
+	}
+	body := `{"intent_ids":[` + strings.Join(ids, ",") + `],"signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]}`
+
+	resp := postBatch(t, NewRouter(h, nil), body)
+	if resp.Succeeded != 30 || r.calls.Load() != 30 {
+		t.Fatalf("expected 30 evaluations, got %+v", resp)
+	}
+	if r.max > 3 {
+		t.Fatalf("expected at most 3 concurrent evaluations, saw %d", r.max)
+	}
+}
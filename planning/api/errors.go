@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Error codes returned in ErrorResponse. They are stable; messages are not.
+const (
+	CodeInvalidRequest       = "invalid_request"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeNotFound             = "not_found"
+	CodeUnknownIntent        = "unknown_intent"
+	CodeIntentNoRules        = "intent_not_implemented"
+	CodeMissingSignals       = "missing_signals"
+	CodeEvaluationFailed     = "evaluation_failed"
+	CodeInvalidOutput        = "invalid_output"
+	CodeCancelled            = "cancelled"
+	CodeUnknownMarket        = "unknown_market"
+	CodeVenueUnavailable     = "venue_unavailable"
+	CodeUpstreamError        = "upstream_error"
+	CodeDataQuality          = "data_quality"
+	CodeStreamUnavailable    = "stream_unavailable"
+	CodeInternal             = "internal_error"
+)
+
+// ErrorResponse is the JSON envelope of every /v1/ error.
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// APIError is a machine-readable error.
+type APIError struct {
+	Status    int    `json:"-"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (e *APIError) Error() string { return e.Message }
+
+func newAPIError(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// writeError writes e as an ErrorResponse, stamped with the request ID.
+func writeError(w http.ResponseWriter, r *http.Request, e *APIError) {
+	body := *e
+	body.RequestID = RequestIDFrom(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Error: body})
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"woodpecker/planning/reasoner"
+)
+
+// RulesStatusSource reports which ruleset is active and the last load error.
+type RulesStatusSource interface {
+	Status() reasoner.ReloadStatus
+}
+
+type AdminHandler struct {
+	Rules RulesStatusSource
+	// Counters, when set, are the runtime rule match counts of the served
+	// rulesets (reasoner.WithCounters).
+	Counters *reasoner.RuleCounters
+}
+
+// RulesStatusResponse is the reload status plus, when counted, how often
+// each intent was evaluated and each rule matched since startup.
+type RulesStatusResponse struct {
+	reasoner.ReloadStatus
+	Counters *reasoner.CounterSnapshot `json:"counters,omitempty"`
+}
+
+func (h *AdminHandler) RulesStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	resp := RulesStatusResponse{ReloadStatus: h.Rules.Status()}
+	if h.Counters != nil {
+		snap := h.Counters.Snapshot()
+		resp.Counters = &snap
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"woodpecker/planning/reasoner"
+)
+
+type staticStatus reasoner.ReloadStatus
+
+func (s staticStatus) Status() reasoner.ReloadStatus { return reasoner.ReloadStatus(s) }
+
+func TestAdminHandler_RulesStatus(t *testing.T) {
+	h := &AdminHandler{Rules: staticStatus{
+		Rulesets:  []reasoner.RulesetStatus{{RulesetID: "regime_state_v1", Version: "v7"}},
+		LastError: "bad operator",
+	}}
+
+	req := httptest.NewRequest(http.MethodGet, "/planning/admin/rules", nil)
+	w := httptest.NewRecorder()
+	h.RulesStatus(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var got reasoner.ReloadStatus
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(got.Rulesets) != 1 || got.Rulesets[0].Version != "v7" || got.LastError != "bad operator" {
+		t.Fatalf("unexpected status payload: %+v", got)
+	}
+}
+
+func TestAdminHandler_RulesCounters(t *testing.T) {
+	counters := reasoner.NewRuleCounters()
+	counters.Record("interpret.regime_state", []reasoner.Rule{{ID: "regime_shift_strong"}})
+	counters.Record("interpret.regime_state", nil)
+	h := &AdminHandler{Rules: staticStatus{}, Counters: counters}
+
+	req := httptest.NewRequest(http.MethodGet, "/planning/admin/rules", nil)
+	w := httptest.NewRecorder()
+	h.RulesStatus(w, req)
+
+	var got RulesStatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if got.Counters == nil || got.Counters.Evaluations["interpret.regime_state"] != 2 || got.Counters.Matches["regime_shift_strong"] != 1 {
+		t.Fatalf("unexpected counters: %+v", got.Counters)
+	}
+}
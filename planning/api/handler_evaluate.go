@@ -2,41 +2,244 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
+	"woodpecker/planning/intents"
 	"woodpecker/planning/reasoner"
+	"woodpecker/planning/stream"
 )
 
+// DefaultMaxBodyBytes limits request bodies when PlanningHandler.MaxBodyBytes is unset.
+const DefaultMaxBodyBytes = 1 << 20
+
 type PlanningHandler struct {
 	Reasoner reasoner.IntentReasoner
+
+	// Intents, when set, rejects unknown intent IDs with 404.
+	Intents *intents.Registry
+
+	// Signals, when set, enforces each intent's required signals (422) and
+	// flags signals the intent does not declare.
+	Signals intents.SignalMap
+
+	// Validation controls how outputs are checked against
+	// intent_output.schema.json before they are written.
+	Validation ValidationMode
+
+	// MaxBodyBytes limits request bodies; 0 means DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+
+	// BatchWorkers and MaxBatchItems bound batch evaluations; 0 means
+	// DefaultBatchWorkers / DefaultMaxBatchItems.
+	BatchWorkers  int
+	MaxBatchItems int
+
+	// Markets, when set, serves /planning/market/evaluate.
+	Markets MarketPipeline
+
+	// Stream, when set, serves /planning/stream; Heartbeat defaults to
+	// DefaultHeartbeat.
+	Stream    *stream.Hub
+	Heartbeat time.Duration
+}
+
+// ValidationMode selects what happens to outputs that violate the schema.
+type ValidationMode string
+
+const (
+	// ValidationLog logs violations and still returns the output. It is the default.
+	ValidationLog ValidationMode = "log"
+	// ValidationStrict refuses invalid outputs with 500.
+	ValidationStrict ValidationMode = "strict"
+	// ValidationOff skips schema validation.
+	ValidationOff ValidationMode = "off"
+)
+
+// ParseValidationMode accepts "log", "strict", "off" or "" (log).
+func ParseValidationMode(s string) (ValidationMode, error) {
+	switch m := ValidationMode(s); m {
+	case "":
+		return ValidationLog, nil
+	case ValidationLog, ValidationStrict, ValidationOff:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown validation mode '%s'", s)
+	}
 }
 
+// EvaluateIntent is the legacy (unversioned) endpoint: plain-text errors and
+// lenient decoding. New clients should use EvaluateIntentV1.
 func (h *PlanningHandler) EvaluateIntent(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	var req IntentEvaluateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	req, apiErr := h.decodeRequest(w, r, false)
+	if apiErr == nil {
+		var body []byte
+		if body, apiErr = h.evaluate(r, req); apiErr == nil {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(body)
+			return
+		}
+	}
+
+	if apiErr.Code == CodeMissingSignals {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(MissingSignalsResponse{
+			Error:    apiErr.Message,
+			IntentID: req.IntentID,
+			Missing:  apiErr.Details.(MissingSignalsDetails).Missing,
+		})
 		return
 	}
+	http.Error(w, apiErr.Message, apiErr.Status)
+}
+
+// decodeRequest reads a single evaluation request.
+func (h *PlanningHandler) decodeRequest(w http.ResponseWriter, r *http.Request, strict bool) (IntentEvaluateRequest, *APIError) {
+	var req IntentEvaluateRequest
+	if e := h.decodeBody(w, r, strict, &req); e != nil {
+		return req, e
+	}
+	req.Params = withExplain(r, req.Params)
+	return req, nil
+}
+
+// decodeBody reads the request body into dst within the size limit. Strict
+// decoding rejects unknown fields and trailing data.
+func (h *PlanningHandler) decodeBody(w http.ResponseWriter, r *http.Request, strict bool, dst any) *APIError {
+	limit := h.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	err := dec.Decode(dst)
+	if err == nil && strict && dec.More() {
+		err = errors.New("unexpected data after JSON body")
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return newAPIError(http.StatusRequestEntityTooLarge, CodeBodyTooLarge,
+			fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+	case err != nil && strict:
+		return newAPIError(http.StatusBadRequest, CodeInvalidRequest, "invalid request body: "+err.Error())
+	case err != nil:
+		return newAPIError(http.StatusBadRequest, CodeInvalidRequest, "invalid request body")
+	}
+	return nil
+}
+
+// withExplain sets params["explain"] when ?explain=true / ?debug=true request
+// a structured reasoning trace.
+func withExplain(r *http.Request, params map[string]any) map[string]any {
+	if !explainQuery(r) {
+		return params
+	}
+	if params == nil {
+		params = map[string]any{}
+	}
+	params["explain"] = true
+	return params
+}
+
+// evaluate runs a decoded request through the reasoner and returns the
+// encoded output. Reasoner errors are logged, not returned to the client.
+func (h *PlanningHandler) evaluate(r *http.Request, req IntentEvaluateRequest) ([]byte, *APIError) {
+	if h.Intents != nil {
+		if _, err := h.Intents.Lookup(req.IntentID); err != nil {
+			return nil, unknownIntent(req.IntentID)
+		}
+	}
 
 	signalInputs := make([]reasoner.SignalInput, 0, len(req.Signals))
+	signalIDs := make([]string, 0, len(req.Signals))
 	for _, s := range req.Signals {
-		signalInputs = append(signalInputs, reasoner.SignalInput{
+		in := reasoner.SignalInput{
 			SignalID: s.SignalID,
 			Value:    s.Value,
-		})
+		}
+		if s.ObservedAt != nil {
+			in.ObservedAt = *s.ObservedAt
+		}
+		signalInputs = append(signalInputs, in)
+		signalIDs = append(signalIDs, s.SignalID)
+	}
+
+	var check intents.SignalCheck
+	if h.Signals != nil {
+		check = h.Signals.Check(req.IntentID, signalIDs)
+		if len(check.Missing) > 0 {
+			e := newAPIError(http.StatusUnprocessableEntity, CodeMissingSignals, check.Err(req.IntentID).Error())
+			e.Details = MissingSignalsDetails{IntentID: req.IntentID, Missing: check.Missing}
+			return nil, e
+		}
 	}
 
 	result, err := h.Reasoner.Evaluate(req.IntentID, req.Params, signalInputs)
+	if errors.Is(err, intents.ErrUnknownIntent) {
+		return nil, unknownIntent(req.IntentID)
+	}
+	if errors.Is(err, reasoner.ErrNoRules) {
+		e := newAPIError(http.StatusNotImplemented, CodeIntentNoRules, fmt.Sprintf("intent '%s' has no rules yet", req.IntentID))
+		e.Details = map[string]string{"intent_id": req.IntentID}
+		return nil, e
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		log.Printf("❌ [%s] %s: %v", RequestIDFrom(r.Context()), req.IntentID, err)
+		return nil, newAPIError(http.StatusInternalServerError, CodeEvaluationFailed, "intent evaluation failed")
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(result)
+	result.UnexpectedSignals = check.Unexpected
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, CodeInternal, "failed to encode output")
+	}
+
+	// 🔒 OUTPUT CONTRACT (intent_output.schema.json)
+	if h.Validation != ValidationOff {
+		if err := intents.ValidateOutputJSON(body); err != nil {
+			log.Printf("⚠️ [%s] %s: %v", RequestIDFrom(r.Context()), req.IntentID, err)
+			if h.Validation == ValidationStrict {
+				e := newAPIError(http.StatusInternalServerError, CodeInvalidOutput, "intent output does not match schema")
+				var serr *intents.SchemaError
+				if errors.As(err, &serr) {
+					e.Details = serr.Violations
+				}
+				return nil, e
+			}
+		}
+	}
+
+	return append(body, '\n'), nil
+}
+
+func unknownIntent(id string) *APIError {
+	e := newAPIError(http.StatusNotFound, CodeUnknownIntent, fmt.Sprintf("unknown intent '%s'", id))
+	e.Details = map[string]string{"intent_id": id}
+	return e
+}
+
+func explainQuery(r *http.Request) bool {
+	q := r.URL.Query()
+	for _, key := range []string{"explain", "debug"} {
+		if b, err := strconv.ParseBool(q.Get(key)); err == nil && b {
+			return true
+		}
+	}
+	return false
 }
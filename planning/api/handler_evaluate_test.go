@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"woodpecker/planning/intents"
 	"woodpecker/planning/reasoner"
 )
 
@@ -33,3 +34,76 @@ func TestEvaluateIntentHandler_OK(t *testing.T) {
 		t.Fatalf("expected status 200, got %d", w.Code)
 	}
 }
+
+func TestEvaluateIntentHandler_ExplainQuery(t *testing.T) {
+	r := &reasoner.RuleBasedReasoner{
+		Version: "v1",
+		Rules: []reasoner.Rule{{
+			ID:     "weak",
+			Intent: "interpret.regime_state",
+			When: reasoner.ConditionBlock{All: []reasoner.Condition{
+				{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5},
+			}},
+			Then: reasoner.RuleAction{Status: "weak_signal", ConfidenceBoost: 0.2},
+		}},
+	}
+	h := &PlanningHandler{Reasoner: r}
+
+	b, _ := json.Marshal(IntentEvaluateRequest{
+		IntentID: "interpret.regime_state",
+		Signals:  []SignalSnapshot{{SignalID: "REGIME_SHIFT", Value: 0.6}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/planning/intent/evaluate?explain=true", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+
+	h.EvaluateIntent(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var out intents.IntentOutput
+	if err := json.NewDecoder(w.Body).Decode(&out); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if out.Trace == nil || !out.Trace.Rules[0].Matched {
+		t.Fatalf("expected trace with matched rule, got %+v", out.Trace)
+	}
+}
+
+type stubReasoner struct{ out intents.IntentOutput }
+
+func (s stubReasoner) Evaluate(string, map[string]any, []reasoner.SignalInput) (intents.IntentOutput, error) {
+	return s.out, nil
+}
+
+func TestEvaluateIntentHandler_StrictValidation(t *testing.T) {
+	b, _ := json.Marshal(IntentEvaluateRequest{
+		IntentID: "interpret.regime_state",
+		Signals:  []SignalSnapshot{{SignalID: "REGIME_SHIFT", Value: 0.6}},
+	})
+
+	// Valid output from a real reasoner passes.
+	h := &PlanningHandler{Reasoner: &reasoner.SimpleReasoner{Version: "v1"}, Validation: ValidationStrict}
+	w := httptest.NewRecorder()
+	h.EvaluateIntent(w, httptest.NewRequest(http.MethodPost, "/planning/intent/evaluate", bytes.NewReader(b)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Invalid output is refused in strict mode...
+	invalid := stubReasoner{out: intents.IntentOutput{Status: "maybe"}}
+	h = &PlanningHandler{Reasoner: invalid, Validation: ValidationStrict}
+	w = httptest.NewRecorder()
+	h.EvaluateIntent(w, httptest.NewRequest(http.MethodPost, "/planning/intent/evaluate", bytes.NewReader(b)))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+
+	// ...and only logged by default.
+	h = &PlanningHandler{Reasoner: invalid}
+	w = httptest.NewRecorder()
+	h.EvaluateIntent(w, httptest.NewRequest(http.MethodPost, "/planning/intent/evaluate", bytes.NewReader(b)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+}
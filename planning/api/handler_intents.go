@@ -0,0 +1,28 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"woodpecker/planning/intents"
+)
+
+// IntentListResponse is returned by GET /planning/intents.
+type IntentListResponse struct {
+	Intents []intents.Intent `json:"intents"`
+}
+
+func (h *PlanningHandler) ListIntents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := IntentListResponse{Intents: []intents.Intent{}}
+	if h.Intents != nil {
+		resp.Intents = h.Intents.All()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
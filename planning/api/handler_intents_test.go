@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+func testRegistry(t *testing.T) *intents.Registry {
+	t.Helper()
+	reg, err := intents.NewRegistry([]intents.Intent{
+		{ID: "interpret.regime_state", Category: "interpret", Description: "Detect structural shifts in expectations"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func TestEvaluateIntentHandler_UnknownIntent(t *testing.T) {
+	h := &PlanningHandler{
+		Reasoner: &reasoner.SimpleReasoner{Version: "v1"},
+		Intents:  testRegistry(t),
+	}
+
+	b, _ := json.Marshal(IntentEvaluateRequest{
+		IntentID: "interpret.weather",
+		Signals:  []SignalSnapshot{{SignalID: "REGIME_SHIFT", Value: 0.8}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/planning/intent/evaluate", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+
+	h.EvaluateIntent(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestListIntentsHandler(t *testing.T) {
+	h := &PlanningHandler{Intents: testRegistry(t)}
+
+	req := httptest.NewRequest(http.MethodGet, "/planning/intents", nil)
+	w := httptest.NewRecorder()
+
+	h.ListIntents(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp IntentListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(resp.Intents) != 1 || resp.Intents[0].Category != "interpret" {
+		t.Fatalf("unexpected intents: %+v", resp.Intents)
+	}
+}
+
+func TestEvaluateIntentHandler_MissingSignals(t *testing.T) {
+	h := &PlanningHandler{
+		Reasoner: &reasoner.SimpleReasoner{Version: "v1"},
+		Signals: intents.SignalMap{
+			"interpret.regime_state": {
+				Required: []string{"REGIME_SHIFT", "PROBABILITY_ACCELERATION"},
+				Optional: []string{"CONVICTION_SPIKE"},
+			},
+		},
+	}
+
+	post := func(signals ...SignalSnapshot) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(IntentEvaluateRequest{IntentID: "interpret.regime_state", Signals: signals})
+		req := httptest.NewRequest(http.MethodPost, "/planning/intent/evaluate", bytes.NewReader(b))
+		w := httptest.NewRecorder()
+		h.EvaluateIntent(w, req)
+		return w
+	}
+
+	w := post(SignalSnapshot{SignalID: "REGIME_SHIFT", Value: 0.8})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d", w.Code)
+	}
+	var missing MissingSignalsResponse
+	if err := json.NewDecoder(w.Body).Decode(&missing); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(missing.Missing) != 1 || missing.Missing[0] != "PROBABILITY_ACCELERATION" {
+		t.Fatalf("unexpected missing signals: %v", missing.Missing)
+	}
+
+	w = post(
+		SignalSnapshot{SignalID: "REGIME_SHIFT", Value: 0.8},
+		SignalSnapshot{SignalID: "PROBABILITY_ACCELERATION", Value: 0.7},
+		SignalSnapshot{SignalID: "DIVERGENCE_ALERT", Value: 0.4},
+	)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var out intents.IntentOutput
+	if err := json.NewDecoder(w.Body).Decode(&out); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(out.UnexpectedSignals) != 1 || out.UnexpectedSignals[0] != "DIVERGENCE_ALERT" {
+		t.Fatalf("expected DIVERGENCE_ALERT to be flagged, got %v", out.UnexpectedSignals)
+	}
+}
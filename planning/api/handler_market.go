@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/pipeline"
+	"woodpecker/planning/guardrails"
+)
+
+// MarketPipeline computes reasoner inputs for a venue market reference.
+type MarketPipeline interface {
+	Run(ref pipeline.MarketRef) (pipeline.Result, error)
+}
+
+// MarketEvaluateRequest evaluates one intent on a market, or on every market
+// of an event when only event_id is given.
+type MarketEvaluateRequest struct {
+	Venue    pipeline.Venue `json:"venue"`
+	MarketID string         `json:"market_id,omitempty"`
+	EventID  string         `json:"event_id,omitempty"`
+	IntentID string         `json:"intent_id"`
+	Params   map[string]any `json:"params,omitempty"`
+}
+
+// MarketEvaluateResponse returns features, signals and output per market.
+type MarketEvaluateResponse struct {
+	SnapshotID string             `json:"snapshot_id"`
+	Source     string             `json:"source"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Markets    []MarketEvaluation `json:"markets"`
+}
+
+// MarketEvaluation is the pipeline result of one market with the intent
+// evaluated on its signals. Output and Error are mutually exclusive. Markets
+// below the pipeline's minimum data quality are Skipped: they are not
+// evaluated and carry a data_quality error.
+type MarketEvaluation struct {
+	Venue    pipeline.Venue           `json:"venue"`
+	EventID  string                   `json:"event_id"`
+	Market   polymarket.MarketPoint   `json:"market"`
+	Features polymarket.FeatureVector `json:"features"`
+	Signals  []SignalSnapshot         `json:"signals"`
+	Skipped  bool                     `json:"skipped,omitempty"`
+
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  *APIError       `json:"error,omitempty"`
+}
+
+// EvaluateMarket is POST /planning/market/evaluate (and its /v1/ alias):
+// market ID in, features + signals + IntentOutput out.
+func (h *PlanningHandler) EvaluateMarket(w http.ResponseWriter, r *http.Request) {
+	if e := negotiate(r, http.MethodPost); e != nil {
+		writeError(w, r, e)
+		return
+	}
+	if h.Markets == nil {
+		writeError(w, r, newAPIError(http.StatusNotImplemented, CodeVenueUnavailable, "market pipeline is not configured"))
+		return
+	}
+
+	var req MarketEvaluateRequest
+	if e := h.decodeBody(w, r, true, &req); e != nil {
+		writeError(w, r, e)
+		return
+	}
+	if req.IntentID == "" {
+		writeError(w, r, newAPIError(http.StatusBadRequest, CodeInvalidRequest, "intent_id is required"))
+		return
+	}
+	if h.Intents != nil {
+		if _, err := h.Intents.Lookup(req.IntentID); err != nil {
+			writeError(w, r, unknownIntent(req.IntentID))
+			return
+		}
+	}
+	req.Params = withExplain(r, req.Params)
+
+	// 1️⃣ Snapshot → features → signals
+	res, err := h.Markets.Run(pipeline.MarketRef{Venue: req.Venue, MarketID: req.MarketID, EventID: req.EventID})
+	if err != nil {
+		writeError(w, r, pipelineError(r, err))
+		return
+	}
+
+	// 2️⃣ Intent evaluation per market (same path as /planning/intent/evaluate)
+	resp := MarketEvaluateResponse{
+		SnapshotID: res.Snapshot.SnapshotID,
+		Source:     res.Snapshot.Source,
+		Timestamp:  res.Snapshot.Timestamp,
+		Markets:    make([]MarketEvaluation, 0, len(res.Markets)),
+	}
+	for _, m := range res.Markets {
+		eval := MarketEvaluation{
+			Venue:    m.Venue,
+			EventID:  m.EventID,
+			Market:   m.Point,
+			Features: m.Features,
+			Signals:  h.intentSignals(req.IntentID, m),
+		}
+		if m.Skipped {
+			eval.Skipped = true
+			eval.Error = dataQualityError(m)
+			resp.Markets = append(resp.Markets, eval)
+			continue
+		}
+		eval.Output, eval.Error = h.evaluate(r, IntentEvaluateRequest{
+			IntentID: req.IntentID,
+			Params:   guardrails.WithDataQuality(req.Params, m.DataQuality()),
+			Signals:  eval.Signals,
+		})
+		resp.Markets = append(resp.Markets, eval)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// intentSignals keeps the signals the intent declares; without a signal
+// contract every computed signal is passed on.
+func (h *PlanningHandler) intentSignals(intentID string, m pipeline.MarketSignals) []SignalSnapshot {
+	out := make([]SignalSnapshot, 0, len(m.Signals))
+	for _, s := range m.Signals {
+		if !h.Signals.Accepts(intentID, s.SignalID) {
+			continue
+		}
+		observedAt := s.ObservedAt
+		out = append(out, SignalSnapshot{SignalID: s.SignalID, Value: s.Value, ObservedAt: &observedAt})
+	}
+	return out
+}
+
+// dataQualityError explains why a market was skipped.
+func dataQualityError(m pipeline.MarketSignals) *APIError {
+	msg := fmt.Sprintf("market data quality %.2f is below the minimum", m.DataQuality())
+	if q := m.Point.Quality; q != nil && len(q.Issues) > 0 {
+		msg += ": " + strings.Join(q.Issues, ", ")
+	}
+	return newAPIError(http.StatusUnprocessableEntity, CodeDataQuality, msg)
+}
+
+func pipelineError(r *http.Request, err error) *APIError {
+	switch {
+	case errors.Is(err, pipeline.ErrInvalidRef):
+		return newAPIError(http.StatusBadRequest, CodeInvalidRequest, err.Error())
+	case errors.Is(err, pipeline.ErrMarketNotFound):
+		return newAPIError(http.StatusNotFound, CodeUnknownMarket, err.Error())
+	case errors.Is(err, pipeline.ErrVenueUnavailable):
+		return newAPIError(http.StatusNotImplemented, CodeVenueUnavailable, err.Error())
+	default:
+		log.Printf("❌ [%s] market pipeline: %v", RequestIDFrom(r.Context()), err)
+		return newAPIError(http.StatusBadGateway, CodeUpstreamError, "market data unavailable from venue")
+	}
+}
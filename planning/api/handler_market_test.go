@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/pipeline"
+	"woodpecker/planning/guardrails"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+type stubPipeline struct {
+	res pipeline.Result
+	err error
+}
+
+func (s stubPipeline) Run(pipeline.MarketRef) (pipeline.Result, error) { return s.res, s.err }
+
+func marketResult() pipeline.Result {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	signals := polymarket.BuildAllSignals(polymarket.FeatureVector{ProbabilityMomentum: 0.5, ImpliedConfidence: 0.8})
+	for i := range signals {
+		signals[i].ObservedAt = now
+	}
+	return pipeline.Result{
+		Snapshot: polymarket.Snapshot{SnapshotID: "snap1", Source: "polymarket-gamma", Timestamp: now},
+		Markets: []pipeline.MarketSignals{{
+			Venue:   pipeline.VenuePolymarket,
+			EventID: "e1",
+			Point:   polymarket.MarketPoint{MarketID: "m1", MidPrice: 0.42},
+			Signals: signals,
+		}},
+	}
+}
+
+func TestEvaluateMarket_OK(t *testing.T) {
+	h := &PlanningHandler{
+		Reasoner: &reasoner.SimpleReasoner{Version: "v1"},
+		Intents:  testRegistry(t),
+		Signals: intents.SignalMap{
+			"interpret.regime_state": {Required: []string{"REGIME_SHIFT"}, Optional: []string{"CONVICTION_SPIKE"}},
+		},
+		Markets: stubPipeline{res: marketResult()},
+	}
+
+	w := serve(NewRouter(h, nil), newJSONRequest("/planning/market/evaluate",
+		`{"venue":"polymarket","market_id":"m1","intent_id":"interpret.regime_state"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MarketEvaluateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if resp.SnapshotID != "snap1" || len(resp.Markets) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	m := resp.Markets[0]
+	if m.Error != nil || m.Output == nil {
+		t.Fatalf("expected output, got error %+v", m.Error)
+	}
+	if len(m.Signals) != 2 {
+		t.Fatalf("expected only the intent's declared signals, got %+v", m.Signals)
+	}
+
+	var out intents.IntentOutput
+	if err := json.Unmarshal(m.Output, &out); err != nil || out.Meta.IntentID != "interpret.regime_state" {
+		t.Fatalf("unexpected output: %s (%v)", m.Output, err)
+	}
+	if len(out.UnexpectedSignals) != 0 {
+		t.Fatalf("filtered signals must not be flagged, got %v", out.UnexpectedSignals)
+	}
+}
+
+func TestEvaluateMarket_SkippedForDataQuality(t *testing.T) {
+	res := marketResult()
+	res.Markets[0].Skipped = true
+	res.Markets[0].Signals = nil
+	res.Markets[0].Point.Quality = &polymarket.Quality{Score: 0.1, Issues: []string{polymarket.IssueStaleQuote}}
+	r := &concurrencyReasoner{}
+	h := &PlanningHandler{Reasoner: r, Markets: stubPipeline{res: res}}
+
+	w := serve(NewRouter(h, nil), newJSONRequest("/planning/market/evaluate",
+		`{"venue":"polymarket","market_id":"m1","intent_id":"interpret.regime_state"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MarketEvaluateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	m := resp.Markets[0]
+	if !m.Skipped || m.Output != nil || m.Error == nil || m.Error.Code != CodeDataQuality {
+		t.Fatalf("expected a skipped evaluation, got %+v", m)
+	}
+	if n := r.calls.Load(); n != 0 {
+		t.Fatalf("the reasoner must not be called for a skipped market, got %d calls", n)
+	}
+}
+
+func TestEvaluateMarket_ClientCannotRaiseDataQuality(t *testing.T) {
+	res := marketResult()
+	res.Markets[0].Point.Quality = &polymarket.Quality{Score: 0.1, Issues: []string{polymarket.IssueStaleQuote}}
+	below := 0.3
+	h := &PlanningHandler{
+		Reasoner: &guardrails.Reasoner{
+			Next: &reasoner.SimpleReasoner{Version: "v1"},
+			Engine: &guardrails.Engine{Config: guardrails.Config{Policies: []guardrails.Policy{
+				{ID: "block_low_data_quality", When: guardrails.Conditions{DataQualityBelow: &below}, Block: true},
+			}}},
+		},
+		Markets: stubPipeline{res: res},
+	}
+
+	w := serve(NewRouter(h, nil), newJSONRequest("/planning/market/evaluate",
+		`{"venue":"polymarket","market_id":"m1","intent_id":"interpret.regime_state","params":{"data_quality":1}}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MarketEvaluateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	var out intents.IntentOutput
+	if err := json.Unmarshal(resp.Markets[0].Output, &out); err != nil {
+		t.Fatalf("unexpected output: %s (%v)", resp.Markets[0].Output, err)
+	}
+	if !out.Guardrails.Blocked {
+		t.Fatalf("a client data_quality must not lift the guardrail, got %+v", out.Guardrails)
+	}
+}
+
+func TestEvaluateMarket_ShippedOpportunityRules(t *testing.T) {
+	rulesets, err := reasoner.LoadRulesets("../rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := reasoner.NewRulesetRouter("v1", rulesets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := intents.LoadRegistry("../intents/intents.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signalMap, err := intents.LoadSignalMap("../intents/signal_intent_map.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A persistent cross-venue gap on a market backed by conviction.
+	res := marketResult()
+	res.Markets[0].Signals = polymarket.BuildAllSignals(polymarket.FeatureVector{
+		ImpliedConfidence: 0.7,
+		CrossVenue:        &polymarket.CrossVenueFeatures{AdjustedGap: 0.2, Persistence: 1},
+	})
+	h := &PlanningHandler{
+		Reasoner: router,
+		Intents:  registry,
+		Signals:  signalMap,
+		Markets:  stubPipeline{res: res},
+	}
+
+	w := serve(NewRouter(h, nil), newJSONRequest("/planning/market/evaluate",
+		`{"venue":"polymarket","market_id":"m1","intent_id":"evaluate.opportunity"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MarketEvaluateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	var out intents.IntentOutput
+	if err := json.Unmarshal(resp.Markets[0].Output, &out); err != nil {
+		t.Fatalf("unexpected output: %s (%v) %+v", resp.Markets[0].Output, err, resp.Markets[0].Error)
+	}
+	if out.Status != intents.StatusStrongSignal || out.Meta.RulesetID != "opportunity_v1" {
+		t.Fatalf("expected the cross-venue rules to fire, got %s from %s", out.Status, out.Meta.RulesetID)
+	}
+	used := false
+	for _, s := range out.Signals {
+		used = used || (s.SignalID == "CROSS_VENUE_DIVERGENCE" && s.Weight > 0)
+	}
+	if !used {
+		t.Fatalf("expected CROSS_VENUE_DIVERGENCE to be credited, got %+v", out.Signals)
+	}
+}
+
+func TestEvaluateMarket_Errors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("%w: nope", pipeline.ErrMarketNotFound), http.StatusNotFound, CodeUnknownMarket},
+		{fmt.Errorf("%w: venue", pipeline.ErrInvalidRef), http.StatusBadRequest, CodeInvalidRequest},
+		{fmt.Errorf("%w: kalshi", pipeline.ErrVenueUnavailable), http.StatusNotImplemented, CodeVenueUnavailable},
+		{fmt.Errorf("gamma api error: status 503"), http.StatusBadGateway, CodeUpstreamError},
+	}
+	for _, c := range cases {
+		h := &PlanningHandler{Reasoner: &reasoner.SimpleReasoner{Version: "v1"}, Markets: stubPipeline{err: c.err}}
+		w := serve(NewRouter(h, nil), newJSONRequest("/v1/planning/market/evaluate",
+			`{"venue":"polymarket","market_id":"m1","intent_id":"interpret.regime_state"}`))
+		if w.Code != c.status || decodeError(t, w).Code != c.code {
+			t.Fatalf("%v: expected %d %s, got %d", c.err, c.status, c.code, w.Code)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/stream"
+)
+
+// DefaultHeartbeat is how often an idle stream sends a keep-alive comment.
+const DefaultHeartbeat = 15 * time.Second
+
+// StreamEvents is GET /planning/stream: a server-sent events stream of intent
+// evaluation changes.
+//
+// Query filters (comma-separated, repeatable): intent (glob, e.g. trigger.*),
+// status, market. Replay starts after the Last-Event-ID header (or the
+// last_event_id query parameter).
+func (h *PlanningHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, newAPIError(http.StatusMethodNotAllowed, CodeMethodNotAllowed,
+			fmt.Sprintf("method %s not allowed, use GET", r.Method)))
+		return
+	}
+	if !acceptsEventStream(r.Header.Values("Accept")) {
+		writeError(w, r, newAPIError(http.StatusNotAcceptable, CodeNotAcceptable,
+			"stream is only available as text/event-stream"))
+		return
+	}
+	if h.Stream == nil {
+		writeError(w, r, newAPIError(http.StatusNotImplemented, CodeStreamUnavailable, "evaluation stream is not configured"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, newAPIError(http.StatusInternalServerError, CodeInternal, "streaming unsupported"))
+		return
+	}
+
+	filter, lastID, e := streamQuery(r)
+	if e != nil {
+		writeError(w, r, e)
+		return
+	}
+
+	replay, sub := h.Stream.Subscribe(filter, lastID)
+	defer h.Stream.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, ev := range replay {
+		writeEvent(w, ev)
+	}
+	flusher.Flush()
+
+	heartbeat := h.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind: the client reconnects with Last-Event-ID.
+				return
+			}
+			writeEvent(w, ev)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev stream.Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: evaluation\ndata: %s\n\n", ev.ID, data)
+}
+
+func streamQuery(r *http.Request) (stream.Filter, uint64, *APIError) {
+	q := r.URL.Query()
+
+	var f stream.Filter
+	f.Intents = splitQuery(q["intent"])
+	f.MarketIDs = splitQuery(q["market"])
+	for _, s := range splitQuery(q["status"]) {
+		status := intents.IntentStatus(s)
+		if !status.IsValid() {
+			return f, 0, newAPIError(http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("unknown status '%s'", s))
+		}
+		f.Statuses = append(f.Statuses, status)
+	}
+
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = q.Get("last_event_id")
+	}
+	var lastID uint64
+	if raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return f, 0, newAPIError(http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("invalid last event id '%s'", raw))
+		}
+		lastID = id
+	}
+	return f, lastID, nil
+}
+
+func splitQuery(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// acceptsEventStream reports whether the Accept header allows text/event-stream.
+func acceptsEventStream(values []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+				continue
+			}
+			switch mt {
+			case "text/event-stream", "text/*", "*/*":
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/stream"
+)
+
+// readEvent reads one SSE event, skipping comments.
+func readEvent(t *testing.T, r *bufio.Reader) (id string, ev stream.Event) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev); err != nil {
+				t.Fatalf("invalid event data: %v", err)
+			}
+		case line == "" && id != "":
+			return id, ev
+		}
+	}
+}
+
+func TestStreamEvents_ReplayAndLive(t *testing.T) {
+	hub := stream.NewHub(10)
+	hub.Publish(stream.Event{IntentID: "trigger.regime_change", Status: intents.StatusWeakSignal})
+	hub.Publish(stream.Event{IntentID: "observe.market_state", Status: intents.StatusStrongSignal})
+	hub.Publish(stream.Event{IntentID: "trigger.regime_change", Status: intents.StatusStrongSignal})
+
+	srv := httptest.NewServer(NewRouter(&PlanningHandler{Stream: hub}, nil))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/planning/stream?intent=trigger.*", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	id, ev := readEvent(t, r)
+	if id != "3" || ev.Status != intents.StatusStrongSignal {
+		t.Fatalf("expected replay of event 3 only, got %s %+v", id, ev)
+	}
+
+	hub.Publish(stream.Event{IntentID: "observe.market_state", Status: intents.StatusWeakSignal})
+	hub.Publish(stream.Event{IntentID: "trigger.regime_change", Status: intents.StatusModerateSignal})
+
+	id, ev = readEvent(t, r)
+	if id != "5" || ev.Status != intents.StatusModerateSignal {
+		t.Fatalf("expected live event 5, got %s %+v", id, ev)
+	}
+}
+
+func TestStreamEvents_BadRequests(t *testing.T) {
+	router := NewRouter(&PlanningHandler{Stream: stream.NewHub(1)}, nil)
+
+	w := serve(router, httptest.NewRequest(http.MethodGet, "/planning/stream?status=maybe", nil))
+	if w.Code != http.StatusBadRequest || decodeError(t, w).Code != CodeInvalidRequest {
+		t.Fatalf("expected 400 for unknown status, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/planning/stream", nil)
+	req.Header.Set("Accept", "application/json")
+	if w := serve(router, req); w.Code != http.StatusNotAcceptable {
+		t.Fatalf("expected 406, got %d", w.Code)
+	}
+
+	w = serve(NewRouter(&PlanningHandler{}, nil), httptest.NewRequest(http.MethodGet, "/planning/stream", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("expected 501 without a hub, got %d", w.Code)
+	}
+}
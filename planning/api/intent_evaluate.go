@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 // IntentEvaluateRequest is the input to the Planning Layer.
 // It is intentionally generic and future-proof.
 type IntentEvaluateRequest struct {
@@ -11,6 +13,20 @@ type IntentEvaluateRequest struct {
 // SignalSnapshot represents a point-in-time signal value
 // coming from the Signal Layer (already computed).
 type SignalSnapshot struct {
-	SignalID string  `json:"signal_id"`
-	Value    float64 `json:"value"` // expected normalized 0..1
+	SignalID   string     `json:"signal_id"`
+	Value      float64    `json:"value"`                 // expected normalized 0..1
+	ObservedAt *time.Time `json:"observed_at,omitempty"` // used for staleness guardrails
+}
+
+// MissingSignalsResponse is returned with 422 when required signals are absent.
+type MissingSignalsResponse struct {
+	Error    string   `json:"error"`
+	IntentID string   `json:"intent_id"`
+	Missing  []string `json:"missing_signals"`
+}
+
+// MissingSignalsDetails are the details of a missing_signals error.
+type MissingSignalsDetails struct {
+	IntentID string   `json:"intent_id"`
+	Missing  []string `json:"missing_signals"`
 }
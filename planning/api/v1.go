@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// EvaluateIntentV1 is POST /v1/planning/intent/evaluate. Unlike the legacy
+// endpoint it decodes strictly, negotiates content types and always answers
+// errors with an ErrorResponse.
+func (h *PlanningHandler) EvaluateIntentV1(w http.ResponseWriter, r *http.Request) {
+	if e := negotiate(r, http.MethodPost); e != nil {
+		writeError(w, r, e)
+		return
+	}
+
+	req, e := h.decodeRequest(w, r, true)
+	if e == nil {
+		e = validateRequest(req)
+	}
+	if e != nil {
+		writeError(w, r, e)
+		return
+	}
+
+	body, e := h.evaluate(r, req)
+	if e != nil {
+		writeError(w, r, e)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// ListIntentsV1 is GET /v1/planning/intents.
+func (h *PlanningHandler) ListIntentsV1(w http.ResponseWriter, r *http.Request) {
+	if e := negotiate(r, http.MethodGet); e != nil {
+		writeError(w, r, e)
+		return
+	}
+	h.ListIntents(w, r)
+}
+
+// RulesStatusV1 is GET /v1/planning/admin/rules.
+func (h *AdminHandler) RulesStatusV1(w http.ResponseWriter, r *http.Request) {
+	if e := negotiate(r, http.MethodGet); e != nil {
+		writeError(w, r, e)
+		return
+	}
+	h.RulesStatus(w, r)
+}
+
+// validateRequest checks what strict decoding cannot.
+func validateRequest(req IntentEvaluateRequest) *APIError {
+	if req.IntentID == "" {
+		return newAPIError(http.StatusBadRequest, CodeInvalidRequest, "intent_id is required")
+	}
+	if len(req.Signals) == 0 {
+		return newAPIError(http.StatusBadRequest, CodeInvalidRequest, "signals must not be empty")
+	}
+	for i, s := range req.Signals {
+		if s.SignalID == "" {
+			return newAPIError(http.StatusBadRequest, CodeInvalidRequest,
+				fmt.Sprintf("signals[%d].signal_id is required", i))
+		}
+		if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+			return newAPIError(http.StatusBadRequest, CodeInvalidRequest,
+				fmt.Sprintf("signals[%d].value must be a finite number", i))
+		}
+	}
+	return nil
+}
+
+// negotiate checks the method, the request Content-Type (for bodies) and
+// that the client accepts JSON.
+func negotiate(r *http.Request, method string) *APIError {
+	if r.Method != method {
+		return newAPIError(http.StatusMethodNotAllowed, CodeMethodNotAllowed,
+			fmt.Sprintf("method %s not allowed, use %s", r.Method, method))
+	}
+
+	if method == http.MethodPost {
+		mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mt != "application/json" {
+			return newAPIError(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType,
+				"Content-Type must be application/json")
+		}
+	}
+
+	if !acceptsJSON(r.Header.Values("Accept")) {
+		return newAPIError(http.StatusNotAcceptable, CodeNotAcceptable,
+			"responses are only available as application/json")
+	}
+	return nil
+}
+
+// acceptsJSON reports whether the Accept header allows application/json.
+// A missing header accepts anything.
+func acceptsJSON(values []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+				continue
+			}
+			switch mt {
+			case "application/json", "application/*", "*/*":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewRouter serves the /v1/ API and the legacy unversioned routes, all with
+// request-ID propagation. admin may be nil.
+func NewRouter(h *PlanningHandler, admin *AdminHandler) http.Handler {
+	mux := http.NewServeMux()
+
+	// /v1/
+	mux.HandleFunc("/v1/planning/intent/evaluate", h.EvaluateIntentV1)
+	mux.HandleFunc("/v1/planning/intent/evaluate/batch", h.EvaluateBatchV1)
+	mux.HandleFunc("/v1/planning/intents", h.ListIntentsV1)
+	mux.HandleFunc("/v1/planning/market/evaluate", h.EvaluateMarket)
+	mux.HandleFunc("/v1/planning/stream", h.StreamEvents)
+	if admin != nil {
+		mux.HandleFunc("/v1/planning/admin/rules", admin.RulesStatusV1)
+	}
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, newAPIError(http.StatusNotFound, CodeNotFound,
+			fmt.Sprintf("no such endpoint: %s", r.URL.Path)))
+	})
+
+	// Legacy
+	mux.HandleFunc("/planning/intent/evaluate", h.EvaluateIntent)
+	mux.HandleFunc("/planning/intents", h.ListIntents)
+	mux.HandleFunc("/planning/market/evaluate", h.EvaluateMarket)
+	mux.HandleFunc("/planning/stream", h.StreamEvents)
+	if admin != nil {
+		mux.HandleFunc("/planning/admin/rules", admin.RulesStatus)
+	}
+
+	return WithRequestID(mux)
+}
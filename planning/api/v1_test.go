@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+func v1Router(t *testing.T) http.Handler {
+	t.Helper()
+	h := &PlanningHandler{
+		Reasoner: &reasoner.SimpleReasoner{Version: "v1"},
+		Intents:  testRegistry(t),
+		Signals: intents.SignalMap{
+			"interpret.regime_state": {Required: []string{"REGIME_SHIFT"}},
+		},
+		MaxBodyBytes: 512,
+	}
+	return NewRouter(h, nil)
+}
+
+func newJSONRequest(path, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func serve(router http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func postV1(router http.Handler, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := newJSONRequest("/v1/planning/intent/evaluate", body)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return serve(router, req)
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) APIError {
+	t.Helper()
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("expected JSON error envelope, got %q: %v", w.Body.String(), err)
+	}
+	return resp.Error
+}
+
+func TestV1_EvaluateOK(t *testing.T) {
+	w := postV1(v1Router(t), `{"intent_id":"interpret.regime_state","signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]}`,
+		map[string]string{RequestIDHeader: "client-123"})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get(RequestIDHeader); got != "client-123" {
+		t.Fatalf("expected request id to be propagated, got %q", got)
+	}
+}
+
+func TestV1_Errors(t *testing.T) {
+	router := v1Router(t)
+	valid := `{"intent_id":"interpret.regime_state","signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]}`
+
+	cases := []struct {
+		name   string
+		body   string
+		header map[string]string
+		status int
+		code   string
+	}{
+		{"unknown field", `{"intent_id":"interpret.regime_state","signal":[]}`, nil, http.StatusBadRequest, CodeInvalidRequest},
+		{"trailing data", valid + `{}`, nil, http.StatusBadRequest, CodeInvalidRequest},
+		{"missing intent", `{"signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]}`, nil, http.StatusBadRequest, CodeInvalidRequest},
+		{"too large", `{"intent_id":"` + strings.Repeat("x", 1024) + `"}`, nil, http.StatusRequestEntityTooLarge, CodeBodyTooLarge},
+		{"content type", valid, map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType},
+		{"accept", valid, map[string]string{"Accept": "text/html, application/json;q=0"}, http.StatusNotAcceptable, CodeNotAcceptable},
+		{"unknown intent", `{"intent_id":"interpret.weather","signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]}`, nil, http.StatusNotFound, CodeUnknownIntent},
+		{"missing signals", `{"intent_id":"interpret.regime_state","signals":[{"signal_id":"CONVICTION_SPIKE","value":0.8}]}`, nil, http.StatusUnprocessableEntity, CodeMissingSignals},
+	}
+
+	for _, c := range cases {
+		w := postV1(router, c.body, c.header)
+		if w.Code != c.status {
+			t.Fatalf("%s: expected status %d, got %d: %s", c.name, c.status, w.Code, w.Body.String())
+		}
+		e := decodeError(t, w)
+		if e.Code != c.code {
+			t.Fatalf("%s: expected code %s, got %+v", c.name, c.code, e)
+		}
+		if e.RequestID == "" || e.RequestID != w.Header().Get(RequestIDHeader) {
+			t.Fatalf("%s: expected generated request id in body and header, got %q / %q",
+				c.name, e.RequestID, w.Header().Get(RequestIDHeader))
+		}
+	}
+}
+
+func TestV1_ReasonerErrorsAreNotEchoed(t *testing.T) {
+	h := &PlanningHandler{Reasoner: failingReasoner{}}
+	w := postV1(NewRouter(h, nil), `{"intent_id":"interpret.regime_state","signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]}`, nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+	if e := decodeError(t, w); e.Code != CodeEvaluationFailed || strings.Contains(e.Message, "secret") {
+		t.Fatalf("unexpected error: %+v", e)
+	}
+}
+
+func TestV1_MethodAndNotFound(t *testing.T) {
+	router := v1Router(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/planning/intent/evaluate", nil))
+	if w.Code != http.StatusMethodNotAllowed || decodeError(t, w).Code != CodeMethodNotAllowed {
+		t.Fatalf("expected JSON 405, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/nope", nil))
+	if w.Code != http.StatusNotFound || decodeError(t, w).Code != CodeNotFound {
+		t.Fatalf("expected JSON 404, got %d", w.Code)
+	}
+}
+
+type failingReasoner struct{}
+
+func (failingReasoner) Evaluate(string, map[string]any, []reasoner.SignalInput) (intents.IntentOutput, error) {
+	return intents.IntentOutput{}, errSecret
+}
+
+var errSecret = errors.New("secret ruleset path /etc/rules")
+
+func TestV1_IntentWithoutRules(t *testing.T) {
+	router, err := reasoner.NewRulesetRouter("v1", []reasoner.Ruleset{{RulesetID: "other", Rules: []reasoner.Rule{{
+		ID:     "r",
+		Intent: "interpret.other",
+		When:   reasoner.ConditionBlock{All: []reasoner.Condition{{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5}}},
+		Then:   reasoner.RuleAction{Status: "weak_signal"},
+	}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &PlanningHandler{Reasoner: router, Intents: testRegistry(t)}
+
+	w := postV1(NewRouter(h, nil), `{"intent_id":"interpret.regime_state","signals":[{"signal_id":"REGIME_SHIFT","value":0.8}]}`, nil)
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("expected status 501, got %d: %s", w.Code, w.Body.String())
+	}
+	if e := decodeError(t, w); e.Code != CodeIntentNoRules {
+		t.Fatalf("expected %s, got %+v", CodeIntentNoRules, e)
+	}
+}
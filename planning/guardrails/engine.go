@@ -0,0 +1,166 @@
+package guardrails
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+// Engine applies guardrail policies to intent outputs.
+type Engine struct {
+	Config Config
+
+	// Now is used for signal staleness; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Reasoner wraps any IntentReasoner and applies the engine to its outputs.
+type Reasoner struct {
+	Next   reasoner.IntentReasoner
+	Engine *Engine
+}
+
+func (r *Reasoner) Evaluate(
+	intentID string,
+	params map[string]any,
+	signals []reasoner.SignalInput,
+) (intents.IntentOutput, error) {
+	out, err := r.Next.Evaluate(intentID, params, signals)
+	if err != nil {
+		return out, err
+	}
+	r.Engine.Apply(&out, params, signals)
+	return out, nil
+}
+
+// Apply evaluates every policy against the output as produced by the
+// reasoner, then applies the effects of the fired ones:
+//   - confidence is capped at the lowest fired cap
+//   - human confirmation is required if any fired policy (or the default) asks for it
+//   - a blocking policy zeroes confidence and downgrades the status to low_confidence
+func (e *Engine) Apply(out *intents.IntentOutput, params map[string]any, signals []reasoner.SignalInput) {
+	g := intents.Guardrails{
+		HumanConfirmationRequired: e.Config.DefaultHumanConfirmation,
+	}
+
+	var (
+		capped  = false
+		capAt   = 1.0
+		blockBy = ""
+	)
+
+	for _, p := range e.Config.Policies {
+		if !e.fires(p, out, params, signals) {
+			continue
+		}
+		g.PoliciesFired = append(g.PoliciesFired, p.ID)
+
+		if p.CapConfidence != nil && *p.CapConfidence < capAt {
+			capAt = *p.CapConfidence
+			capped = true
+		}
+		if p.RequireHumanConfirmation {
+			g.HumanConfirmationRequired = true
+		}
+		if p.Block && blockBy == "" {
+			blockBy = p.ID
+		}
+	}
+
+	if capped && out.Confidence > capAt {
+		out.Confidence = capAt
+		g.ConfidenceCapped = true
+	}
+
+	if blockBy != "" {
+		g.Blocked = true
+		g.HumanConfirmationRequired = true
+		out.Summary = fmt.Sprintf("Blocked by guardrail policy '%s' (reasoner status was %s). %s",
+			blockBy, out.Status, out.Summary)
+		out.Status = intents.StatusLowConfidence
+		out.Confidence = 0
+	}
+
+	out.Guardrails = &g
+}
+
+func (e *Engine) fires(
+	p Policy,
+	out *intents.IntentOutput,
+	params map[string]any,
+	signals []reasoner.SignalInput,
+) bool {
+	if !matchesIntent(p.Intents, out.Meta.IntentID) {
+		return false
+	}
+
+	w := p.When
+	if len(w.Statuses) > 0 && !contains(w.Statuses, string(out.Status)) {
+		return false
+	}
+	if w.MinConfidence != nil && out.Confidence < *w.MinConfidence {
+		return false
+	}
+	if w.MaxConfidence != nil && out.Confidence > *w.MaxConfidence {
+		return false
+	}
+	if w.SignalAgeOver > 0 && !e.anyStale(signals, w.SignalAgeOver) {
+		return false
+	}
+	if w.DataQualityBelow != nil {
+		q, ok := dataQuality(params)
+		if !ok || q >= *w.DataQualityBelow {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Engine) anyStale(signals []reasoner.SignalInput, maxAge time.Duration) bool {
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	t := now()
+	for _, s := range signals {
+		if !s.ObservedAt.IsZero() && t.Sub(s.ObservedAt) > maxAge {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesIntent(patterns []string, intentID string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, intentID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func dataQuality(params map[string]any) (float64, bool) {
+	switch v := params[ParamDataQuality].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func contains(xs []string, x string) bool {
+	for _, v := range xs {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package guardrails
+
+import (
+	"testing"
+	"time"
+
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+func ptr(f float64) *float64 { return &f }
+
+type fixedReasoner struct{ out intents.IntentOutput }
+
+func (f fixedReasoner) Evaluate(intentID string, _ map[string]any, _ []reasoner.SignalInput) (intents.IntentOutput, error) {
+	out := f.out
+	out.Meta.IntentID = intentID
+	return out, nil
+}
+
+func TestLoadConfig_Shipped(t *testing.T) {
+	if _, err := LoadConfig("policies.yaml"); err != nil {
+		t.Fatalf("shipped policies must load: %v", err)
+	}
+}
+
+func TestConfig_ValidateRejectsNoEffect(t *testing.T) {
+	cfg := Config{Policies: []Policy{{ID: "noop", Intents: []string{"trigger.*"}}}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for policy without effect")
+	}
+}
+
+func TestEngine_CapAndConfirm(t *testing.T) {
+	engine := &Engine{Config: Config{Policies: []Policy{
+		{ID: "cap_triggers", Intents: []string{"trigger.*"}, CapConfidence: ptr(0.6)},
+		{ID: "confirm_strong", When: Conditions{Statuses: []string{"strong_signal"}}, RequireHumanConfirmation: true},
+	}}}
+	r := &Reasoner{
+		Next:   fixedReasoner{out: intents.IntentOutput{Status: intents.StatusStrongSignal, Confidence: 0.9}},
+		Engine: engine,
+	}
+
+	out, err := r.Evaluate("trigger.regime_change", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Confidence != 0.6 || !out.Guardrails.ConfidenceCapped {
+		t.Fatalf("expected confidence capped at 0.6, got %v (%+v)", out.Confidence, out.Guardrails)
+	}
+	if !out.Guardrails.HumanConfirmationRequired {
+		t.Fatal("expected human confirmation for strong_signal")
+	}
+	if len(out.Guardrails.PoliciesFired) != 2 {
+		t.Fatalf("expected 2 policies fired, got %v", out.Guardrails.PoliciesFired)
+	}
+
+	out, _ = r.Evaluate("interpret.regime_state", nil, nil)
+	if out.Confidence != 0.9 || out.Guardrails.ConfidenceCapped {
+		t.Fatalf("cap must only apply to trigger intents, got %v", out.Confidence)
+	}
+}
+
+func TestEngine_StaleSignals(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	engine := &Engine{
+		Config: Config{Policies: []Policy{
+			{ID: "stale", When: Conditions{SignalAgeOver: 15 * time.Minute}, RequireHumanConfirmation: true},
+		}},
+		Now: func() time.Time { return now },
+	}
+
+	fresh := []reasoner.SignalInput{{SignalID: "REGIME_SHIFT", ObservedAt: now.Add(-time.Minute)}}
+	out := intents.IntentOutput{Status: intents.StatusWeakSignal}
+	engine.Apply(&out, nil, fresh)
+	if out.Guardrails.HumanConfirmationRequired {
+		t.Fatal("fresh signals must not require confirmation")
+	}
+
+	stale := append(fresh, reasoner.SignalInput{SignalID: "CONVICTION_SPIKE", ObservedAt: now.Add(-time.Hour)})
+	engine.Apply(&out, nil, stale)
+	if !out.Guardrails.HumanConfirmationRequired {
+		t.Fatal("stale signals must require confirmation")
+	}
+}
+
+func TestEngine_BlockLowDataQuality(t *testing.T) {
+	engine := &Engine{Config: Config{Policies: []Policy{
+		{ID: "block_low_quality", When: Conditions{DataQualityBelow: ptr(0.3)}, Block: true},
+	}}}
+
+	out := intents.IntentOutput{Status: intents.StatusStrongSignal, Confidence: 0.8, Summary: "ok"}
+	engine.Apply(&out, map[string]any{ParamDataQuality: 0.1}, nil)
+
+	if !out.Guardrails.Blocked || out.Status != intents.StatusLowConfidence || out.Confidence != 0 {
+		t.Fatalf("expected blocked output, got %s %v %+v", out.Status, out.Confidence, out.Guardrails)
+	}
+
+	out = intents.IntentOutput{Status: intents.StatusStrongSignal, Confidence: 0.8}
+	engine.Apply(&out, nil, nil)
+	if out.Guardrails.Blocked {
+		t.Fatal("missing data quality must not block")
+	}
+}
+
+func TestWithDataQuality(t *testing.T) {
+	params := map[string]any{"explain": true}
+	got := WithDataQuality(params, 0.4)
+	if got[ParamDataQuality] != 0.4 || got["explain"] != true {
+		t.Fatalf("unexpected params %v", got)
+	}
+	if _, ok := params[ParamDataQuality]; ok {
+		t.Fatal("params must not be modified")
+	}
+	if got := WithDataQuality(map[string]any{ParamDataQuality: 0.1}, 0.9); got[ParamDataQuality] != 0.1 {
+		t.Fatalf("a lower data_quality param must be kept, got %v", got)
+	}
+	if got := WithDataQuality(map[string]any{ParamDataQuality: 1}, 0.2); got[ParamDataQuality] != 0.2 {
+		t.Fatalf("a data_quality param above the computed score must be replaced, got %v", got)
+	}
+	if got := WithDataQuality(map[string]any{ParamDataQuality: "high"}, 0.2); got[ParamDataQuality] != 0.2 {
+		t.Fatalf("a malformed data_quality param must be replaced, got %v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package guardrails
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParamDataQuality is the evaluation param carrying a 0..1 data quality score
+// for the snapshot the signals were computed from.
+const ParamDataQuality = "data_quality"
+
+// WithDataQuality returns a copy of params with ParamDataQuality set to score.
+// A caller may lower the score it passes but never raise it above the
+// computed one, so data quality policies cannot be bypassed.
+func WithDataQuality(params map[string]any, score float64) map[string]any {
+	out := make(map[string]any, len(params)+1)
+	for k, v := range params {
+		out[k] = v
+	}
+	if q, ok := dataQuality(params); !ok || q > score {
+		out[ParamDataQuality] = score
+	}
+	return out
+}
+
+// Config is a guardrails policy file.
+type Config struct {
+	Version string `yaml:"version"`
+
+	// DefaultHumanConfirmation applies when no fired policy requires confirmation.
+	DefaultHumanConfirmation bool `yaml:"default_human_confirmation"`
+
+	Policies []Policy `yaml:"policies"`
+}
+
+// Policy is a single guardrail. It fires when the output's intent matches one
+// of Intents (glob patterns, e.g. "trigger.*"; empty matches all) and every
+// condition in When holds.
+type Policy struct {
+	ID      string     `yaml:"id"`
+	Intents []string   `yaml:"intents,omitempty"`
+	When    Conditions `yaml:"when,omitempty"`
+
+	// Effects
+	CapConfidence            *float64 `yaml:"cap_confidence,omitempty"`
+	RequireHumanConfirmation bool     `yaml:"require_human_confirmation,omitempty"`
+	Block                    bool     `yaml:"block,omitempty"`
+}
+
+// Conditions are ANDed; unset conditions always hold.
+type Conditions struct {
+	Statuses []string `yaml:"statuses,omitempty"`
+
+	MinConfidence *float64 `yaml:"min_confidence,omitempty"` // confidence >= value
+	MaxConfidence *float64 `yaml:"max_confidence,omitempty"` // confidence <= value
+
+	// SignalAgeOver holds when any timestamped signal is older than this.
+	SignalAgeOver time.Duration `yaml:"signal_age_over,omitempty"`
+
+	// DataQualityBelow holds when the data_quality param is below this.
+	DataQualityBelow *float64 `yaml:"data_quality_below,omitempty"`
+}
+
+// LoadConfig reads and validates a guardrails policy file.
+func LoadConfig(p string) (Config, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks every policy.
+func (c Config) Validate() error {
+	ids := make(map[string]bool)
+	for i, p := range c.Policies {
+		if p.ID == "" {
+			return fmt.Errorf("policy[%d]: id must not be empty", i)
+		}
+		if ids[p.ID] {
+			return fmt.Errorf("policy[%d]: duplicate id '%s'", i, p.ID)
+		}
+		ids[p.ID] = true
+
+		if err := validatePolicy(p); err != nil {
+			return fmt.Errorf("policy[%d] (%s): %w", i, p.ID, err)
+		}
+	}
+	return nil
+}
+
+func validatePolicy(p Policy) error {
+	for _, pattern := range p.Intents {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid intent pattern '%s'", pattern)
+		}
+	}
+
+	if p.CapConfidence != nil && (*p.CapConfidence < 0 || *p.CapConfidence > 1) {
+		return fmt.Errorf("cap_confidence must be between 0 and 1")
+	}
+	if p.CapConfidence == nil && !p.RequireHumanConfirmation && !p.Block {
+		return fmt.Errorf("policy must cap confidence, require human confirmation or block")
+	}
+
+	if p.When.SignalAgeOver < 0 {
+		return fmt.Errorf("signal_age_over must be >= 0")
+	}
+	return nil
+}
@@ -17,12 +17,23 @@ type IntentOutput struct {
 	Evaluation map[string]any `json:"evaluation,omitempty"`
 
 	Guardrails *Guardrails `json:"guardrails,omitempty"`
+
+	// UnexpectedSignals lists provided signals the intent does not declare.
+	UnexpectedSignals []string `json:"unexpected_signals,omitempty"`
+
+	// Trace is only present when explicitly requested (explain/debug).
+	Trace *Trace `json:"trace,omitempty"`
 }
 
 type Meta struct {
 	IntentID  string    `json:"intent_id"`
 	Timestamp time.Time `json:"timestamp"`
 	Version   string    `json:"version"`
+
+	// Ruleset provenance, set when the output was produced by declarative rules.
+	RulesetID      string `json:"ruleset_id,omitempty"`
+	RulesetVersion string `json:"ruleset_version,omitempty"`
+	RulesetHash    string `json:"ruleset_hash,omitempty"`
 }
 
 // IntentStatus is the single source of truth for intent evaluation states.
@@ -37,6 +48,30 @@ const (
 	StatusStrongSignal   IntentStatus = "strong_signal"
 )
 
+// Statuses lists every IntentStatus, weakest first.
+var Statuses = []IntentStatus{
+	StatusNotTriggered,
+	StatusLowConfidence,
+	StatusWeakSignal,
+	StatusModerateSignal,
+	StatusStrongSignal,
+}
+
+// Rank orders statuses from weakest (0) to strongest; unknown statuses are -1.
+func (s IntentStatus) Rank() int {
+	for i, v := range Statuses {
+		if s == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsValid reports whether s is one of Statuses.
+func (s IntentStatus) IsValid() bool {
+	return s.Rank() >= 0
+}
+
 type SignalUsage struct {
 	SignalID string  `json:"signal_id"`
 	Value    float64 `json:"value"`
@@ -51,9 +86,15 @@ type Reasoning struct {
 type ReasoningStep struct {
 	Step        int    `json:"step"`
 	Description string `json:"description"`
+	RuleID      string `json:"rule_id,omitempty"`
 }
 
 type Guardrails struct {
 	HumanConfirmationRequired bool `json:"human_confirmation_required,omitempty"`
 	ConfidenceCapped          bool `json:"confidence_capped,omitempty"`
+
+	// Blocked means the output must not be acted on (e.g. low data quality).
+	Blocked bool `json:"blocked,omitempty"`
+	// PoliciesFired lists the guardrail policies that applied, in config order.
+	PoliciesFired []string `json:"policies_fired,omitempty"`
 }
@@ -1,7 +1,81 @@
 package intents
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrUnknownIntent is returned when an intent ID is not in the registry.
+var ErrUnknownIntent = errors.New("unknown intent")
+
 type Intent struct {
 	ID          string `json:"id"`
 	Category    string `json:"category"`
 	Description string `json:"description"`
 }
+
+// Registry is the catalog of intents the Planning Layer accepts (intents.json).
+type Registry struct {
+	intents []Intent
+	byID    map[string]Intent
+}
+
+// LoadRegistry reads and validates an intents.json file.
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Intents []Intent `json:"intents"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return NewRegistry(file.Intents)
+}
+
+// NewRegistry validates intents and indexes them by ID. An intent ID is
+// "<category>.<name>" and its category must match the prefix.
+func NewRegistry(list []Intent) (*Registry, error) {
+	r := &Registry{byID: make(map[string]Intent, len(list))}
+
+	for i, in := range list {
+		if in.ID == "" {
+			return nil, fmt.Errorf("intents[%d]: id must not be empty", i)
+		}
+		if _, dup := r.byID[in.ID]; dup {
+			return nil, fmt.Errorf("intents[%d]: duplicate id '%s'", i, in.ID)
+		}
+		prefix, _, ok := strings.Cut(in.ID, ".")
+		if !ok || prefix != in.Category {
+			return nil, fmt.Errorf("intents[%d] (%s): category '%s' does not match id prefix", i, in.ID, in.Category)
+		}
+
+		r.intents = append(r.intents, in)
+		r.byID[in.ID] = in
+	}
+
+	return r, nil
+}
+
+// Lookup returns the intent with the given ID or an error wrapping ErrUnknownIntent.
+func (r *Registry) Lookup(id string) (Intent, error) {
+	in, ok := r.byID[id]
+	if !ok {
+		return Intent{}, fmt.Errorf("%w '%s'", ErrUnknownIntent, id)
+	}
+	return in, nil
+}
+
+// All returns the registered intents in file order.
+func (r *Registry) All() []Intent {
+	out := make([]Intent, len(r.intents))
+	copy(out, r.intents)
+	return out
+}
@@ -0,0 +1,32 @@
+package intents
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadRegistry_Shipped(t *testing.T) {
+	reg, err := LoadRegistry("intents.json")
+	if err != nil {
+		t.Fatalf("shipped intents.json must load: %v", err)
+	}
+
+	in, err := reg.Lookup("trigger.regime_change")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if in.Category != "trigger" {
+		t.Fatalf("expected trigger category, got %s", in.Category)
+	}
+
+	if _, err := reg.Lookup("interpret.weather"); !errors.Is(err, ErrUnknownIntent) {
+		t.Fatalf("expected ErrUnknownIntent, got %v", err)
+	}
+}
+
+func TestNewRegistry_CategoryMismatch(t *testing.T) {
+	_, err := NewRegistry([]Intent{{ID: "observe.market_state", Category: "trigger"}})
+	if err == nil {
+		t.Fatal("expected error for category not matching id prefix")
+	}
+}
@@ -0,0 +1,365 @@
+package intents
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+//go:embed intent_output.schema.json
+var intentOutputSchemaJSON []byte
+
+// outputSchema is intent_output.schema.json, compiled once.
+var outputSchema = mustCompileSchema(intentOutputSchemaJSON)
+
+// SchemaViolation is a single place where a document does not satisfy the schema.
+type SchemaViolation struct {
+	Path    string `json:"path"` // e.g. "signals[0].weight"; "" is the root
+	Message string `json:"message"`
+}
+
+// SchemaError lists every violation found in a document.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		path := v.Path
+		if path == "" {
+			path = "(root)"
+		}
+		parts = append(parts, path+": "+v.Message)
+	}
+	return "intent output does not match schema: " + strings.Join(parts, "; ")
+}
+
+// ValidateSchema validates the JSON encoding of o against intent_output.schema.json.
+func (o IntentOutput) ValidateSchema() error {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return ValidateOutputJSON(data)
+}
+
+// ValidateOutputJSON validates an encoded IntentOutput against
+// intent_output.schema.json. Violations are returned as *SchemaError.
+func ValidateOutputJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+
+	var violations []SchemaViolation
+	outputSchema.validate("", doc, &violations)
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────
+// JSON Schema (draft-07 subset)
+// ─────────────────────────────────────────────
+
+// jsonSchema supports the keywords intent_output.schema.json uses. Compiling a
+// schema with any other keyword fails, so a schema edit can never be silently
+// ignored by the validator.
+type jsonSchema struct {
+	Types      []string
+	Required   []string
+	Properties map[string]*jsonSchema
+
+	// AdditionalProperties is nil when extra properties are allowed.
+	AdditionalProperties *jsonSchema
+	NoAdditional         bool
+
+	Items     *jsonSchema
+	Enum      []any
+	Minimum   *float64
+	Maximum   *float64
+	MinLength int
+	Format    string
+}
+
+var annotationKeywords = map[string]bool{
+	"$schema":     true,
+	"title":       true,
+	"description": true,
+}
+
+func mustCompileSchema(data []byte) *jsonSchema {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		panic(fmt.Sprintf("intent output schema: %v", err))
+	}
+	s, err := compileSchema("", raw)
+	if err != nil {
+		panic(fmt.Sprintf("intent output schema: %v", err))
+	}
+	return s
+}
+
+func compileSchema(path string, raw map[string]any) (*jsonSchema, error) {
+	s := &jsonSchema{}
+
+	for key, v := range raw {
+		var err error
+		switch key {
+		case "type":
+			s.Types, err = schemaTypes(v)
+		case "required":
+			s.Required, err = stringList(v)
+		case "properties":
+			props, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: properties must be an object", schemaPath(path))
+			}
+			s.Properties = make(map[string]*jsonSchema, len(props))
+			for name, p := range props {
+				sub, ok := p.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("%s: property '%s' must be a schema", schemaPath(path), name)
+				}
+				if s.Properties[name], err = compileSchema(joinPath(path, name), sub); err != nil {
+					return nil, err
+				}
+			}
+		case "additionalProperties":
+			switch ap := v.(type) {
+			case bool:
+				s.NoAdditional = !ap
+			case map[string]any:
+				s.AdditionalProperties, err = compileSchema(path+".*", ap)
+			default:
+				err = fmt.Errorf("%s: additionalProperties must be a boolean or a schema", schemaPath(path))
+			}
+		case "items":
+			sub, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: items must be a schema", schemaPath(path))
+			}
+			s.Items, err = compileSchema(path+"[]", sub)
+		case "enum":
+			list, ok := v.([]any)
+			if !ok || len(list) == 0 {
+				return nil, fmt.Errorf("%s: enum must be a non-empty array", schemaPath(path))
+			}
+			s.Enum = list
+		case "minimum", "maximum":
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s: %s must be a number", schemaPath(path), key)
+			}
+			if key == "minimum" {
+				s.Minimum = &f
+			} else {
+				s.Maximum = &f
+			}
+		case "minLength":
+			n, ok := v.(float64)
+			if !ok || n < 0 || n != math.Trunc(n) {
+				return nil, fmt.Errorf("%s: minLength must be a non-negative integer", schemaPath(path))
+			}
+			s.MinLength = int(n)
+		case "format":
+			if s.Format, _ = v.(string); s.Format != "date-time" {
+				return nil, fmt.Errorf("%s: unsupported format %v", schemaPath(path), v)
+			}
+		default:
+			if !annotationKeywords[key] {
+				return nil, fmt.Errorf("%s: unsupported keyword '%s'", schemaPath(path), key)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func schemaTypes(v any) ([]string, error) {
+	if t, ok := v.(string); ok {
+		v = []any{t}
+	}
+	types, err := stringList(v)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		switch t {
+		case "object", "array", "string", "number", "integer", "boolean", "null":
+		default:
+			return nil, fmt.Errorf("unknown type '%s'", t)
+		}
+	}
+	return types, nil
+}
+
+func stringList(v any) ([]string, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an array of strings, got %T", v)
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an array of strings, got %T item", item)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (s *jsonSchema) validate(path string, v any, out *[]SchemaViolation) {
+	fail := func(format string, args ...any) {
+		*out = append(*out, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.Types) > 0 && !s.typeMatches(v) {
+		fail("expected %s, got %s", strings.Join(s.Types, " or "), jsonType(v))
+		return
+	}
+
+	if len(s.Enum) > 0 && !enumContains(s.Enum, v) {
+		fail("value %v is not one of %v", v, s.Enum)
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		f, _ := val.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("%v is less than minimum %v", f, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			fail("%v is greater than maximum %v", f, *s.Maximum)
+		}
+
+	case string:
+		if n := utf8.RuneCountInString(val); n < s.MinLength {
+			fail("length %d is less than minLength %d", n, s.MinLength)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, val); err != nil {
+				fail("%q is not a valid date-time", val)
+			}
+		}
+
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, out)
+			}
+		}
+
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				*out = append(*out, SchemaViolation{Path: joinPath(path, name), Message: "is required"})
+			}
+		}
+
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if prop, ok := s.Properties[name]; ok {
+				prop.validate(joinPath(path, name), val[name], out)
+				continue
+			}
+			switch {
+			case s.NoAdditional:
+				*out = append(*out, SchemaViolation{Path: joinPath(path, name), Message: "is not allowed"})
+			case s.AdditionalProperties != nil:
+				s.AdditionalProperties.validate(joinPath(path, name), val[name], out)
+			}
+		}
+	}
+}
+
+func (s *jsonSchema) typeMatches(v any) bool {
+	for _, t := range s.Types {
+		switch t {
+		case "integer":
+			if n, ok := v.(json.Number); ok && isInteger(n) {
+				return true
+			}
+		default:
+			if jsonType(v) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func isInteger(n json.Number) bool {
+	f, err := n.Float64()
+	return err == nil && f == math.Trunc(f)
+}
+
+// enumContains compares by JSON value: numbers numerically, everything else
+// by equality.
+func enumContains(enum []any, v any) bool {
+	for _, e := range enum {
+		if n, ok := v.(json.Number); ok {
+			f, _ := n.Float64()
+			if ef, ok := e.(float64); ok && ef == f {
+				return true
+			}
+			continue
+		}
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func schemaPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
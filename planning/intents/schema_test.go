@@ -0,0 +1,240 @@
+package intents
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validOutput() IntentOutput {
+	return IntentOutput{
+		Meta: Meta{
+			IntentID:  "interpret.regime_state",
+			Timestamp: time.Now().UTC(),
+			Version:   "v1",
+		},
+		Status:     StatusModerateSignal,
+		Confidence: 0.5,
+		Summary:    "Test summary",
+		Signals: []SignalUsage{
+			{SignalID: "REGIME_SHIFT", Value: 0.8, Weight: 1},
+		},
+		Reasoning: Reasoning{
+			Logic:       []ReasoningStep{{Step: 1, Description: "Test logic", RuleID: "r1"}},
+			Explanation: "Test explanation",
+		},
+		Guardrails: &Guardrails{PoliciesFired: []string{"p1"}},
+		Trace: &Trace{
+			Rules: []RuleTrace{{
+				RuleID: "r1",
+				Status: StatusModerateSignal,
+				Conditions: []ConditionTrace{
+					{Block: "all", Signal: "REGIME_SHIFT", Op: ">=", Threshold: 0.5},
+				},
+			}},
+			Resolution: ResolutionTrace{
+				Strategy:            "additive",
+				Status:              StatusModerateSignal,
+				ContributingRuleIDs: []string{"r1"},
+			},
+		},
+	}
+}
+
+func TestValidateSchema_OK(t *testing.T) {
+	out := validOutput()
+	if err := out.ValidateSchema(); err != nil {
+		t.Fatalf("expected valid output, got %v", err)
+	}
+	if err := out.ValidateBasic(); err != nil {
+		t.Fatalf("ValidateBasic must agree with the schema, got %v", err)
+	}
+}
+
+func TestValidateSchema_AllStatuses(t *testing.T) {
+	for _, s := range Statuses {
+		out := validOutput()
+		out.Status = s
+		if err := out.ValidateSchema(); err != nil {
+			t.Fatalf("status %s rejected by schema: %v", s, err)
+		}
+		if err := out.ValidateBasic(); err != nil {
+			t.Fatalf("status %s rejected by ValidateBasic: %v", s, err)
+		}
+	}
+}
+
+func TestValidateSchema_Violations(t *testing.T) {
+	out := validOutput()
+	out.Status = "maybe"
+	out.Confidence = 1.5
+	out.Signals = nil
+	out.Meta.IntentID = ""
+
+	err := out.ValidateSchema()
+	var serr *SchemaError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected *SchemaError, got %v", err)
+	}
+
+	paths := map[string]bool{}
+	for _, v := range serr.Violations {
+		paths[v.Path] = true
+	}
+	for _, want := range []string{"status", "confidence", "signals", "meta.intent_id"} {
+		if !paths[want] {
+			t.Fatalf("expected violation at %s, got %v", want, serr.Violations)
+		}
+	}
+}
+
+func TestValidateOutputJSON_UnknownProperty(t *testing.T) {
+	doc := `{
+		"meta": {"intent_id": "x.y", "timestamp": "2026-01-01T00:00:00Z", "version": "v1"},
+		"status": "weak_signal", "confidence": 0.2, "summary": "s",
+		"signals": [], "reasoning": {"logic": [], "explanation": "e"},
+		"surprise": true
+	}`
+	if err := ValidateOutputJSON([]byte(doc)); err == nil || !strings.Contains(err.Error(), "surprise") {
+		t.Fatalf("expected unknown property to be rejected, got %v", err)
+	}
+}
+
+func TestCompileSchema_RejectsUnsupportedKeyword(t *testing.T) {
+	_, err := compileSchema("", map[string]any{"type": "string", "pattern": "^a"})
+	if err == nil {
+		t.Fatal("expected unsupported keyword to fail compilation")
+	}
+}
+
+// TestSchemaMatchesGoTypes keeps intent_output.schema.json in sync with
+// IntentOutput: every JSON field must be declared with a matching type,
+// non-omitempty fields must be required, and status enums must list
+// exactly Statuses.
+func TestSchemaMatchesGoTypes(t *testing.T) {
+	checkSchemaType(t, "", reflect.TypeOf(IntentOutput{}), outputSchema)
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	statusType = reflect.TypeOf(IntentStatus(""))
+)
+
+func checkSchemaType(t *testing.T, path string, typ reflect.Type, s *jsonSchema) {
+	t.Helper()
+	at := schemaPath(path)
+
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == timeType:
+		expectTypes(t, at, s, "string")
+		if s.Format != "date-time" {
+			t.Errorf("%s: expected format date-time", at)
+		}
+
+	case typ == statusType:
+		expectTypes(t, at, s, "string")
+		var enum []string
+		for _, e := range s.Enum {
+			enum = append(enum, e.(string))
+		}
+		var want []string
+		for _, st := range Statuses {
+			want = append(want, string(st))
+		}
+		sort.Strings(enum)
+		sort.Strings(want)
+		if strings.Join(enum, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: enum %v does not match Statuses %v", at, enum, want)
+		}
+
+	case typ.Kind() == reflect.String:
+		expectTypes(t, at, s, "string")
+	case typ.Kind() == reflect.Bool:
+		expectTypes(t, at, s, "boolean")
+	case typ.Kind() == reflect.Int:
+		expectTypes(t, at, s, "integer")
+	case typ.Kind() == reflect.Float64:
+		expectTypes(t, at, s, "number")
+
+	case typ.Kind() == reflect.Map:
+		expectTypes(t, at, s, "object")
+		if s.NoAdditional {
+			t.Errorf("%s: maps must allow additional properties", at)
+		}
+
+	case typ.Kind() == reflect.Slice:
+		expectTypes(t, at, s, "array")
+		if s.Items == nil {
+			t.Errorf("%s: missing items", at)
+			return
+		}
+		checkSchemaType(t, path+"[]", typ.Elem(), s.Items)
+
+	case typ.Kind() == reflect.Struct:
+		expectTypes(t, at, s, "object")
+		if !s.NoAdditional {
+			t.Errorf("%s: objects must set additionalProperties: false", at)
+		}
+
+		required := map[string]bool{}
+		for _, r := range s.Required {
+			required[r] = true
+		}
+		seen := map[string]bool{}
+
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			seen[name] = true
+
+			prop, ok := s.Properties[name]
+			if !ok {
+				t.Errorf("%s: field %s is not declared in the schema", at, joinPath(path, name))
+				continue
+			}
+			omitempty := strings.Contains(opts, "omitempty")
+			if !omitempty != required[name] {
+				t.Errorf("%s: required mismatch (omitempty=%v, required=%v)", joinPath(path, name), omitempty, required[name])
+			}
+			if f.Type.Kind() == reflect.Pointer && !omitempty && !hasType(prop, "null") {
+				t.Errorf("%s: nullable field must allow null", joinPath(path, name))
+			}
+			checkSchemaType(t, joinPath(path, name), f.Type, prop)
+		}
+
+		for name := range s.Properties {
+			if !seen[name] {
+				t.Errorf("%s: schema property %s has no Go field", at, joinPath(path, name))
+			}
+		}
+
+	default:
+		t.Errorf("%s: unsupported Go type %s", at, typ)
+	}
+}
+
+func expectTypes(t *testing.T, at string, s *jsonSchema, want string) {
+	t.Helper()
+	if !hasType(s, want) {
+		t.Errorf("%s: expected type %s, schema has %v", at, want, s.Types)
+	}
+}
+
+func hasType(s *jsonSchema, want string) bool {
+	for _, ty := range s.Types {
+		if ty == want {
+			return true
+		}
+	}
+	return false
+}
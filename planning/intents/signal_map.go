@@ -0,0 +1,172 @@
+package intents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SignalRequirements lists the signals an intent needs (required) and can
+// use (optional), plus optional relative weights used to explain decisions.
+type SignalRequirements struct {
+	Required []string           `json:"required"`
+	Optional []string           `json:"optional"`
+	Weights  map[string]float64 `json:"weights,omitempty"`
+}
+
+// DefaultSignalWeight is the relative weight of a signal without an explicit entry.
+const DefaultSignalWeight = 1.0
+
+// SignalMap is the per-intent signal contract (signal_intent_map.json).
+type SignalMap map[string]SignalRequirements
+
+// SignalCheck is the result of checking a signal snapshot against the map.
+type SignalCheck struct {
+	Missing    []string
+	Unexpected []string
+}
+
+// MissingSignalsError is returned when required signals are absent.
+type MissingSignalsError struct {
+	IntentID string
+	Missing  []string
+}
+
+func (e *MissingSignalsError) Error() string {
+	return fmt.Sprintf("intent '%s' is missing required signals: %s",
+		e.IntentID, strings.Join(e.Missing, ", "))
+}
+
+// LoadSignalMap reads a signal_intent_map.json file.
+func LoadSignalMap(path string) (SignalMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m SignalMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Validate checks that every mapped intent is registered and that no signal
+// is both required and optional.
+func (m SignalMap) Validate(registry *Registry) error {
+	for _, id := range m.intentIDs() {
+		if _, err := registry.Lookup(id); err != nil {
+			return fmt.Errorf("signal map: %w", err)
+		}
+		req := toSet(m[id].Required)
+		for _, s := range m[id].Optional {
+			if req[s] {
+				return fmt.Errorf("signal map: intent '%s' lists %s as both required and optional", id, s)
+			}
+		}
+		for s, w := range m[id].Weights {
+			if !m.Allows(id, s) {
+				return fmt.Errorf("signal map: intent '%s' weights undeclared signal %s", id, s)
+			}
+			if w < 0 {
+				return fmt.Errorf("signal map: intent '%s' weight for %s must be >= 0", id, s)
+			}
+		}
+	}
+	return nil
+}
+
+// Weight returns the configured relative weight of signalID for intentID.
+func (m SignalMap) Weight(intentID, signalID string) float64 {
+	if w, ok := m[intentID].Weights[signalID]; ok {
+		return w
+	}
+	return DefaultSignalWeight
+}
+
+// Declares reports whether the map has an entry for intentID.
+func (m SignalMap) Declares(intentID string) bool {
+	_, ok := m[intentID]
+	return ok
+}
+
+// Allows reports whether signalID is required or optional for intentID.
+func (m SignalMap) Allows(intentID, signalID string) bool {
+	req, ok := m[intentID]
+	if !ok {
+		return false
+	}
+	for _, s := range req.Required {
+		if s == signalID {
+			return true
+		}
+	}
+	for _, s := range req.Optional {
+		if s == signalID {
+			return true
+		}
+	}
+	return false
+}
+
+// Accepts reports whether signalID may be passed to intentID: intents
+// without an entry have no contract and accept every signal.
+func (m SignalMap) Accepts(intentID, signalID string) bool {
+	return !m.Declares(intentID) || m.Allows(intentID, signalID)
+}
+
+// Check compares the provided signal IDs with the intent's contract.
+// Intents without an entry have no contract and always pass.
+func (m SignalMap) Check(intentID string, signalIDs []string) SignalCheck {
+	var check SignalCheck
+
+	req, ok := m[intentID]
+	if !ok {
+		return check
+	}
+
+	present := toSet(signalIDs)
+	for _, s := range req.Required {
+		if !present[s] {
+			check.Missing = append(check.Missing, s)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range signalIDs {
+		if !seen[s] && !m.Allows(intentID, s) {
+			check.Unexpected = append(check.Unexpected, s)
+		}
+		seen[s] = true
+	}
+	sort.Strings(check.Unexpected)
+
+	return check
+}
+
+// Err returns a *MissingSignalsError when required signals are missing.
+func (c SignalCheck) Err(intentID string) error {
+	if len(c.Missing) == 0 {
+		return nil
+	}
+	return &MissingSignalsError{IntentID: intentID, Missing: c.Missing}
+}
+
+func (m SignalMap) intentIDs() []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func toSet(xs []string) map[string]bool {
+	set := make(map[string]bool, len(xs))
+	for _, x := range xs {
+		set[x] = true
+	}
+	return set
+}
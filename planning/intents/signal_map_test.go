@@ -0,0 +1,49 @@
+package intents
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadSignalMap_Shipped(t *testing.T) {
+	m, err := LoadSignalMap("signal_intent_map.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reg, err := LoadRegistry("intents.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := m.Validate(reg); err != nil {
+		t.Fatalf("shipped signal map must be valid: %v", err)
+	}
+	if !m.Declares("interpret.regime_state") || !m.Declares("evaluate.opportunity") {
+		t.Fatal("expected the shipped signal declarations")
+	}
+}
+
+func TestSignalMap_Check(t *testing.T) {
+	m := SignalMap{
+		"interpret.regime_state": {
+			Required: []string{"REGIME_SHIFT", "PROBABILITY_ACCELERATION"},
+			Optional: []string{"CONVICTION_SPIKE"},
+		},
+	}
+
+	check := m.Check("interpret.regime_state", []string{"REGIME_SHIFT", "CONVICTION_SPIKE", "DIVERGENCE_ALERT"})
+	if len(check.Missing) != 1 || check.Missing[0] != "PROBABILITY_ACCELERATION" {
+		t.Fatalf("unexpected missing: %v", check.Missing)
+	}
+	if len(check.Unexpected) != 1 || check.Unexpected[0] != "DIVERGENCE_ALERT" {
+		t.Fatalf("unexpected unexpected: %v", check.Unexpected)
+	}
+
+	var missing *MissingSignalsError
+	if !errors.As(check.Err("interpret.regime_state"), &missing) {
+		t.Fatal("expected *MissingSignalsError")
+	}
+
+	if check := m.Check("observe.market_state", []string{"X"}); check.Err("observe.market_state") != nil || len(check.Unexpected) != 0 {
+		t.Fatalf("intents without a contract must pass: %+v", check)
+	}
+}
@@ -0,0 +1,36 @@
+package intents
+
+// Trace is a structured record of how a decision was reached. Reasoners only
+// attach it when the caller asks for it (params "explain" or "debug").
+type Trace struct {
+	Rules      []RuleTrace     `json:"rules"`
+	Resolution ResolutionTrace `json:"resolution"`
+}
+
+// RuleTrace records one evaluated rule, whether it matched or not.
+type RuleTrace struct {
+	RuleID     string           `json:"rule_id"`
+	Priority   int              `json:"priority"`
+	Status     IntentStatus     `json:"status"` // status the rule would produce
+	Matched    bool             `json:"matched"`
+	Conditions []ConditionTrace `json:"conditions"`
+}
+
+// ConditionTrace records one condition with the value it was checked against.
+type ConditionTrace struct {
+	Block     string   `json:"block"` // "all" or "any"
+	Signal    string   `json:"signal"`
+	Observed  *float64 `json:"observed"` // null when the signal was not provided
+	Op        string   `json:"op"`
+	Threshold float64  `json:"threshold"`
+	Outcome   bool     `json:"outcome"`
+}
+
+// ResolutionTrace records how matched rules were combined.
+type ResolutionTrace struct {
+	Strategy            string       `json:"strategy"`
+	Status              IntentStatus `json:"status"`
+	Confidence          float64      `json:"confidence"`
+	WinningRuleID       string       `json:"winning_rule_id,omitempty"`
+	ContributingRuleIDs []string     `json:"contributing_rule_ids"`
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 )
 
+// ValidateBasic is a quick structural check that needs no JSON encoding.
+// It agrees with intent_output.schema.json but checks less; ValidateSchema
+// is the full validation.
 func (o IntentOutput) ValidateBasic() error {
 	if o.Meta.IntentID == "" {
 		return errors.New("meta.intent_id is required")
@@ -12,10 +15,7 @@ func (o IntentOutput) ValidateBasic() error {
 	if o.Meta.Version == "" {
 		return errors.New("meta.version is required")
 	}
-	switch o.Status {
-	case StatusNotTriggered, StatusWeakSignal, StatusStrongSignal:
-		// ok
-	default:
+	if !o.Status.IsValid() {
 		return fmt.Errorf("status is invalid: %q", o.Status)
 	}
 	if o.Confidence < 0 || o.Confidence > 1 {
@@ -24,8 +24,10 @@ func (o IntentOutput) ValidateBasic() error {
 	if o.Summary == "" {
 		return errors.New("summary is required")
 	}
-	if len(o.Signals) == 0 {
-		return errors.New("signals must be non-empty")
+	// The schema requires the arrays to be present (not null); they may be
+	// empty, e.g. reasoning.logic when no rule matched.
+	if o.Signals == nil {
+		return errors.New("signals is required")
 	}
 	for i, s := range o.Signals {
 		if s.SignalID == "" {
@@ -38,9 +40,8 @@ func (o IntentOutput) ValidateBasic() error {
 	if o.Reasoning.Explanation == "" {
 		return errors.New("reasoning.explanation is required")
 	}
-	// reasoning.logic can be empty in some modes, but schema requires it; keep strict:
-	if len(o.Reasoning.Logic) == 0 {
-		return errors.New("reasoning.logic must be non-empty")
+	if o.Reasoning.Logic == nil {
+		return errors.New("reasoning.logic is required")
 	}
 	return nil
 }
@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"woodpecker/planning/intents"
+)
+
+// DefaultIntents are the intents a rule watches when it lists none.
+var DefaultIntents = []string{"trigger.*"}
+
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 30 * time.Second
+)
+
+// Sink types.
+const (
+	SinkWebhook = "webhook"
+	SinkSMTP    = "smtp"
+	SinkFile    = "file"
+	SinkStdout  = "stdout"
+)
+
+// Config is a notifications file.
+type Config struct {
+	Rules []Rule       `yaml:"rules"`
+	Sinks []SinkConfig `yaml:"sinks"`
+	Retry Retry        `yaml:"retry,omitempty"`
+}
+
+// Rule notifies its sinks when a matching intent's status crosses MinStatus
+// upwards, i.e. the previous status ranked below it and the new one does not.
+type Rule struct {
+	ID      string               `yaml:"id"`
+	Intents []string             `yaml:"intents,omitempty"` // glob patterns, default DefaultIntents
+	Level   intents.IntentStatus `yaml:"min_status"`
+
+	// DedupWindow suppresses a repeat of the same market/intent/status.
+	DedupWindow time.Duration `yaml:"dedup_window,omitempty"`
+	// Cooldown suppresses any further notification for the market/intent.
+	Cooldown time.Duration `yaml:"cooldown,omitempty"`
+
+	Sinks []string `yaml:"sinks"`
+}
+
+// SinkConfig declares a named sink. Secrets are read from the environment.
+type SinkConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+
+	// webhook
+	URL       string        `yaml:"url,omitempty"`
+	SecretEnv string        `yaml:"secret_env,omitempty"`
+	Timeout   time.Duration `yaml:"timeout,omitempty"`
+
+	// smtp
+	Addr        string   `yaml:"addr,omitempty"`
+	From        string   `yaml:"from,omitempty"`
+	To          []string `yaml:"to,omitempty"`
+	UsernameEnv string   `yaml:"username_env,omitempty"`
+	PasswordEnv string   `yaml:"password_env,omitempty"`
+
+	// file
+	Path string `yaml:"path,omitempty"`
+}
+
+// Retry bounds delivery attempts per sink. Backoff doubles after every failed
+// attempt, up to MaxBackoff.
+type Retry struct {
+	MaxAttempts    int           `yaml:"max_attempts,omitempty"`
+	InitialBackoff time.Duration `yaml:"initial_backoff,omitempty"`
+	MaxBackoff     time.Duration `yaml:"max_backoff,omitempty"`
+}
+
+// LoadConfig reads and validates a notifications file.
+func LoadConfig(p string) (Config, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", p, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks sinks, rules and that every rule references known sinks.
+func (c Config) Validate() error {
+	sinks := make(map[string]bool)
+	for i, s := range c.Sinks {
+		if s.Name == "" {
+			return fmt.Errorf("sinks[%d]: name must not be empty", i)
+		}
+		if sinks[s.Name] {
+			return fmt.Errorf("sinks[%d]: duplicate sink '%s'", i, s.Name)
+		}
+		sinks[s.Name] = true
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("sink %s: %w", s.Name, err)
+		}
+	}
+
+	if len(c.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	ids := make(map[string]bool)
+	for i, r := range c.Rules {
+		if r.ID == "" {
+			return fmt.Errorf("rules[%d]: id must not be empty", i)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("rules[%d]: duplicate rule '%s'", i, r.ID)
+		}
+		ids[r.ID] = true
+
+		if !r.Level.IsValid() {
+			return fmt.Errorf("rule %s: unknown min_status '%s'", r.ID, r.Level)
+		}
+		for _, pattern := range r.Intents {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %s: bad intent pattern '%s'", r.ID, pattern)
+			}
+		}
+		if r.DedupWindow < 0 || r.Cooldown < 0 {
+			return fmt.Errorf("rule %s: dedup_window and cooldown must be >= 0", r.ID)
+		}
+		if len(r.Sinks) == 0 {
+			return fmt.Errorf("rule %s: at least one sink is required", r.ID)
+		}
+		for _, s := range r.Sinks {
+			if !sinks[s] {
+				return fmt.Errorf("rule %s: unknown sink '%s'", r.ID, s)
+			}
+		}
+	}
+
+	if c.Retry.MaxAttempts < 0 || c.Retry.InitialBackoff < 0 || c.Retry.MaxBackoff < 0 {
+		return fmt.Errorf("retry values must be >= 0")
+	}
+	return nil
+}
+
+// Validate checks the fields the sink type needs.
+func (s SinkConfig) Validate() error {
+	switch s.Type {
+	case SinkWebhook:
+		if s.URL == "" {
+			return fmt.Errorf("webhook requires url")
+		}
+	case SinkSMTP:
+		if s.Addr == "" || s.From == "" || len(s.To) == 0 {
+			return fmt.Errorf("smtp requires addr, from and to")
+		}
+	case SinkFile:
+		if s.Path == "" {
+			return fmt.Errorf("file requires path")
+		}
+	case SinkStdout:
+	default:
+		return fmt.Errorf("unknown sink type '%s'", s.Type)
+	}
+	return nil
+}
+
+// patterns returns the rule's intent globs.
+func (r Rule) patterns() []string {
+	if len(r.Intents) == 0 {
+		return DefaultIntents
+	}
+	return r.Intents
+}
+
+// matches reports whether the rule watches intentID.
+func (r Rule) matches(intentID string) bool {
+	for _, p := range r.patterns() {
+		if m, _ := path.Match(p, intentID); m {
+			return true
+		}
+	}
+	return false
+}
+
+// crossed reports whether prev → cur crosses the rule level upwards. An
+// unknown previous status (first evaluation) counts as below every level.
+func (r Rule) crossed(prev, cur intents.IntentStatus) bool {
+	level := r.Level.Rank()
+	return prev.Rank() < level && cur.Rank() >= level
+}
@@ -0,0 +1,245 @@
+// Package notify delivers notifications to webhook, SMTP and file sinks when
+// an intent's status crosses a configured level (by default on trigger.*
+// intents), with per market/intent dedup windows, cooldowns and retries.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"woodpecker/pipeline"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/stream"
+)
+
+// Notification is the payload delivered to sinks.
+type Notification struct {
+	// ID is stable across retries so receivers can drop duplicates.
+	ID     string    `json:"id"`
+	RuleID string    `json:"rule_id"`
+	Time   time.Time `json:"time"`
+
+	Venue    pipeline.Venue `json:"venue"`
+	EventID  string         `json:"event_id"`
+	MarketID string         `json:"market_id"`
+	IntentID string         `json:"intent_id"`
+
+	Level          intents.IntentStatus `json:"level"`
+	Status         intents.IntentStatus `json:"status"`
+	PreviousStatus intents.IntentStatus `json:"previous_status,omitempty"`
+	Confidence     float64              `json:"confidence"`
+	Summary        string               `json:"summary"`
+
+	Output intents.IntentOutput `json:"output"`
+}
+
+// Notifier turns stream events into notifications. Deliveries run in the
+// background; Wait blocks until they are done.
+type Notifier struct {
+	Rules []Rule
+	Sinks map[string]Sink
+	Retry Retry
+
+	// Now and Sleep default to time.Now and a context-aware sleep.
+	Now   func() time.Time
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time // rule|market|intent → last notification
+	lastSeen map[string]time.Time // rule|market|intent|status → last notification
+
+	wg sync.WaitGroup
+}
+
+// New builds a Notifier from a validated config, opening its sinks.
+func New(cfg Config) (*Notifier, error) {
+	sinks, err := BuildSinks(cfg.Sinks)
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{Rules: cfg.Rules, Sinks: sinks, Retry: cfg.Retry}, nil
+}
+
+// Filter selects the hub events any rule may notify on.
+func (n *Notifier) Filter() stream.Filter {
+	var f stream.Filter
+	for _, r := range n.Rules {
+		f.Intents = append(f.Intents, r.patterns()...)
+	}
+	return f
+}
+
+// Run subscribes to hub and handles events until ctx is done, then waits for
+// pending deliveries and closes the sinks. A subscription dropped for falling
+// behind is resumed from the last handled event.
+func (n *Notifier) Run(ctx context.Context, hub *stream.Hub) {
+	defer func() {
+		n.Wait()
+		n.Close()
+	}()
+
+	var lastID uint64
+	for {
+		replay, sub := hub.Subscribe(n.Filter(), lastID)
+		for _, e := range replay {
+			n.Handle(ctx, e)
+			lastID = e.ID
+		}
+
+	events:
+		for {
+			select {
+			case <-ctx.Done():
+				hub.Unsubscribe(sub)
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					log.Printf("⚠️ notify: fell behind the stream, resuming after event %d", lastID)
+					break events
+				}
+				n.Handle(ctx, e)
+				lastID = e.ID
+			}
+		}
+	}
+}
+
+// Handle checks e against every rule and dispatches the notifications that
+// pass dedup and cooldown. It returns how many notifications were dispatched.
+func (n *Notifier) Handle(ctx context.Context, e stream.Event) int {
+	now := time.Now
+	if n.Now != nil {
+		now = n.Now
+	}
+
+	n.mu.Lock()
+	if n.lastSent == nil {
+		n.lastSent = make(map[string]time.Time)
+		n.lastSeen = make(map[string]time.Time)
+	}
+
+	var pending []func()
+	for _, r := range n.Rules {
+		if !r.matches(e.IntentID) || !r.crossed(e.PreviousStatus, e.Status) {
+			continue
+		}
+
+		t := now()
+		key := r.ID + "|" + pipeline.HistoryKey(e.Venue, e.MarketID) + "|" + e.IntentID
+		statusKey := key + "|" + string(e.Status)
+		if last, ok := n.lastSent[key]; ok && t.Sub(last) < r.Cooldown {
+			continue
+		}
+		if last, ok := n.lastSeen[statusKey]; ok && t.Sub(last) < r.DedupWindow {
+			continue
+		}
+		n.lastSent[key] = t
+		n.lastSeen[statusKey] = t
+
+		note := Notification{
+			ID:             fmt.Sprintf("%s-%d", r.ID, e.ID),
+			RuleID:         r.ID,
+			Time:           t.UTC(),
+			Venue:          e.Venue,
+			EventID:        e.EventID,
+			MarketID:       e.MarketID,
+			IntentID:       e.IntentID,
+			Level:          r.Level,
+			Status:         e.Status,
+			PreviousStatus: e.PreviousStatus,
+			Confidence:     e.Output.Confidence,
+			Summary:        e.Output.Summary,
+			Output:         e.Output,
+		}
+		for _, name := range r.Sinks {
+			sink, ok := n.Sinks[name]
+			if !ok {
+				log.Printf("⚠️ notify: rule %s: sink %s is not configured", r.ID, name)
+				continue
+			}
+			pending = append(pending, func() { n.deliver(ctx, name, sink, note) })
+		}
+	}
+	n.mu.Unlock()
+
+	for _, d := range pending {
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			d()
+		}()
+	}
+	return len(pending)
+}
+
+// Wait blocks until every dispatched delivery has finished or given up.
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+// Close closes the sinks that hold resources (e.g. open files).
+func (n *Notifier) Close() {
+	for name, sink := range n.Sinks {
+		if c, ok := sink.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				log.Printf("⚠️ notify: closing sink %s: %v", name, err)
+			}
+		}
+	}
+}
+
+// deliver sends note to sink, retrying with exponential backoff.
+func (n *Notifier) deliver(ctx context.Context, name string, sink Sink, note Notification) {
+	attempts := n.Retry.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+	backoff := n.Retry.InitialBackoff
+	if backoff <= 0 {
+		backoff = DefaultInitialBackoff
+	}
+	maxBackoff := n.Retry.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+	sleep := n.Sleep
+	if sleep == nil {
+		sleep = sleepCtx
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := sink.Send(ctx, note)
+		if err == nil {
+			return
+		}
+		if isPermanent(err) || attempt >= attempts {
+			log.Printf("❌ notify: %s → %s failed after %d attempt(s): %v", note.ID, name, attempt, err)
+			return
+		}
+		log.Printf("⚠️ notify: %s → %s attempt %d: %v (retrying in %s)", note.ID, name, attempt, err, backoff)
+
+		if err := sleep(ctx, backoff); err != nil {
+			log.Printf("❌ notify: %s → %s abandoned: %v", note.ID, name, err)
+			return
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
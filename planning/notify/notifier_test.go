@@ -0,0 +1,440 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"woodpecker/pipeline"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/stream"
+)
+
+// recordingSink records every notification and fails the first failures sends.
+type recordingSink struct {
+	mu       sync.Mutex
+	got      []Notification
+	attempts int
+	failures int
+	err      error
+}
+
+func (s *recordingSink) Send(_ context.Context, n Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return s.err
+	}
+	s.got = append(s.got, n)
+	return nil
+}
+
+func (s *recordingSink) sent() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Notification(nil), s.got...)
+}
+
+// clock is a settable Now.
+type clock struct{ t time.Time }
+
+func (c *clock) Now() time.Time { return c.t }
+
+func event(id uint64, prev, cur intents.IntentStatus) stream.Event {
+	return stream.Event{
+		ID:             id,
+		Venue:          pipeline.VenueKalshi,
+		EventID:        "KXBTCD-25DEC3117",
+		MarketID:       "KXBTCD-25DEC3117-T100000",
+		IntentID:       "trigger.regime_change",
+		Status:         cur,
+		PreviousStatus: prev,
+		Output:         intents.IntentOutput{Status: cur, Confidence: 0.7, Summary: "Regime change"},
+	}
+}
+
+func newTestNotifier(rule Rule, sink Sink) (*Notifier, *clock) {
+	c := &clock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	rule.Sinks = []string{"test"}
+	return &Notifier{
+		Rules: []Rule{rule},
+		Sinks: map[string]Sink{"test": sink},
+		Now:   c.Now,
+		Sleep: func(context.Context, time.Duration) error { return nil },
+	}, c
+}
+
+func TestHandle_NotifiesOnUpwardCrossingOnly(t *testing.T) {
+	sink := &recordingSink{}
+	n, _ := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal}, sink)
+	ctx := context.Background()
+
+	cases := []struct {
+		prev, cur intents.IntentStatus
+		want      int
+	}{
+		{intents.StatusWeakSignal, intents.StatusWeakSignal, 0},
+		{intents.StatusStrongSignal, intents.StatusModerateSignal, 0}, // already above
+		{intents.StatusModerateSignal, intents.StatusWeakSignal, 0},   // downward
+		{intents.StatusWeakSignal, intents.StatusStrongSignal, 1},
+	}
+	for i, c := range cases {
+		if got := n.Handle(ctx, event(uint64(i+1), c.prev, c.cur)); got != c.want {
+			t.Fatalf("%s → %s: expected %d notifications, got %d", c.prev, c.cur, c.want, got)
+		}
+	}
+	n.Wait()
+
+	sent := sink.sent()
+	if len(sent) != 1 || sent[0].Status != intents.StatusStrongSignal || sent[0].Level != intents.StatusModerateSignal {
+		t.Fatalf("unexpected notifications %+v", sent)
+	}
+	if sent[0].ID != "r-4" {
+		t.Fatalf("expected delivery ID r-4, got %s", sent[0].ID)
+	}
+}
+
+func TestHandle_FirstEvaluationCounts(t *testing.T) {
+	n, _ := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal}, &recordingSink{})
+	if got := n.Handle(context.Background(), event(1, "", intents.StatusModerateSignal)); got != 1 {
+		t.Fatalf("first evaluation above the level must notify, got %d", got)
+	}
+	n.Wait()
+}
+
+func TestHandle_IgnoresOtherIntents(t *testing.T) {
+	n, _ := newTestNotifier(Rule{ID: "r", Level: intents.StatusWeakSignal}, &recordingSink{})
+	e := event(1, intents.StatusNotTriggered, intents.StatusStrongSignal)
+	e.IntentID = "observe.market_state"
+	if got := n.Handle(context.Background(), e); got != 0 {
+		t.Fatalf("rules default to trigger.* intents, got %d notifications", got)
+	}
+}
+
+func TestHandle_DedupWindow(t *testing.T) {
+	n, c := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal, DedupWindow: time.Hour}, &recordingSink{})
+	ctx := context.Background()
+
+	up := func(id uint64, s intents.IntentStatus) int {
+		return n.Handle(ctx, event(id, intents.StatusWeakSignal, s))
+	}
+
+	if up(1, intents.StatusModerateSignal) != 1 {
+		t.Fatal("first crossing must notify")
+	}
+	c.t = c.t.Add(10 * time.Minute)
+	if up(2, intents.StatusModerateSignal) != 0 {
+		t.Fatal("same status within the dedup window must be suppressed")
+	}
+	if up(3, intents.StatusStrongSignal) != 1 {
+		t.Fatal("a different status is not a duplicate")
+	}
+	c.t = c.t.Add(time.Hour)
+	if up(4, intents.StatusModerateSignal) != 1 {
+		t.Fatal("same status after the dedup window must notify")
+	}
+	n.Wait()
+}
+
+func TestHandle_Cooldown(t *testing.T) {
+	n, c := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal, Cooldown: 30 * time.Minute}, &recordingSink{})
+	ctx := context.Background()
+
+	if n.Handle(ctx, event(1, intents.StatusWeakSignal, intents.StatusModerateSignal)) != 1 {
+		t.Fatal("first crossing must notify")
+	}
+	c.t = c.t.Add(10 * time.Minute)
+	if n.Handle(ctx, event(2, intents.StatusWeakSignal, intents.StatusStrongSignal)) != 0 {
+		t.Fatal("any status within the cooldown must be suppressed")
+	}
+
+	other := event(3, intents.StatusWeakSignal, intents.StatusStrongSignal)
+	other.MarketID = "KXBTCD-25DEC3117-T105000"
+	if n.Handle(ctx, other) != 1 {
+		t.Fatal("cooldown is per market")
+	}
+
+	c.t = c.t.Add(30 * time.Minute)
+	if n.Handle(ctx, event(4, intents.StatusWeakSignal, intents.StatusStrongSignal)) != 1 {
+		t.Fatal("crossing after the cooldown must notify")
+	}
+	n.Wait()
+}
+
+func TestDeliver_RetriesWithBackoff(t *testing.T) {
+	sink := &recordingSink{failures: 2, err: errors.New("connection refused")}
+	n, _ := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal}, sink)
+	n.Retry = Retry{MaxAttempts: 4, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second}
+
+	var waits []time.Duration
+	n.Sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	n.Handle(context.Background(), event(1, intents.StatusWeakSignal, intents.StatusModerateSignal))
+	n.Wait()
+
+	if len(sink.sent()) != 1 || sink.attempts != 3 {
+		t.Fatalf("expected delivery on the 3rd attempt, got %d attempts", sink.attempts)
+	}
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Fatalf("unexpected backoff %v", waits)
+	}
+}
+
+func TestDeliver_PermanentErrorIsNotRetried(t *testing.T) {
+	sink := &recordingSink{failures: 10, err: Permanent(errors.New("bad request"))}
+	n, _ := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal}, sink)
+
+	n.Handle(context.Background(), event(1, intents.StatusWeakSignal, intents.StatusModerateSignal))
+	n.Wait()
+
+	if sink.attempts != 1 {
+		t.Fatalf("permanent errors must not be retried, got %d attempts", sink.attempts)
+	}
+}
+
+func TestWebhookSink_SignsAndRetries(t *testing.T) {
+	secret := []byte("s3cret")
+
+	var (
+		mu       sync.Mutex
+		requests int
+		received []Notification
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		ts := r.Header.Get(TimestampHeader)
+		if got, want := r.Header.Get(SignatureHeader), Sign(secret, ts, body); got != want {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil || r.Header.Get(DeliveryHeader) != n.ID {
+			http.Error(w, "bad payload", http.StatusBadRequest)
+			return
+		}
+		received = append(received, n)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sink := &WebhookSink{URL: srv.URL, Secret: secret, Client: srv.Client()}
+	n, _ := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal}, sink)
+
+	n.Handle(context.Background(), event(7, intents.StatusWeakSignal, intents.StatusStrongSignal))
+	n.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 2 {
+		t.Fatalf("expected a retry after 503, got %d requests", requests)
+	}
+	if len(received) != 1 || received[0].ID != "r-7" || received[0].MarketID != "KXBTCD-25DEC3117-T100000" {
+		t.Fatalf("unexpected webhook deliveries %+v", received)
+	}
+}
+
+func TestWebhookSink_ClientErrorIsPermanent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer srv.Close()
+
+	err := (&WebhookSink{URL: srv.URL, Client: srv.Client()}).Send(context.Background(), Notification{ID: "x"})
+	if err == nil || !isPermanent(err) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+}
+
+func TestSMTPSink_Message(t *testing.T) {
+	var gotTo []string
+	var gotMsg string
+	sink := &SMTPSink{
+		Addr: "localhost:25",
+		From: "woodpecker@example.com",
+		To:   []string{"ops@example.com"},
+		send: func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+			gotTo, gotMsg = to, string(msg)
+			return nil
+		},
+	}
+
+	e := event(1, intents.StatusWeakSignal, intents.StatusStrongSignal)
+	if err := sink.Send(context.Background(), Notification{
+		ID: "r-1", RuleID: "r", IntentID: e.IntentID, Venue: e.Venue, MarketID: e.MarketID,
+		Level: intents.StatusModerateSignal, Status: e.Status, PreviousStatus: e.PreviousStatus, Summary: "Regime change",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gotTo) != 1 || gotTo[0] != "ops@example.com" {
+		t.Fatalf("unexpected recipients %v", gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: [woodpecker] trigger.regime_change strong_signal on kalshi KXBTCD-25DEC3117-T100000") {
+		t.Fatalf("unexpected message:\n%s", gotMsg)
+	}
+}
+
+func TestWriterSink_JSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &WriterSink{W: &buf}
+	for _, id := range []string{"a", "b"} {
+		if err := sink.Send(context.Background(), Notification{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	var n Notification
+	if err := json.Unmarshal([]byte(lines[1]), &n); err != nil || n.ID != "b" {
+		t.Fatalf("unexpected line %q (%v)", lines[1], err)
+	}
+}
+
+func TestRun_DeliversHubEvents(t *testing.T) {
+	sink := &recordingSink{}
+	n, _ := newTestNotifier(Rule{ID: "r", Level: intents.StatusModerateSignal, Cooldown: time.Hour}, sink)
+	hub := stream.NewHub(10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.Run(ctx, hub)
+		close(done)
+	}()
+
+	// Publish until the subscription is live.
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sink.sent()) == 0 && time.Now().Before(deadline) {
+		hub.Publish(event(0, intents.StatusWeakSignal, intents.StatusModerateSignal))
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if len(sink.sent()) != 1 {
+		t.Fatalf("expected one notification within the cooldown, got %d", len(sink.sent()))
+	}
+}
+
+func TestLoadConfig_Default(t *testing.T) {
+	cfg, err := LoadConfig("notify.yaml")
+	if err != nil {
+		t.Fatalf("notify.yaml must be valid: %v", err)
+	}
+	watchlist, err := stream.LoadConfig("../stream/watchlist.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range cfg.Rules {
+		if len(r.Sinks) == 0 {
+			t.Fatalf("rule %s has no sinks", r.ID)
+		}
+		watched := false
+		for _, id := range watchlist.Intents {
+			watched = watched || r.matches(id)
+		}
+		if !watched {
+			t.Fatalf("rule %s watches no intent of the shipped watchlist", r.ID)
+		}
+	}
+}
+
+func TestConfigValidate_Errors(t *testing.T) {
+	base := func() Config {
+		return Config{
+			Rules: []Rule{{ID: "r", Level: intents.StatusModerateSignal, Sinks: []string{"out"}}},
+			Sinks: []SinkConfig{{Name: "out", Type: SinkStdout}},
+		}
+	}
+
+	cases := map[string]func(*Config){
+		"unknown sink":   func(c *Config) { c.Rules[0].Sinks = []string{"nope"} },
+		"bad level":      func(c *Config) { c.Rules[0].Level = "loud" },
+		"bad type":       func(c *Config) { c.Sinks[0].Type = "pager" },
+		"webhook no url": func(c *Config) { c.Sinks[0].Type = SinkWebhook },
+		"no rules":       func(c *Config) { c.Rules = nil },
+	}
+	for name, mutate := range cases {
+		cfg := base()
+		mutate(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+	if err := base().Validate(); err != nil {
+		t.Fatalf("base config must be valid: %v", err)
+	}
+}
+
+func TestBuildSinks_FileSink(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "out.jsonl")
+	sinks, err := BuildSinks([]SinkConfig{{Name: "f", Type: SinkFile, Path: p}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, ok := sinks["f"].(*WriterSink)
+	if !ok {
+		t.Fatalf("expected a WriterSink, got %T", sinks["f"])
+	}
+
+	n := &Notifier{Sinks: sinks}
+	n.Close()
+	if _, err := io.WriteString(ws.W, "x"); err == nil {
+		t.Fatalf("expected the file to be closed")
+	}
+}
+
+func TestBuildSinks_SMTPCredentialsFromEnv(t *testing.T) {
+	t.Setenv("NOTIFY_TEST_SMTP_USER", "bot")
+	t.Setenv("NOTIFY_TEST_SMTP_PASS", "")
+	base := SinkConfig{Name: "mail", Type: SinkSMTP, Addr: "smtp.example.com:587", From: "a@example.com", To: []string{"b@example.com"}}
+
+	for name, c := range map[string]SinkConfig{
+		"unset password": {UsernameEnv: "NOTIFY_TEST_SMTP_USER", PasswordEnv: "NOTIFY_TEST_SMTP_PASS"},
+		"no password":    {UsernameEnv: "NOTIFY_TEST_SMTP_USER"},
+		"unset username": {UsernameEnv: "NOTIFY_TEST_SMTP_MISSING", PasswordEnv: "NOTIFY_TEST_SMTP_USER"},
+	} {
+		cfg := base
+		cfg.UsernameEnv, cfg.PasswordEnv = c.UsernameEnv, c.PasswordEnv
+		if _, err := BuildSinks([]SinkConfig{cfg}); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+
+	t.Setenv("NOTIFY_TEST_SMTP_PASS", "secret")
+	cfg := base
+	cfg.UsernameEnv, cfg.PasswordEnv = "NOTIFY_TEST_SMTP_USER", "NOTIFY_TEST_SMTP_PASS"
+	sinks, err := BuildSinks([]SinkConfig{cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sinks["mail"].(*SMTPSink).Auth == nil {
+		t.Fatalf("expected auth to be configured")
+	}
+}
@@ -0,0 +1,253 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"woodpecker/planning/intents"
+)
+
+// Webhook headers. The signature is "sha256=" + hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the sink secret.
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Signature-Timestamp"
+	DeliveryHeader  = "X-Delivery-ID"
+)
+
+// DefaultWebhookTimeout bounds one webhook attempt when the sink sets none.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Sink delivers a notification. Errors wrapped with Permanent are not retried.
+type Sink interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+// permanentError marks a delivery failure that a retry cannot fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Sign returns the X-Signature value for body sent at timestamp (unix seconds).
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookSink POSTs the notification as JSON, signed when Secret is set.
+type WebhookSink struct {
+	URL    string
+	Secret []byte
+	Client *http.Client
+
+	// Now stamps the signature; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Send makes one delivery attempt. 4xx responses other than 408 and 429 are
+// permanent failures.
+func (s *WebhookSink) Send(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, n.ID)
+	if len(s.Secret) > 0 {
+		now := time.Now
+		if s.Now != nil {
+			now = s.Now
+		}
+		ts := strconv.FormatInt(now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, Sign(s.Secret, ts, body))
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return Permanent(fmt.Errorf("webhook returned %s", resp.Status))
+	default:
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+}
+
+// SMTPSink mails a plain-text notification to To.
+type SMTPSink struct {
+	Addr string // host:port
+	From string
+	To   []string
+	Auth smtp.Auth
+
+	// send defaults to smtp.SendMail.
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Send makes one delivery attempt.
+func (s *SMTPSink) Send(ctx context.Context, n Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	send := s.send
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return send(s.Addr, s.Auth, s.From, s.To, s.message(n))
+}
+
+func (s *SMTPSink) message(n Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&b, "Subject: [woodpecker] %s %s on %s %s\r\n", n.IntentID, n.Status, n.Venue, n.MarketID)
+	fmt.Fprintf(&b, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	fmt.Fprintf(&b, "%s\r\n\r\n", n.Summary)
+	fmt.Fprintf(&b, "Intent:     %s\r\n", n.IntentID)
+	fmt.Fprintf(&b, "Status:     %s (was %s, rule %s at %s)\r\n", n.Status, previous(n.PreviousStatus), n.RuleID, n.Level)
+	fmt.Fprintf(&b, "Confidence: %.2f\r\n", n.Confidence)
+	fmt.Fprintf(&b, "Market:     %s %s (event %s)\r\n", n.Venue, n.MarketID, n.EventID)
+	fmt.Fprintf(&b, "Delivery:   %s\r\n", n.ID)
+	return []byte(b.String())
+}
+
+func previous(s intents.IntentStatus) string {
+	if s == "" {
+		return "none"
+	}
+	return string(s)
+}
+
+// WriterSink writes one JSON notification per line (file or stdout sinks).
+type WriterSink struct {
+	mu sync.Mutex
+	W  io.Writer
+
+	// closer is the file the sink opened; stdout is never closed.
+	closer io.Closer
+}
+
+// Close closes the file the sink writes to, if it opened one.
+func (s *WriterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closer == nil {
+		return nil
+	}
+	err := s.closer.Close()
+	s.closer = nil
+	return err
+}
+
+// Send writes n as a JSON line.
+func (s *WriterSink) Send(_ context.Context, n Notification) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return Permanent(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.W.Write(append(line, '\n'))
+	return err
+}
+
+// BuildSinks opens the configured sinks by name.
+func BuildSinks(cfgs []SinkConfig) (map[string]Sink, error) {
+	sinks := make(map[string]Sink, len(cfgs))
+	for _, c := range cfgs {
+		switch c.Type {
+		case SinkWebhook:
+			s := &WebhookSink{URL: c.URL}
+			if c.SecretEnv != "" {
+				secret := os.Getenv(c.SecretEnv)
+				if secret == "" {
+					return nil, fmt.Errorf("sink %s: %s is not set", c.Name, c.SecretEnv)
+				}
+				s.Secret = []byte(secret)
+			}
+			if c.Timeout > 0 {
+				s.Client = &http.Client{Timeout: c.Timeout}
+			}
+			sinks[c.Name] = s
+
+		case SinkSMTP:
+			s := &SMTPSink{Addr: c.Addr, From: c.From, To: c.To}
+			if c.UsernameEnv != "" || c.PasswordEnv != "" {
+				var creds [2]string
+				for i, env := range []string{c.UsernameEnv, c.PasswordEnv} {
+					if env == "" {
+						return nil, fmt.Errorf("sink %s: username_env and password_env must be set together", c.Name)
+					}
+					if creds[i] = os.Getenv(env); creds[i] == "" {
+						return nil, fmt.Errorf("sink %s: %s is not set", c.Name, env)
+					}
+				}
+				host, _, _ := strings.Cut(c.Addr, ":")
+				s.Auth = smtp.PlainAuth("", creds[0], creds[1], host)
+			}
+			sinks[c.Name] = s
+
+		case SinkFile:
+			f, err := os.OpenFile(c.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s: %w", c.Name, err)
+			}
+			sinks[c.Name] = &WriterSink{W: f, closer: f}
+
+		case SinkStdout:
+			sinks[c.Name] = &WriterSink{W: os.Stdout}
+
+		default:
+			return nil, fmt.Errorf("sink %s: unknown sink type '%s'", c.Name, c.Type)
+		}
+	}
+	return sinks, nil
+}
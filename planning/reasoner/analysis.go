@@ -0,0 +1,404 @@
+package reasoner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Signals are normalized to [SignalMin, SignalMax] by the Signal Layer, so
+// thresholds outside that range can never be satisfied.
+const (
+	SignalMin = 0.0
+	SignalMax = 1.0
+)
+
+// FindingKind classifies a static-analysis finding.
+type FindingKind string
+
+const (
+	FindingDeadRule          FindingKind = "dead_rule"
+	FindingShadowedRule      FindingKind = "shadowed_rule"
+	FindingUnreachableStatus FindingKind = "unreachable_status"
+	FindingOverlap           FindingKind = "overlap"
+)
+
+// Finding is a single static-analysis result.
+type Finding struct {
+	Kind    FindingKind
+	Intent  string
+	RuleID  string   // empty for intent-level findings
+	Related []string // other rules involved (shadowing / overlapping rules)
+	Message string
+}
+
+// Analysis is the result of AnalyzeRules.
+type Analysis struct {
+	Strategy ConflictStrategy
+	Findings []Finding
+}
+
+// Has reports whether the analysis contains a finding of the given kind for ruleID.
+func (a Analysis) Has(kind FindingKind, ruleID string) bool {
+	for _, f := range a.Findings {
+		if f.Kind == kind && f.RuleID == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyzeRuleset runs AnalyzeRules with the ruleset's own strategy.
+func AnalyzeRuleset(rs Ruleset) Analysis {
+	return AnalyzeRules(rs.Rules, rs.Strategy)
+}
+
+// AnalyzeRules statically inspects a ruleset:
+//   - dead rules: conditions that can never hold together (contradictory thresholds)
+//   - shadowed rules: every snapshot that matches the rule also matches a rule
+//     that outranks it under the strategy, so it never decides the status
+//   - unreachable statuses: statuses declared for an intent only by dead or shadowed rules
+//   - overlaps: rules with different statuses whose matching regions intersect
+//
+// The analysis is conservative: a rule is only reported as shadowed when each
+// of its regions is contained in a single region of an outranking rule.
+func AnalyzeRules(rules []Rule, strategy ConflictStrategy) Analysis {
+	strategy = strategy.orDefault()
+	a := Analysis{Strategy: strategy}
+
+	regions := make([][]region, len(rules))
+	dead := make([]bool, len(rules))
+	shadowed := make([]bool, len(rules))
+
+	for i, rule := range rules {
+		regions[i] = ruleRegions(rule)
+		if len(regions[i]) == 0 {
+			dead[i] = true
+			a.Findings = append(a.Findings, Finding{
+				Kind:    FindingDeadRule,
+				Intent:  rule.Intent,
+				RuleID:  rule.ID,
+				Message: deadReason(rule),
+			})
+		}
+	}
+
+	for i, rule := range rules {
+		if dead[i] {
+			continue
+		}
+		var by []string
+		covered := true
+		for _, r := range regions[i] {
+			coveredBy := ""
+			for j, other := range rules {
+				if j == i || dead[j] || other.Intent != rule.Intent || !outranks(strategy, rules, j, i) {
+					continue
+				}
+				if anyContains(regions[j], r) {
+					coveredBy = other.ID
+					break
+				}
+			}
+			if coveredBy == "" {
+				covered = false
+				break
+			}
+			by = appendUnique(by, coveredBy)
+		}
+		if covered {
+			shadowed[i] = true
+			a.Findings = append(a.Findings, Finding{
+				Kind:    FindingShadowedRule,
+				Intent:  rule.Intent,
+				RuleID:  rule.ID,
+				Related: by,
+				Message: fmt.Sprintf("every match is also matched by outranking rule(s) %s", strings.Join(by, ", ")),
+			})
+		}
+	}
+
+	a.Findings = append(a.Findings, unreachableStatuses(rules, dead, shadowed)...)
+
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			if rules[i].Intent != rules[j].Intent || dead[i] || dead[j] {
+				continue
+			}
+			if rules[i].Then.Status == rules[j].Then.Status {
+				continue
+			}
+			overlap, ok := firstOverlap(regions[i], regions[j])
+			if !ok {
+				continue
+			}
+			a.Findings = append(a.Findings, Finding{
+				Kind:    FindingOverlap,
+				Intent:  rules[i].Intent,
+				RuleID:  rules[i].ID,
+				Related: []string{rules[j].ID},
+				Message: fmt.Sprintf("%s and %s both match when %s",
+					rules[i].Then.Status, rules[j].Then.Status, overlap),
+			})
+		}
+	}
+
+	return a
+}
+
+// outranks reports whether rules[a] takes precedence over rules[b] when both
+// match, mirroring ResolveConflicts.
+func outranks(strategy ConflictStrategy, rules []Rule, a, b int) bool {
+	ra, rb := rules[a], rules[b]
+	switch strategy {
+	case StrategyFirstMatch:
+		return a < b
+	case StrategyMaxConfidence:
+		if ra.Then.ConfidenceBoost != rb.Then.ConfidenceBoost {
+			return ra.Then.ConfidenceBoost > rb.Then.ConfidenceBoost
+		}
+	}
+	if ra.Priority != rb.Priority {
+		return ra.Priority > rb.Priority
+	}
+	return a < b
+}
+
+func unreachableStatuses(rules []Rule, dead, shadowed []bool) []Finding {
+	type key struct{ intent, status string }
+	reachable := make(map[key]bool)
+	var order []key
+
+	for i, rule := range rules {
+		k := key{rule.Intent, rule.Then.Status}
+		if _, seen := reachable[k]; !seen {
+			order = append(order, k)
+			reachable[k] = false
+		}
+		if !dead[i] && !shadowed[i] {
+			reachable[k] = true
+		}
+	}
+
+	var out []Finding
+	for _, k := range order {
+		if reachable[k] {
+			continue
+		}
+		out = append(out, Finding{
+			Kind:    FindingUnreachableStatus,
+			Intent:  k.intent,
+			Message: fmt.Sprintf("status '%s' is only produced by dead or shadowed rules", k.status),
+		})
+	}
+	return out
+}
+
+func appendUnique(xs []string, x string) []string {
+	for _, v := range xs {
+		if v == x {
+			return xs
+		}
+	}
+	return append(xs, x)
+}
+
+/* ---------- regions ---------- */
+
+// interval is a (possibly half-open) range of signal values.
+type interval struct {
+	lo, hi         float64
+	loOpen, hiOpen bool
+}
+
+func fullInterval() interval { return interval{lo: SignalMin, hi: SignalMax} }
+
+func conditionInterval(c Condition) interval {
+	iv := fullInterval()
+	switch c.Op {
+	case "gte":
+		iv.lo = c.Value
+	case "gt":
+		iv.lo, iv.loOpen = c.Value, true
+	case "lte":
+		iv.hi = c.Value
+	case "lt":
+		iv.hi, iv.hiOpen = c.Value, true
+	case "eq":
+		iv.lo, iv.hi = c.Value, c.Value
+	}
+	return iv.intersect(fullInterval())
+}
+
+func (iv interval) intersect(o interval) interval {
+	out := iv
+	if o.lo > out.lo || (o.lo == out.lo && o.loOpen) {
+		out.lo, out.loOpen = o.lo, o.loOpen
+	}
+	if o.hi < out.hi || (o.hi == out.hi && o.hiOpen) {
+		out.hi, out.hiOpen = o.hi, o.hiOpen
+	}
+	return out
+}
+
+func (iv interval) empty() bool {
+	return iv.lo > iv.hi || (iv.lo == iv.hi && (iv.loOpen || iv.hiOpen))
+}
+
+// contains reports whether o ⊆ iv.
+func (iv interval) contains(o interval) bool {
+	if o.empty() {
+		return true
+	}
+	loOK := iv.lo < o.lo || (iv.lo == o.lo && (!iv.loOpen || o.loOpen))
+	hiOK := iv.hi > o.hi || (iv.hi == o.hi && (!iv.hiOpen || o.hiOpen))
+	return loOK && hiOK
+}
+
+func (iv interval) String() string {
+	l, r := "[", "]"
+	if iv.loOpen {
+		l = "("
+	}
+	if iv.hiOpen {
+		r = ")"
+	}
+	return fmt.Sprintf("%s%g, %g%s", l, iv.lo, iv.hi, r)
+}
+
+// region is a box in signal space. A signal present in the map must be
+// present in the snapshot (missing signals never satisfy a condition).
+type region map[string]interval
+
+func (r region) with(c Condition) region {
+	out := make(region, len(r)+1)
+	for k, v := range r {
+		out[k] = v
+	}
+	iv, ok := out[c.Signal]
+	if !ok {
+		iv = fullInterval()
+	}
+	out[c.Signal] = iv.intersect(conditionInterval(c))
+	return out
+}
+
+func (r region) empty() bool {
+	for _, iv := range r {
+		if iv.empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether o ⊆ r.
+func (r region) contains(o region) bool {
+	for sig, iv := range r {
+		oiv, ok := o[sig]
+		if !ok || !iv.contains(oiv) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r region) intersect(o region) region {
+	out := make(region, len(r)+len(o))
+	for k, v := range r {
+		out[k] = v
+	}
+	for k, v := range o {
+		if iv, ok := out[k]; ok {
+			out[k] = iv.intersect(v)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (r region) String() string {
+	sigs := make([]string, 0, len(r))
+	for s := range r {
+		sigs = append(sigs, s)
+	}
+	sort.Strings(sigs)
+
+	parts := make([]string, 0, len(sigs))
+	for _, s := range sigs {
+		parts = append(parts, fmt.Sprintf("%s ∈ %s", s, r[s]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ruleRegions returns the non-empty boxes in which the rule matches: the
+// `all` box, intersected with each `any` condition when present.
+func ruleRegions(rule Rule) []region {
+	base := region{}
+	for _, c := range rule.When.All {
+		base = base.with(c)
+	}
+	if base.empty() {
+		return nil
+	}
+	if len(rule.When.Any) == 0 {
+		return []region{base}
+	}
+
+	var out []region
+	for _, c := range rule.When.Any {
+		r := base.with(c)
+		if !r.empty() {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func deadReason(rule Rule) string {
+	base := region{}
+	for _, c := range rule.When.All {
+		base = base.with(c)
+		if iv := base[c.Signal]; iv.empty() {
+			return fmt.Sprintf("contradictory thresholds on %s", c.Signal)
+		}
+	}
+	return "no 'any' condition can hold together with the 'all' conditions"
+}
+
+func anyContains(regions []region, r region) bool {
+	for _, outer := range regions {
+		if outer.contains(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstOverlap(a, b []region) (region, bool) {
+	for _, ra := range a {
+		for _, rb := range b {
+			if x := ra.intersect(rb); !x.empty() {
+				return x, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Write prints the findings grouped by kind.
+func (a Analysis) Write(w io.Writer) {
+	if len(a.Findings) == 0 {
+		fmt.Fprintln(w, "no findings")
+		return
+	}
+	for _, f := range a.Findings {
+		subject := f.Intent
+		if f.RuleID != "" {
+			subject = f.Intent + "/" + f.RuleID
+		}
+		fmt.Fprintf(w, "%-18s %s: %s\n", f.Kind, subject, f.Message)
+	}
+}
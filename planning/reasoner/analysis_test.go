@@ -0,0 +1,122 @@
+package reasoner
+
+import "testing"
+
+func analysisRule(id string, priority int, status string, all ...Condition) Rule {
+	return Rule{
+		ID:       id,
+		Intent:   "interpret.regime_state",
+		Priority: priority,
+		When:     ConditionBlock{All: all},
+		Then:     RuleAction{Status: status, ConfidenceBoost: 0.1},
+	}
+}
+
+func TestAnalyzeRules_DeadRule(t *testing.T) {
+	rules := []Rule{
+		analysisRule("contradiction", 1, "weak_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.7},
+			Condition{Signal: "REGIME_SHIFT", Op: "lt", Value: 0.5},
+		),
+		analysisRule("out_of_range", 2, "strong_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "gt", Value: 1},
+		),
+		analysisRule("alive", 3, "moderate_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5},
+		),
+	}
+
+	a := AnalyzeRules(rules, "")
+	if !a.Has(FindingDeadRule, "contradiction") || !a.Has(FindingDeadRule, "out_of_range") {
+		t.Fatalf("expected both dead rules to be reported: %+v", a.Findings)
+	}
+	if a.Has(FindingDeadRule, "alive") {
+		t.Fatal("alive rule must not be reported dead")
+	}
+}
+
+func TestAnalyzeRules_ShadowedAndUnreachable(t *testing.T) {
+	rules := []Rule{
+		analysisRule("broad", 10, "strong_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5},
+		),
+		analysisRule("narrow", 5, "weak_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.7},
+			Condition{Signal: "CONVICTION_SPIKE", Op: "lt", Value: 0.3},
+		),
+	}
+
+	a := AnalyzeRules(rules, StrategyHighestPriority)
+	if !a.Has(FindingShadowedRule, "narrow") {
+		t.Fatalf("expected narrow to be shadowed: %+v", a.Findings)
+	}
+	if a.Has(FindingShadowedRule, "broad") {
+		t.Fatal("broad must not be shadowed")
+	}
+
+	unreachable := false
+	for _, f := range a.Findings {
+		if f.Kind == FindingUnreachableStatus && f.Intent == "interpret.regime_state" {
+			unreachable = true
+		}
+	}
+	if !unreachable {
+		t.Fatalf("expected weak_signal to be unreachable: %+v", a.Findings)
+	}
+
+	// Under first_match the narrow rule wins when declared first.
+	a = AnalyzeRules([]Rule{rules[1], rules[0]}, StrategyFirstMatch)
+	if a.Has(FindingShadowedRule, "narrow") {
+		t.Fatal("narrow must not be shadowed when it is matched first")
+	}
+}
+
+func TestAnalyzeRules_Overlap(t *testing.T) {
+	rules := []Rule{
+		analysisRule("high", 10, "strong_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.6},
+		),
+		analysisRule("low", 5, "weak_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "lte", Value: 0.6},
+		),
+		analysisRule("disjoint", 1, "moderate_signal",
+			Condition{Signal: "REGIME_SHIFT", Op: "lt", Value: 0.6},
+			Condition{Signal: "CONVICTION_SPIKE", Op: "gt", Value: 0.9},
+		),
+	}
+
+	a := AnalyzeRules(rules, "")
+	if !a.Has(FindingOverlap, "high") {
+		t.Fatalf("expected high/low overlap at REGIME_SHIFT=0.6: %+v", a.Findings)
+	}
+	for _, f := range a.Findings {
+		if f.Kind == FindingOverlap && f.RuleID == "high" && f.Related[0] == "disjoint" {
+			t.Fatal("high and disjoint must not overlap")
+		}
+	}
+}
+
+func TestRuleCounters_NeverMatched(t *testing.T) {
+	rules := []Rule{
+		analysisRule("often", 2, "strong_signal", Condition{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5}),
+		analysisRule("never", 1, "weak_signal", Condition{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.9}),
+	}
+	counters := NewRuleCounters()
+	r := &RuleBasedReasoner{Version: "v1", Rules: rules, Counters: counters}
+
+	for i := 0; i < 3; i++ {
+		if _, err := r.Evaluate("interpret.regime_state", nil,
+			[]SignalInput{{SignalID: "REGIME_SHIFT", Value: 0.6}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	snap := counters.Snapshot()
+	if snap.Evaluations["interpret.regime_state"] != 3 || snap.Matches["often"] != 3 {
+		t.Fatalf("unexpected counts: %+v", snap)
+	}
+	never := snap.NeverMatched(rules)
+	if len(never) != 1 || never[0] != "never" {
+		t.Fatalf("expected [never], got %v", never)
+	}
+}
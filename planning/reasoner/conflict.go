@@ -0,0 +1,169 @@
+package reasoner
+
+import (
+	"fmt"
+	"sort"
+
+	"woodpecker/planning/intents"
+)
+
+// ConflictStrategy decides how several matched rules are combined into a
+// single status and confidence.
+type ConflictStrategy string
+
+const (
+	// StrategyAdditive takes the status of the highest-priority rule and sums
+	// every matched confidence_boost (clamped to 1). It is the default.
+	StrategyAdditive ConflictStrategy = "additive"
+
+	// StrategyFirstMatch uses the first matched rule in declaration order.
+	StrategyFirstMatch ConflictStrategy = "first_match"
+
+	// StrategyHighestPriority uses only the highest-priority rule.
+	StrategyHighestPriority ConflictStrategy = "highest_priority"
+
+	// StrategyMaxConfidence uses the rule with the largest confidence_boost.
+	StrategyMaxConfidence ConflictStrategy = "max_confidence"
+
+	// StrategyWeightedAverage takes the status of the highest-priority rule and
+	// averages confidence boosts weighted by (priority + 1).
+	StrategyWeightedAverage ConflictStrategy = "weighted_average"
+
+	// StrategyNoisyOr takes the status of the highest-priority rule and
+	// combines boosts as independent evidence: 1 - Π(1 - boost).
+	StrategyNoisyOr ConflictStrategy = "noisy_or"
+)
+
+// IsValid reports whether s is a known strategy. The empty strategy is valid
+// and means StrategyAdditive.
+func (s ConflictStrategy) IsValid() bool {
+	switch s {
+	case "",
+		StrategyAdditive,
+		StrategyFirstMatch,
+		StrategyHighestPriority,
+		StrategyMaxConfidence,
+		StrategyWeightedAverage,
+		StrategyNoisyOr:
+		return true
+	default:
+		return false
+	}
+}
+
+// orDefault returns StrategyAdditive for the empty strategy.
+func (s ConflictStrategy) orDefault() ConflictStrategy {
+	if s == "" {
+		return StrategyAdditive
+	}
+	return s
+}
+
+// Resolution is the outcome of combining matched rules.
+type Resolution struct {
+	Strategy   ConflictStrategy
+	Status     intents.IntentStatus
+	Confidence float64
+
+	// Contributing lists the rules that influenced the result, in precedence order.
+	Contributing []Rule
+}
+
+// ResolveConflicts combines matched rules (in declaration order) using the
+// given strategy. Ties on priority are broken by declaration order.
+func ResolveConflicts(strategy ConflictStrategy, matched []Rule) (Resolution, error) {
+	strategy = strategy.orDefault()
+	res := Resolution{
+		Strategy: strategy,
+		Status:   intents.StatusLowConfidence,
+	}
+
+	if !strategy.IsValid() {
+		return res, fmt.Errorf("unknown conflict strategy '%s'", strategy)
+	}
+	if len(matched) == 0 {
+		return res, nil
+	}
+
+	// Precedence: priority DESC, declaration order for ties.
+	byPriority := make([]Rule, len(matched))
+	copy(byPriority, matched)
+	sort.SliceStable(byPriority, func(i, j int) bool {
+		return byPriority[i].Priority > byPriority[j].Priority
+	})
+	top := byPriority[0]
+
+	switch strategy {
+	case StrategyAdditive:
+		res.Status = intents.IntentStatus(top.Then.Status)
+		for _, rule := range byPriority {
+			res.Confidence += rule.Then.ConfidenceBoost
+		}
+		res.Contributing = byPriority
+
+	case StrategyFirstMatch:
+		first := matched[0]
+		res.Status = intents.IntentStatus(first.Then.Status)
+		res.Confidence = first.Then.ConfidenceBoost
+		res.Contributing = []Rule{first}
+
+	case StrategyHighestPriority:
+		res.Status = intents.IntentStatus(top.Then.Status)
+		res.Confidence = top.Then.ConfidenceBoost
+		res.Contributing = []Rule{top}
+
+	case StrategyMaxConfidence:
+		best := byPriority[0]
+		for _, rule := range byPriority[1:] {
+			if rule.Then.ConfidenceBoost > best.Then.ConfidenceBoost {
+				best = rule
+			}
+		}
+		res.Status = intents.IntentStatus(best.Then.Status)
+		res.Confidence = best.Then.ConfidenceBoost
+		res.Contributing = []Rule{best}
+
+	case StrategyWeightedAverage:
+		var sum, weights float64
+		for _, rule := range byPriority {
+			w := float64(rule.Priority + 1)
+			sum += w * rule.Then.ConfidenceBoost
+			weights += w
+		}
+		res.Status = intents.IntentStatus(top.Then.Status)
+		res.Confidence = sum / weights
+		res.Contributing = byPriority
+
+	case StrategyNoisyOr:
+		miss := 1.0
+		for _, rule := range byPriority {
+			miss *= 1 - rule.Then.ConfidenceBoost
+		}
+		res.Status = intents.IntentStatus(top.Then.Status)
+		res.Confidence = 1 - miss
+		res.Contributing = byPriority
+	}
+
+	if res.Confidence > 1 {
+		res.Confidence = 1
+	}
+
+	return res, nil
+}
+
+// Trace converts the resolution into its structured trace form.
+func (res Resolution) Trace() intents.ResolutionTrace {
+	t := intents.ResolutionTrace{
+		Strategy:            string(res.Strategy),
+		Status:              res.Status,
+		Confidence:          res.Confidence,
+		ContributingRuleIDs: []string{},
+	}
+	for i, rule := range res.Contributing {
+		if i == 0 {
+			t.WinningRuleID = rule.ID
+		}
+		t.ContributingRuleIDs = append(t.ContributingRuleIDs, rule.ID)
+	}
+	return t
+}
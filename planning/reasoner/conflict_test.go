@@ -0,0 +1,77 @@
+package reasoner
+
+import (
+	"math"
+	"testing"
+
+	"woodpecker/planning/intents"
+)
+
+func conflictRules() []Rule {
+	return []Rule{
+		{ID: "early", Priority: 10, Then: RuleAction{Status: "weak_signal", ConfidenceBoost: 0.2}},
+		{ID: "confirmed", Priority: 30, Then: RuleAction{Status: "strong_signal", ConfidenceBoost: 0.5}},
+		{ID: "noisy", Priority: 30, Then: RuleAction{Status: "strong_signal", ConfidenceBoost: 0.6}},
+	}
+}
+
+func TestResolveConflicts_Strategies(t *testing.T) {
+	cases := []struct {
+		strategy   ConflictStrategy
+		status     intents.IntentStatus
+		confidence float64
+		first      string
+	}{
+		{"", intents.StatusStrongSignal, 1, "confirmed"},
+		{StrategyFirstMatch, intents.StatusWeakSignal, 0.2, "early"},
+		{StrategyHighestPriority, intents.StatusStrongSignal, 0.5, "confirmed"},
+		{StrategyMaxConfidence, intents.StatusStrongSignal, 0.6, "noisy"},
+		{StrategyWeightedAverage, intents.StatusStrongSignal, (11*0.2 + 31*0.5 + 31*0.6) / 73, "confirmed"},
+		{StrategyNoisyOr, intents.StatusStrongSignal, 1 - 0.8*0.5*0.4, "confirmed"},
+	}
+
+	for _, tc := range cases {
+		res, err := ResolveConflicts(tc.strategy, conflictRules())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.strategy, err)
+		}
+		if res.Status != tc.status {
+			t.Fatalf("%s: expected %s, got %s", tc.strategy, tc.status, res.Status)
+		}
+		if math.Abs(res.Confidence-tc.confidence) > 1e-9 {
+			t.Fatalf("%s: expected confidence %v, got %v", tc.strategy, tc.confidence, res.Confidence)
+		}
+		if res.Contributing[0].ID != tc.first {
+			t.Fatalf("%s: expected %s first, got %s", tc.strategy, tc.first, res.Contributing[0].ID)
+		}
+	}
+}
+
+func TestResolveConflicts_StableTieBreak(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		res, err := ResolveConflicts(StrategyAdditive, conflictRules())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Contributing[0].ID != "confirmed" || res.Contributing[1].ID != "noisy" {
+			t.Fatalf("expected declaration order on ties, got %s, %s",
+				res.Contributing[0].ID, res.Contributing[1].ID)
+		}
+	}
+}
+
+func TestResolveConflicts_NoMatch(t *testing.T) {
+	res, err := ResolveConflicts(StrategyNoisyOr, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != intents.StatusLowConfidence || res.Confidence != 0 {
+		t.Fatalf("expected low_confidence with 0 confidence, got %s %v", res.Status, res.Confidence)
+	}
+}
+
+func TestResolveConflicts_UnknownStrategy(t *testing.T) {
+	if _, err := ResolveConflicts("majority_vote", conflictRules()); err == nil {
+		t.Fatal("expected error for unknown strategy")
+	}
+}
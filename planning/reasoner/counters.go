@@ -0,0 +1,87 @@
+package reasoner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// RuleCounters records, at runtime, how often each intent was evaluated and
+// how often each rule matched. It is safe for concurrent use.
+type RuleCounters struct {
+	mu          sync.Mutex
+	evaluations map[string]int64
+	matches     map[string]int64
+}
+
+// CounterSnapshot is a point-in-time copy of RuleCounters.
+type CounterSnapshot struct {
+	Evaluations map[string]int64 `json:"evaluations"` // by intent
+	Matches     map[string]int64 `json:"matches"`     // by rule ID
+}
+
+func NewRuleCounters() *RuleCounters {
+	return &RuleCounters{
+		evaluations: make(map[string]int64),
+		matches:     make(map[string]int64),
+	}
+}
+
+// Record counts one evaluation of intentID and a match for every matched rule.
+func (c *RuleCounters) Record(intentID string, matched []Rule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evaluations[intentID]++
+	for _, r := range matched {
+		c.matches[r.ID]++
+	}
+}
+
+// Snapshot returns a copy of the current counts.
+func (c *RuleCounters) Snapshot() CounterSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := CounterSnapshot{
+		Evaluations: make(map[string]int64, len(c.evaluations)),
+		Matches:     make(map[string]int64, len(c.matches)),
+	}
+	for k, v := range c.evaluations {
+		s.Evaluations[k] = v
+	}
+	for k, v := range c.matches {
+		s.Matches[k] = v
+	}
+	return s
+}
+
+// NeverMatched returns the IDs of rules whose intent was evaluated at least
+// once but which never matched.
+func (s CounterSnapshot) NeverMatched(rules []Rule) []string {
+	var out []string
+	for _, r := range rules {
+		if s.Evaluations[r.Intent] > 0 && s.Matches[r.ID] == 0 {
+			out = append(out, r.ID)
+		}
+	}
+	return out
+}
+
+// Write prints per-rule match counts and rates, sorted by intent then rule order.
+func (s CounterSnapshot) Write(w io.Writer, rules []Rule) {
+	sorted := make([]Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Intent < sorted[j].Intent })
+
+	for _, r := range sorted {
+		evals := s.Evaluations[r.Intent]
+		rate := 0.0
+		if evals > 0 {
+			rate = float64(s.Matches[r.ID]) / float64(evals)
+		}
+		fmt.Fprintf(w, "%-28s %-32s %6d / %-6d (%5.1f%%)\n",
+			r.Intent, r.ID, s.Matches[r.ID], evals, 100*rate)
+	}
+}
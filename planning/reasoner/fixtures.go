@@ -0,0 +1,229 @@
+package reasoner
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFixtureTolerance is the allowed confidence difference when a case
+// does not set its own tolerance.
+const DefaultFixtureTolerance = 1e-6
+
+// FixtureFile is a YAML file of signal snapshots with expected outcomes.
+// It lets rule authors check a ruleset without writing Go.
+type FixtureFile struct {
+	Cases []FixtureCase `yaml:"cases"`
+}
+
+// FixtureCase is one signal snapshot evaluated against an intent.
+type FixtureCase struct {
+	Name    string             `yaml:"name"`
+	Intent  string             `yaml:"intent"`
+	Params  map[string]any     `yaml:"params,omitempty"`
+	Signals map[string]float64 `yaml:"signals"`
+	Expect  FixtureExpectation `yaml:"expect"`
+
+	// Source is the fixture file the case was loaded from.
+	Source string `yaml:"-"`
+}
+
+// FixtureExpectation lists the checks for a case. Unset fields are not checked;
+// `matched_rules: []` asserts that no rule matched.
+type FixtureExpectation struct {
+	Status       string    `yaml:"status,omitempty"`
+	Confidence   *float64  `yaml:"confidence,omitempty"`
+	Tolerance    float64   `yaml:"tolerance,omitempty"`
+	MatchedRules *[]string `yaml:"matched_rules,omitempty"`
+}
+
+// FixtureDiff is a single mismatch between expected and actual outcome.
+type FixtureDiff struct {
+	Field    string
+	Expected string
+	Actual   string
+}
+
+// FixtureResult is the outcome of running one case.
+type FixtureResult struct {
+	Case   FixtureCase
+	Diffs  []FixtureDiff
+	Err    error
+	Passed bool
+}
+
+// FixtureReport aggregates the results of a fixture run.
+type FixtureReport struct {
+	Results []FixtureResult
+	Passed  int
+	Failed  int
+}
+
+// LoadFixtures loads cases from a fixture file or from every *.yaml / *.yml
+// file in a directory.
+func LoadFixtures(path string) ([]FixtureCase, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = rulesetFiles(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var cases []FixtureCase
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		var ff FixtureFile
+		if err := yaml.Unmarshal(data, &ff); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		for i, c := range ff.Cases {
+			if c.Intent == "" {
+				return nil, fmt.Errorf("%s: case[%d] (%s): intent must not be empty", f, i, c.Name)
+			}
+			if c.Name == "" {
+				c.Name = fmt.Sprintf("%s#%d", filepath.Base(f), i+1)
+			}
+			c.Source = f
+			cases = append(cases, c)
+		}
+	}
+	return cases, nil
+}
+
+// RunFixtures evaluates every case through the rule engine and compares the
+// outcome with its expectation.
+func RunFixtures(rulesets []Ruleset, cases []FixtureCase) (FixtureReport, error) {
+	router, err := NewRulesetRouter("fixtures", rulesets)
+	if err != nil {
+		return FixtureReport{}, err
+	}
+	return RunFixturesWith(router, cases), nil
+}
+
+// RunFixturesWith runs cases through an existing router, e.g. one with
+// RuleCounters attached.
+func RunFixturesWith(router *RulesetRouter, cases []FixtureCase) FixtureReport {
+	var report FixtureReport
+	for _, c := range cases {
+		res := runFixture(router, c)
+		if res.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+func runFixture(router *RulesetRouter, c FixtureCase) FixtureResult {
+	res := FixtureResult{Case: c}
+
+	names := make([]string, 0, len(c.Signals))
+	for name := range c.Signals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	signals := make([]SignalInput, 0, len(names))
+	for _, name := range names {
+		signals = append(signals, SignalInput{SignalID: name, Value: c.Signals[name]})
+	}
+
+	out, err := router.Evaluate(c.Intent, c.Params, signals)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	exp := c.Expect
+	if exp.Status != "" && exp.Status != string(out.Status) {
+		res.Diffs = append(res.Diffs, FixtureDiff{
+			Field:    "status",
+			Expected: exp.Status,
+			Actual:   string(out.Status),
+		})
+	}
+
+	if exp.Confidence != nil {
+		tol := exp.Tolerance
+		if tol <= 0 {
+			tol = DefaultFixtureTolerance
+		}
+		if math.Abs(*exp.Confidence-out.Confidence) > tol {
+			res.Diffs = append(res.Diffs, FixtureDiff{
+				Field:    "confidence",
+				Expected: fmt.Sprintf("%.4f (±%g)", *exp.Confidence, tol),
+				Actual:   fmt.Sprintf("%.4f", out.Confidence),
+			})
+		}
+	}
+
+	if exp.MatchedRules != nil {
+		var rules []Rule
+		if rb, ok := router.byIntent[c.Intent]; ok {
+			rules = rb.Rules
+		}
+		signalMap := make(map[string]float64, len(signals))
+		for _, s := range signals {
+			signalMap[s.SignalID] = s.Value
+		}
+		matched, err := EvaluateRules(c.Intent, signalMap, rules)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+
+		actual := make([]string, 0, len(matched))
+		for _, r := range matched {
+			actual = append(actual, r.ID)
+		}
+		expected := append([]string(nil), (*exp.MatchedRules)...)
+		sort.Strings(actual)
+		sort.Strings(expected)
+
+		if strings.Join(actual, ",") != strings.Join(expected, ",") {
+			res.Diffs = append(res.Diffs, FixtureDiff{
+				Field:    "matched_rules",
+				Expected: "[" + strings.Join(expected, ", ") + "]",
+				Actual:   "[" + strings.Join(actual, ", ") + "]",
+			})
+		}
+	}
+
+	res.Passed = len(res.Diffs) == 0
+	return res
+}
+
+// Write prints a human-readable report: one line per case, diffs for failures.
+func (r FixtureReport) Write(w io.Writer) {
+	for _, res := range r.Results {
+		switch {
+		case res.Err != nil:
+			fmt.Fprintf(w, "ERROR %s (%s): %v\n", res.Case.Name, res.Case.Intent, res.Err)
+		case res.Passed:
+			fmt.Fprintf(w, "PASS  %s (%s)\n", res.Case.Name, res.Case.Intent)
+		default:
+			fmt.Fprintf(w, "FAIL  %s (%s)\n", res.Case.Name, res.Case.Intent)
+			for _, d := range res.Diffs {
+				fmt.Fprintf(w, "      %s: expected %s, got %s\n", d.Field, d.Expected, d.Actual)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", r.Passed, r.Failed)
+}
@@ -0,0 +1,75 @@
+package reasoner
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFixtures_Shipped(t *testing.T) {
+	rulesets, err := LoadRulesets("../rules")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases, err := LoadFixtures("../rules/fixtures")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := RunFixtures(rulesets, cases)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Failed > 0 {
+		var buf bytes.Buffer
+		report.Write(&buf)
+		t.Fatalf("shipped fixtures failed:\n%s", buf.String())
+	}
+}
+
+func TestRunFixtures_ReportsDiffs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.yaml")
+	writeFile(t, path, `
+cases:
+  - name: wrong expectations
+    intent: interpret.regime_state
+    signals:
+      REGIME_SHIFT: 0.6
+    expect:
+      status: strong_signal
+      confidence: 0.5
+      matched_rules: []
+`)
+
+	cases, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rules := []Rule{{
+		ID:     "regime_weak",
+		Intent: "interpret.regime_state",
+		When:   ConditionBlock{All: []Condition{{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5}}},
+		Then:   RuleAction{Status: "weak_signal", ConfidenceBoost: 0.2},
+	}}
+
+	report, err := RunFixtures([]Ruleset{{RulesetID: "test", Rules: rules}}, cases)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Failed != 1 {
+		t.Fatalf("expected 1 failure, got %d", report.Failed)
+	}
+
+	diffs := report.Results[0].Diffs
+	if len(diffs) != 3 {
+		t.Fatalf("expected 3 diffs, got %+v", diffs)
+	}
+
+	var buf bytes.Buffer
+	report.Write(&buf)
+	if !strings.Contains(buf.String(), "matched_rules: expected [], got [regime_weak]") {
+		t.Fatalf("unexpected report:\n%s", buf.String())
+	}
+}
@@ -1,6 +1,11 @@
 package reasoner
 
-import "woodpecker/planning/intents"
+import (
+	"strconv"
+	"time"
+
+	"woodpecker/planning/intents"
+)
 
 type IntentReasoner interface {
 	Evaluate(
@@ -13,4 +18,39 @@ type IntentReasoner interface {
 type SignalInput struct {
 	SignalID string
 	Value    float64
+
+	// ObservedAt is when the signal was computed; zero when unknown.
+	ObservedAt time.Time
+}
+
+// SignalWeighter provides per-intent relative signal weights from config.
+// intents.SignalMap implements it.
+type SignalWeighter interface {
+	Weight(intentID, signalID string) float64
+}
+
+// ExplainRequested reports whether params ask for a structured trace via a
+// truthy "explain" or "debug" entry (true, "true", "1", 1).
+func ExplainRequested(params map[string]any) bool {
+	for _, key := range []string{"explain", "debug"} {
+		switch v := params[key].(type) {
+		case bool:
+			if v {
+				return true
+			}
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil && b {
+				return true
+			}
+		case float64:
+			if v != 0 {
+				return true
+			}
+		case int:
+			if v != 0 {
+				return true
+			}
+		}
+	}
+	return false
 }
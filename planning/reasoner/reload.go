@@ -0,0 +1,300 @@
+package reasoner
+
+import (
+	"context"
+	"crypto/sha256"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"woodpecker/planning/intents"
+)
+
+// DefaultReloadInterval is how often a RulesetWatcher polls its path.
+const DefaultReloadInterval = 2 * time.Second
+
+// ReloadStatus describes the rulesets currently served by a RulesetWatcher
+// and the outcome of the last reload attempt.
+type ReloadStatus struct {
+	Path     string          `json:"path"`
+	Rulesets []RulesetStatus `json:"rulesets"`
+	LoadedAt time.Time       `json:"loaded_at"`
+
+	// UncoveredIntents are registered intents without rules (requires
+	// WithIntentCoverage); they are rejected as unknown until rules exist.
+	UncoveredIntents []string `json:"uncovered_intents,omitempty"`
+
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// RulesetStatus summarizes one active ruleset.
+type RulesetStatus struct {
+	RulesetID   string `json:"ruleset_id"`
+	Version     string `json:"version"`
+	ContentHash string `json:"content_hash"`
+	Strategy    string `json:"strategy"`
+	RuleCount   int    `json:"rule_count"`
+	Source      string `json:"source"`
+}
+
+// RulesetWatcher serves the rulesets found at Path (a file or a directory)
+// and swaps them atomically when anything under it changes. Rulesets that
+// fail to load or validate are rejected and the previous ones keep serving.
+type RulesetWatcher struct {
+	Path     string
+	Version  string
+	Interval time.Duration
+
+	// Checks run on every candidate set of rulesets; any error rejects it.
+	Checks []RulesetCheck
+	// Weights and Counters are attached to every activated ruleset.
+	Weights  SignalWeighter
+	Counters *RuleCounters
+	// Registry, when set, is checked against and reported on (see
+	// WithIntentCoverage).
+	Registry *intents.Registry
+
+	current atomic.Pointer[RulesetRouter]
+
+	mu       sync.Mutex
+	status   ReloadStatus
+	lastSeen [sha256.Size]byte
+	// transient is set while status.LastError is a fingerprint error (e.g.
+	// a file caught mid-write), cleared by the next successful poll.
+	transient bool
+}
+
+// WatcherOption configures a RulesetWatcher before its initial load.
+type WatcherOption func(*RulesetWatcher)
+
+// WithChecks adds validations applied to the initial load and every reload.
+func WithChecks(checks ...RulesetCheck) WatcherOption {
+	return func(w *RulesetWatcher) { w.Checks = append(w.Checks, checks...) }
+}
+
+// WithSignalWeights attaches per-intent signal weights to served rulesets.
+func WithSignalWeights(sw SignalWeighter) WatcherOption {
+	return func(w *RulesetWatcher) { w.Weights = sw }
+}
+
+// WithIntentCoverage rejects rules for intents missing from registry and
+// reports registered intents without rules in Status (and the log).
+func WithIntentCoverage(registry *intents.Registry) WatcherOption {
+	return func(w *RulesetWatcher) {
+		w.Registry = registry
+		w.Checks = append(w.Checks, CheckIntentCoverage(registry))
+	}
+}
+
+// WithCounters records rule matches of served rulesets in c.
+func WithCounters(c *RuleCounters) WatcherOption {
+	return func(w *RulesetWatcher) { w.Counters = c }
+}
+
+// NewRulesetWatcher loads path once (fail-fast) and returns a watcher serving it.
+func NewRulesetWatcher(path, version string, opts ...WatcherOption) (*RulesetWatcher, error) {
+	w := &RulesetWatcher{
+		Path:     path,
+		Version:  version,
+		Interval: DefaultReloadInterval,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Evaluate delegates to the currently active rulesets.
+func (w *RulesetWatcher) Evaluate(
+	intentID string,
+	params map[string]any,
+	signals []SignalInput,
+) (intents.IntentOutput, error) {
+	return w.current.Load().Evaluate(intentID, params, signals)
+}
+
+// Rulesets returns the currently active rulesets.
+func (w *RulesetWatcher) Rulesets() []Ruleset {
+	return w.current.Load().Rulesets()
+}
+
+// Reload loads, validates and activates the rulesets. On failure the active
+// rulesets are left untouched and the error is recorded in Status.
+func (w *RulesetWatcher) Reload() error {
+	sum, err := fingerprint(w.Path)
+	if err != nil {
+		w.recordError(err)
+		w.setTransient(true)
+		return err
+	}
+	return w.reload(sum)
+}
+
+func (w *RulesetWatcher) reload(sum [sha256.Size]byte) error {
+	w.mu.Lock()
+	w.lastSeen = sum
+	w.mu.Unlock()
+
+	rulesets, err := LoadRulesets(w.Path)
+	if err != nil {
+		w.recordError(err)
+		return err
+	}
+	for _, check := range w.Checks {
+		if err := check(rulesets); err != nil {
+			w.recordError(err)
+			return err
+		}
+	}
+	router, err := NewRulesetRouter(w.Version, rulesets)
+	if err != nil {
+		w.recordError(err)
+		return err
+	}
+	router.SetWeights(w.Weights)
+	router.SetCounters(w.Counters)
+
+	w.current.Store(router)
+
+	status := ReloadStatus{
+		Path:     w.Path,
+		LoadedAt: time.Now().UTC(),
+	}
+	if w.Registry != nil {
+		status.UncoveredIntents = UncoveredIntents(w.Registry, rulesets)
+		if len(status.UncoveredIntents) > 0 {
+			log.Printf("⚠️ rules: intents without rules (rejected with ErrNoRules): %v", status.UncoveredIntents)
+		}
+	}
+	for _, rs := range rulesets {
+		status.Rulesets = append(status.Rulesets, RulesetStatus{
+			RulesetID:   rs.RulesetID,
+			Version:     rs.Version,
+			ContentHash: rs.ContentHash,
+			Strategy:    string(rs.Strategy.orDefault()),
+			RuleCount:   len(rs.Rules),
+			Source:      rs.Source,
+		})
+	}
+
+	w.mu.Lock()
+	w.status = status
+	w.mu.Unlock()
+
+	return nil
+}
+
+// Watch polls Path until ctx is cancelled and reloads whenever any YAML file
+// under it changes.
+func (w *RulesetWatcher) Watch(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+func (w *RulesetWatcher) poll() {
+	sum, err := fingerprint(w.Path)
+	if err != nil {
+		w.recordError(err)
+		w.setTransient(true)
+		return
+	}
+
+	w.mu.Lock()
+	if w.transient {
+		w.status.LastError = ""
+		w.status.LastErrorAt = nil
+		w.transient = false
+	}
+	unchanged := sum == w.lastSeen
+	w.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	_ = w.reload(sum)
+}
+
+// Status returns a copy of the current reload status.
+func (w *RulesetWatcher) Status() ReloadStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *RulesetWatcher) setTransient(v bool) {
+	w.mu.Lock()
+	w.transient = v
+	w.mu.Unlock()
+}
+
+func (w *RulesetWatcher) recordError(err error) {
+	now := time.Now().UTC()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.Path = w.Path
+	w.status.LastError = err.Error()
+	w.status.LastErrorAt = &now
+	w.transient = false
+}
+
+// fingerprint hashes the names and contents of every YAML file that can
+// affect the rulesets at path: the directory tree for a directory, or the
+// file's own directory tree (where its includes live) for a file.
+func fingerprint(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return sum, err
+	}
+	root := path
+	if !info.IsDir() {
+		root = filepath.Dir(path)
+	}
+
+	h := sha256.New()
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isYAML(d.Name()) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		h.Write([]byte(p))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return sum, err
+	}
+
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
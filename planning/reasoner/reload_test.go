@@ -0,0 +1,205 @@
+package reasoner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"woodpecker/planning/intents"
+)
+
+const reloadRulesV1 = `
+version: v1
+rules:
+  - id: weak
+    intent: interpret.regime_state
+    when:
+      all:
+        - signal: REGIME_SHIFT
+          op: gte
+          value: 0.5
+    then:
+      status: weak_signal
+      confidence_boost: 0.2
+`
+
+const reloadRulesV2 = `
+version: v2
+rules:
+  - id: strong
+    intent: interpret.regime_state
+    when:
+      all:
+        - signal: REGIME_SHIFT
+          op: gte
+          value: 0.5
+    then:
+      status: strong_signal
+      confidence_boost: 0.6
+`
+
+const reloadRulesInvalid = `
+version: v3
+rules:
+  - id: broken
+    intent: interpret.regime_state
+    when:
+      all:
+        - signal: REGIME_SHIFT
+          op: approx
+          value: 0.5
+    then:
+      status: strong_signal
+`
+
+func TestRulesetWatcher_ReloadKeepsOldOnFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, reloadRulesV1)
+
+	w, err := NewRulesetWatcher(path, "v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertStatus(t, w, intents.StatusWeakSignal)
+
+	writeFile(t, path, reloadRulesV2)
+	w.poll()
+	assertStatus(t, w, intents.StatusStrongSignal)
+	if got := w.Status().Rulesets[0].Version; got != "v2" {
+		t.Fatalf("expected ruleset version v2, got %s", got)
+	}
+
+	writeFile(t, path, reloadRulesInvalid)
+	w.poll()
+	assertStatus(t, w, intents.StatusStrongSignal)
+
+	st := w.Status()
+	if st.Rulesets[0].Version != "v2" {
+		t.Fatalf("expected v2 to stay active, got %s", st.Rulesets[0].Version)
+	}
+	if st.LastError == "" || st.LastErrorAt == nil {
+		t.Fatal("expected load error to be recorded")
+	}
+}
+
+func TestRulesetWatcher_TransientErrorCleared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, reloadRulesV1)
+
+	w, err := NewRulesetWatcher(path, "v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The file disappears for one poll, then comes back unchanged.
+	moved := path + ".tmp"
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatal(err)
+	}
+	w.poll()
+	if w.Status().LastError == "" {
+		t.Fatal("expected the fingerprint error to be recorded")
+	}
+	if err := os.Rename(moved, path); err != nil {
+		t.Fatal(err)
+	}
+	w.poll()
+	if st := w.Status(); st.LastError != "" || st.LastErrorAt != nil {
+		t.Fatalf("expected the transient error to be cleared, got %q", st.LastError)
+	}
+
+	// A rejected ruleset stays reported while the content is unchanged.
+	writeFile(t, path, reloadRulesInvalid)
+	w.poll()
+	w.poll()
+	if w.Status().LastError == "" {
+		t.Fatal("expected the load error to stay recorded")
+	}
+}
+
+func TestRulesetWatcher_ReportsUncoveredIntents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, reloadRulesV1)
+	registry, err := intents.NewRegistry([]intents.Intent{
+		{ID: "interpret.regime_state", Category: "interpret"},
+		{ID: "trigger.regime_change", Category: "trigger"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewRulesetWatcher(path, "v1", WithIntentCoverage(registry))
+	if err != nil {
+		t.Fatalf("intents without rules must not fail the load: %v", err)
+	}
+	if got := w.Status().UncoveredIntents; len(got) != 1 || got[0] != "trigger.regime_change" {
+		t.Fatalf("expected the uncovered intent reported, got %v", got)
+	}
+	if _, err := w.Evaluate("trigger.regime_change", nil, nil); !errors.Is(err, ErrNoRules) {
+		t.Fatalf("expected an unruled intent to be rejected with ErrNoRules, got %v", err)
+	}
+}
+
+func TestRulesetWatcher_ConcurrentEvaluate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, reloadRulesV1)
+
+	w, err := NewRulesetWatcher(path, "v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				out, err := w.Evaluate("interpret.regime_state", nil,
+					[]SignalInput{{SignalID: "REGIME_SHIFT", Value: 0.6}})
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if out.Status != intents.StatusWeakSignal && out.Status != intents.StatusStrongSignal {
+					t.Errorf("unexpected status %s", out.Status)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			writeFile(t, path, reloadRulesV2)
+		} else {
+			writeFile(t, path, reloadRulesV1)
+		}
+		w.poll()
+	}
+	wg.Wait()
+}
+
+func assertStatus(t *testing.T, w *RulesetWatcher, want intents.IntentStatus) {
+	t.Helper()
+	out, err := w.Evaluate("interpret.regime_state", nil,
+		[]SignalInput{{SignalID: "REGIME_SHIFT", Value: 0.6}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != want {
+		t.Fatalf("expected %s, got %s", want, out.Status)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,15 +1,28 @@
 package reasoner
 
 import (
-	"sort"
 	"time"
 
 	"woodpecker/planning/intents"
 )
 
 type RuleBasedReasoner struct {
-	Version string
-	Rules   []Rule
+	Version  string
+	Rules    []Rule
+	Strategy ConflictStrategy // empty means StrategyAdditive
+	Ruleset  RulesetRef       // stamped on IntentOutput.Meta
+	Counters *RuleCounters    // optional runtime match counters
+	Weights  SignalWeighter   // optional per-intent signal weights
+}
+
+// NewRuleBasedReasoner builds a reasoner serving a single loaded ruleset.
+func NewRuleBasedReasoner(version string, rs Ruleset) *RuleBasedReasoner {
+	return &RuleBasedReasoner{
+		Version:  version,
+		Rules:    rs.Rules,
+		Strategy: rs.Strategy,
+		Ruleset:  rs.Ref(),
+	}
 }
 
 func (r *RuleBasedReasoner) Evaluate(
@@ -29,38 +42,34 @@ func (r *RuleBasedReasoner) Evaluate(
 	if err != nil {
 		return intents.IntentOutput{}, err
 	}
+	if r.Counters != nil {
+		r.Counters.Record(intentID, matchedRules)
+	}
 
-	// 3️⃣ Resolver precedencia (priority DESC)
-	sort.Slice(matchedRules, func(i, j int) bool {
-		return matchedRules[i].Priority > matchedRules[j].Priority
-	})
+	// 3️⃣ Resolver conflictos (priority DESC, orden de declaración en empates).
+	// Si no matchea nada, la estrategia devuelve low_confidence explícito.
+	resolution, err := ResolveConflicts(r.Strategy, matchedRules)
+	if err != nil {
+		return intents.IntentOutput{}, err
+	}
 
-	status := intents.StatusNotTriggered
-	confidence := 0.0
+	// 4️⃣ Aplicar reglas que contribuyeron al resultado
 	reasonSteps := []intents.ReasoningStep{}
-
-	// 4️⃣ Aplicar reglas
-	for i, rule := range matchedRules {
-		// status siempre viene de la regla de mayor prioridad
-		if i == 0 {
-			status = intents.IntentStatus(rule.Then.Status)
-		}
-
-		confidence += rule.Then.ConfidenceBoost
-
+	for i, rule := range resolution.Contributing {
 		reasonSteps = append(reasonSteps, intents.ReasoningStep{
 			Step:        i + 1,
 			Description: rule.Explanation,
+			RuleID:      rule.ID,
 		})
 	}
 
-	if confidence > 1 {
-		confidence = 1
-	}
-
-	// 5️⃣ Fallback explícito si no matchea nada
-	if len(matchedRules) == 0 {
-		status = intents.StatusLowConfidence
+	// 5️⃣ Traza estructurada (solo si se pide explain/debug)
+	var trace *intents.Trace
+	if ExplainRequested(params) {
+		trace = &intents.Trace{
+			Rules:      TraceRules(intentID, signalMap, r.Rules),
+			Resolution: resolution.Trace(),
+		}
 	}
 
 	return intents.IntentOutput{
@@ -68,11 +77,15 @@ func (r *RuleBasedReasoner) Evaluate(
 			IntentID:  intentID,
 			Timestamp: time.Now().UTC(),
 			Version:   r.Version,
+
+			RulesetID:      r.Ruleset.ID,
+			RulesetVersion: r.Ruleset.Version,
+			RulesetHash:    r.Ruleset.ContentHash,
 		},
-		Status:     status,
-		Confidence: confidence,
+		Status:     resolution.Status,
+		Confidence: resolution.Confidence,
 		Summary:    "Intent evaluated using declarative rule engine.",
-		Signals:    mapSignals(signals),
+		Signals:    contributionWeights(intentID, signals, resolution.Contributing, r.Weights),
 		Reasoning: intents.Reasoning{
 			Logic:       reasonSteps,
 			Explanation: "Declarative rules matched the current signal snapshot.",
@@ -80,23 +93,6 @@ func (r *RuleBasedReasoner) Evaluate(
 		Guardrails: &intents.Guardrails{
 			HumanConfirmationRequired: true,
 		},
+		Trace: trace,
 	}, nil
 }
-
-func mapSignals(inputs []SignalInput) []intents.SignalUsage {
-	if len(inputs) == 0 {
-		return nil
-	}
-
-	w := 1.0 / float64(len(inputs))
-	out := make([]intents.SignalUsage, 0, len(inputs))
-
-	for _, s := range inputs {
-		out = append(out, intents.SignalUsage{
-			SignalID: s.SignalID,
-			Value:    s.Value,
-			Weight:   w,
-		})
-	}
-	return out
-}
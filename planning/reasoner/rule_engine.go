@@ -1,6 +1,10 @@
 package reasoner
 
-import "fmt"
+import (
+	"fmt"
+
+	"woodpecker/planning/intents"
+)
 
 // EvaluateRules returns all rules that match the given intent and signal snapshot.
 func EvaluateRules(
@@ -70,3 +74,52 @@ func evaluateCondition(cond Condition, signals map[string]float64) bool {
 		panic(fmt.Sprintf("unsupported operator: %s", cond.Op))
 	}
 }
+
+// TraceRules evaluates every rule for the intent, without short-circuiting,
+// and records each condition with the observed value and its outcome.
+func TraceRules(
+	intentID string,
+	signals map[string]float64,
+	rules []Rule,
+) []intents.RuleTrace {
+
+	traces := []intents.RuleTrace{}
+
+	for _, rule := range rules {
+		if rule.Intent != intentID {
+			continue
+		}
+
+		rt := intents.RuleTrace{
+			RuleID:     rule.ID,
+			Priority:   rule.Priority,
+			Status:     intents.IntentStatus(rule.Then.Status),
+			Matched:    evaluateConditionBlock(rule.When, signals),
+			Conditions: []intents.ConditionTrace{},
+		}
+		for _, cond := range rule.When.All {
+			rt.Conditions = append(rt.Conditions, traceCondition("all", cond, signals))
+		}
+		for _, cond := range rule.When.Any {
+			rt.Conditions = append(rt.Conditions, traceCondition("any", cond, signals))
+		}
+
+		traces = append(traces, rt)
+	}
+
+	return traces
+}
+
+func traceCondition(block string, cond Condition, signals map[string]float64) intents.ConditionTrace {
+	ct := intents.ConditionTrace{
+		Block:     block,
+		Signal:    cond.Signal,
+		Op:        cond.Op,
+		Threshold: cond.Value,
+		Outcome:   evaluateCondition(cond, signals),
+	}
+	if v, ok := signals[cond.Signal]; ok {
+		ct.Observed = &v
+	}
+	return ct
+}
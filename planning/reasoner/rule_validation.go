@@ -14,6 +14,138 @@ func ValidateRules(rules []Rule) error {
 			return fmt.Errorf("rule[%d] (%s): %w", i, rule.ID, err)
 		}
 	}
+	return validatePrecedence(rules)
+}
+
+// ValidateRuleset validates the ruleset-level settings and all of its rules.
+func ValidateRuleset(rs Ruleset) error {
+	if !rs.Strategy.IsValid() {
+		return fmt.Errorf("invalid strategy '%s'", rs.Strategy)
+	}
+	return ValidateRules(rs.Rules)
+}
+
+// ValidateRulesets validates a set of rulesets served together: ruleset IDs
+// must be unique and each intent must be owned by a single ruleset.
+func ValidateRulesets(rulesets []Ruleset) error {
+	ids := make(map[string]bool)
+	owners := make(map[string]string)
+
+	for _, rs := range rulesets {
+		if ids[rs.RulesetID] {
+			return fmt.Errorf("duplicate ruleset_id '%s'", rs.RulesetID)
+		}
+		ids[rs.RulesetID] = true
+
+		for _, rule := range rs.Rules {
+			owner, ok := owners[rule.Intent]
+			if ok && owner != rs.RulesetID {
+				return fmt.Errorf(
+					"intent '%s' is defined by rulesets '%s' and '%s'",
+					rule.Intent, owner, rs.RulesetID,
+				)
+			}
+			owners[rule.Intent] = rs.RulesetID
+		}
+	}
+	return nil
+}
+
+// ValidateIntentCoverage checks rulesets against the intent registry: every
+// rule must target a registered intent. Registered intents without rules are
+// allowed (see UncoveredIntents); requests for them fail with ErrNoRules.
+func ValidateIntentCoverage(registry *intents.Registry, rulesets []Ruleset) error {
+	for _, rs := range rulesets {
+		for _, rule := range rs.Rules {
+			if _, err := registry.Lookup(rule.Intent); err != nil {
+				return fmt.Errorf("ruleset '%s' rule '%s': %w", rs.RulesetID, rule.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// UncoveredIntents returns the registered intents no rule targets, in
+// registry order.
+func UncoveredIntents(registry *intents.Registry, rulesets []Ruleset) []string {
+	covered := make(map[string]bool)
+	for _, rs := range rulesets {
+		for _, rule := range rs.Rules {
+			covered[rule.Intent] = true
+		}
+	}
+
+	var out []string
+	for _, in := range registry.All() {
+		if !covered[in.ID] {
+			out = append(out, in.ID)
+		}
+	}
+	return out
+}
+
+// ValidateRuleSignals checks that rules only reference signals that the
+// signal map declares (required or optional) for the rule's intent.
+func ValidateRuleSignals(signals intents.SignalMap, rulesets []Ruleset) error {
+	for _, rs := range rulesets {
+		for _, rule := range rs.Rules {
+			if !signals.Declares(rule.Intent) {
+				return fmt.Errorf("ruleset '%s' rule '%s': intent '%s' has no signal declaration",
+					rs.RulesetID, rule.ID, rule.Intent)
+			}
+			conds := append(append([]Condition(nil), rule.When.All...), rule.When.Any...)
+			for _, c := range conds {
+				if !signals.Allows(rule.Intent, c.Signal) {
+					return fmt.Errorf("ruleset '%s' rule '%s': signal %s is not declared for intent '%s'",
+						rs.RulesetID, rule.ID, c.Signal, rule.Intent)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RulesetCheck is an extra validation applied to a full set of rulesets
+// before it is activated.
+type RulesetCheck func([]Ruleset) error
+
+// CheckIntentCoverage adapts ValidateIntentCoverage to a RulesetCheck.
+func CheckIntentCoverage(registry *intents.Registry) RulesetCheck {
+	return func(rulesets []Ruleset) error {
+		return ValidateIntentCoverage(registry, rulesets)
+	}
+}
+
+// CheckRuleSignals adapts ValidateRuleSignals to a RulesetCheck.
+func CheckRuleSignals(signals intents.SignalMap) RulesetCheck {
+	return func(rulesets []Ruleset) error {
+		return ValidateRuleSignals(signals, rulesets)
+	}
+}
+
+// validatePrecedence rejects rules for the same intent that share a priority
+// but declare different statuses: which one wins would depend on file order.
+func validatePrecedence(rules []Rule) error {
+	type key struct {
+		intent   string
+		priority int
+	}
+	seen := make(map[key]Rule)
+
+	for _, rule := range rules {
+		k := key{intent: rule.Intent, priority: rule.Priority}
+		prev, ok := seen[k]
+		if !ok {
+			seen[k] = rule
+			continue
+		}
+		if prev.Then.Status != rule.Then.Status {
+			return fmt.Errorf(
+				"ambiguous precedence: rules '%s' (%s) and '%s' (%s) for intent '%s' share priority %d",
+				prev.ID, prev.Then.Status, rule.ID, rule.Then.Status, rule.Intent, rule.Priority,
+			)
+		}
+	}
 	return nil
 }
 
@@ -25,3 +25,27 @@ func TestValidateRules_InvalidStatus(t *testing.T) {
 		t.Fatal("expected validation error for invalid status")
 	}
 }
+
+func TestValidateRules_AmbiguousPriority(t *testing.T) {
+	when := ConditionBlock{All: []Condition{{Signal: "X", Op: "gte", Value: 0.5}}}
+	rules := []Rule{
+		{ID: "a", Intent: "test.intent", Priority: 5, When: when, Then: RuleAction{Status: "weak_signal"}},
+		{ID: "b", Intent: "test.intent", Priority: 5, When: when, Then: RuleAction{Status: "strong_signal"}},
+	}
+
+	if err := ValidateRules(rules); err == nil {
+		t.Fatal("expected validation error for equal-priority rules with different statuses")
+	}
+
+	rules[1].Intent = "other.intent"
+	if err := ValidateRules(rules); err != nil {
+		t.Fatalf("rules for different intents must not conflict: %v", err)
+	}
+}
+
+func TestValidateRuleset_InvalidStrategy(t *testing.T) {
+	rs := Ruleset{Version: "v1", Strategy: "majority_vote"}
+	if err := ValidateRuleset(rs); err == nil {
+		t.Fatal("expected validation error for unknown strategy")
+	}
+}
@@ -2,8 +2,37 @@ package reasoner
 
 // Ruleset groups a versioned collection of declarative rules.
 type Ruleset struct {
-	Version string `yaml:"version"`
-	Rules   []Rule `yaml:"rules"`
+	RulesetID string           `yaml:"ruleset_id"`
+	Version   string           `yaml:"version"`
+	Strategy  ConflictStrategy `yaml:"strategy,omitempty"`
+
+	// Includes lists files (relative to this one) whose fragments are shared.
+	Includes []string `yaml:"includes,omitempty"`
+	// Fragments are named condition blocks that rules pull in via `use`.
+	Fragments map[string]ConditionBlock `yaml:"fragments,omitempty"`
+
+	Rules []Rule `yaml:"rules"`
+
+	// ContentHash identifies the exact bytes (file + includes) the ruleset was built from.
+	ContentHash string `yaml:"-"`
+	// Source is the file the ruleset was loaded from.
+	Source string `yaml:"-"`
+}
+
+// Ref returns the identity of the ruleset as stamped on outputs.
+func (rs Ruleset) Ref() RulesetRef {
+	return RulesetRef{
+		ID:          rs.RulesetID,
+		Version:     rs.Version,
+		ContentHash: rs.ContentHash,
+	}
+}
+
+// RulesetRef identifies the ruleset that produced a decision.
+type RulesetRef struct {
+	ID          string
+	Version     string
+	ContentHash string
 }
 
 // Rule defines a single declarative rule evaluated by the Rule Engine.
@@ -21,9 +50,11 @@ type Rule struct {
 // ConditionBlock represents logical groupings of conditions.
 // - All: every condition must match
 // - Any: at least one condition must match
+// - Use: fragments whose conditions are merged into All/Any at load time
 type ConditionBlock struct {
 	All []Condition `yaml:"all,omitempty"`
 	Any []Condition `yaml:"any,omitempty"`
+	Use []string    `yaml:"use,omitempty"`
 }
 
 // Condition represents a single signal comparison.
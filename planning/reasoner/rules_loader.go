@@ -1,26 +1,214 @@
 package reasoner
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 func LoadRulesFromFile(path string) ([]Rule, error) {
+	ruleset, err := LoadRulesetFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ruleset.Rules, nil
+}
+
+// LoadRulesetFromFile loads a full ruleset, resolving includes and fragments.
+func LoadRulesetFromFile(path string) (Ruleset, error) {
 	data, err := os.ReadFile(path)
+	if err != nil {
+		return Ruleset{}, err
+	}
+	return parseRuleset(path, data)
+}
+
+// LoadRulesets loads a single ruleset file, or every *.yaml / *.yml file in a
+// directory. Directories are not scanned recursively, so shared include files
+// can live in subdirectories without being loaded as rulesets.
+func LoadRulesets(path string) ([]Ruleset, error) {
+	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
 
+	if !info.IsDir() {
+		rs, err := LoadRulesetFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []Ruleset{rs}, nil
+	}
+
+	files, err := rulesetFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no rulesets found in %s", path)
+	}
+
+	rulesets := make([]Ruleset, 0, len(files))
+	for _, f := range files {
+		rs, err := LoadRulesetFromFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		rulesets = append(rulesets, rs)
+	}
+
+	// 🔒 Cross-ruleset validation (fail-fast)
+	if err := ValidateRulesets(rulesets); err != nil {
+		return nil, err
+	}
+
+	return rulesets, nil
+}
+
+func rulesetFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !isYAML(e.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isYAML(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func parseRuleset(path string, data []byte) (Ruleset, error) {
 	var ruleset Ruleset
 	if err := yaml.Unmarshal(data, &ruleset); err != nil {
-		return nil, err
+		return Ruleset{}, err
+	}
+
+	h := sha256.New()
+	h.Write(data)
+
+	fragments := make(map[string]ConditionBlock)
+	visited := map[string]bool{filepath.Clean(path): true}
+	if err := loadIncludes(filepath.Dir(path), ruleset.Includes, fragments, visited, h); err != nil {
+		return Ruleset{}, err
+	}
+	if err := mergeFragments(fragments, ruleset.Fragments, path); err != nil {
+		return Ruleset{}, err
+	}
+	ruleset.Fragments = fragments
+
+	if err := expandFragments(ruleset.Rules, fragments); err != nil {
+		return Ruleset{}, err
 	}
 
+	if ruleset.RulesetID == "" {
+		ruleset.RulesetID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	ruleset.Source = path
+	ruleset.ContentHash = hex.EncodeToString(h.Sum(nil))[:16]
+
 	// 🔒 VALIDATION STEP (fail-fast)
-	if err := ValidateRules(ruleset.Rules); err != nil {
-		return nil, err
+	if err := ValidateRuleset(ruleset); err != nil {
+		return Ruleset{}, err
 	}
 
-	return ruleset.Rules, nil
+	return ruleset, nil
+}
+
+// includeFile is the shape of a shared fragment file.
+type includeFile struct {
+	Includes  []string                  `yaml:"includes,omitempty"`
+	Fragments map[string]ConditionBlock `yaml:"fragments"`
+}
+
+func loadIncludes(
+	dir string,
+	includes []string,
+	fragments map[string]ConditionBlock,
+	visited map[string]bool,
+	h hash.Hash,
+) error {
+	for _, inc := range includes {
+		path := filepath.Clean(filepath.Join(dir, inc))
+		if visited[path] {
+			return fmt.Errorf("include cycle at %s", inc)
+		}
+		visited[path] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", inc, err)
+		}
+		h.Write(data)
+
+		var f includeFile
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return fmt.Errorf("include %s: %w", inc, err)
+		}
+		if err := loadIncludes(filepath.Dir(path), f.Includes, fragments, visited, h); err != nil {
+			return err
+		}
+		if err := mergeFragments(fragments, f.Fragments, inc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeFragments(dst, src map[string]ConditionBlock, origin string) error {
+	for name, frag := range src {
+		if _, dup := dst[name]; dup {
+			return fmt.Errorf("fragment '%s' redefined in %s", name, origin)
+		}
+		if len(frag.Use) > 0 {
+			return fmt.Errorf("fragment '%s': nested 'use' is not supported", name)
+		}
+		dst[name] = frag
+	}
+	return nil
+}
+
+// expandFragments merges every `use` reference into the rule's own conditions.
+// At most one of the rule and its fragments may have an `any` list: merging
+// two would turn (X) AND (Y) into X OR Y.
+func expandFragments(rules []Rule, fragments map[string]ConditionBlock) error {
+	for i := range rules {
+		block := &rules[i].When
+		anyFrom := ""
+		if len(block.Any) > 0 {
+			anyFrom = "the rule"
+		}
+		for _, name := range block.Use {
+			frag, ok := fragments[name]
+			if !ok {
+				return fmt.Errorf("rule '%s': unknown fragment '%s'", rules[i].ID, name)
+			}
+			if len(frag.Any) > 0 {
+				if anyFrom != "" {
+					return fmt.Errorf("rule '%s': fragment '%s' has an any list and so does %s; any lists cannot be combined", rules[i].ID, name, anyFrom)
+				}
+				anyFrom = fmt.Sprintf("fragment '%s'", name)
+			}
+			block.All = append(block.All, frag.All...)
+			block.Any = append(block.Any, frag.Any...)
+		}
+		block.Use = nil
+	}
+	return nil
 }
@@ -0,0 +1,246 @@
+package reasoner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"woodpecker/planning/intents"
+)
+
+func TestLoadRulesets_Shipped(t *testing.T) {
+	rulesets, err := LoadRulesets("../rules")
+	if err != nil {
+		t.Fatalf("shipped rulesets must load: %v", err)
+	}
+
+	var rs Ruleset
+	for _, r := range rulesets {
+		if r.RulesetID == "regime_state_v1" {
+			rs = r
+		}
+	}
+	if rs.RulesetID == "" {
+		t.Fatal("expected regime_state_v1 ruleset")
+	}
+	if rs.Version != "v1" || rs.Strategy != StrategyAdditive || rs.ContentHash == "" {
+		t.Fatalf("unexpected ruleset metadata: %s %s %q", rs.Version, rs.Strategy, rs.ContentHash)
+	}
+	if len(rs.Rules[0].When.All) != 3 {
+		t.Fatalf("expected shared fragment to be expanded, got %d conditions", len(rs.Rules[0].When.All))
+	}
+}
+
+func TestLoadRulesets_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "shared", "common.yaml"), `
+fragments:
+  moving:
+    all:
+      - signal: REGIME_SHIFT
+        op: gte
+        value: 0.5
+`)
+	writeFile(t, filepath.Join(dir, "a.yaml"), `
+ruleset_id: a
+version: "3"
+includes: [shared/common.yaml]
+rules:
+  - id: a1
+    intent: intent.a
+    when:
+      use: [moving]
+      any:
+        - signal: CONVICTION_SPIKE
+          op: gt
+          value: 0.2
+    then:
+      status: weak_signal
+      confidence_boost: 0.1
+`)
+	writeFile(t, filepath.Join(dir, "b.yml"), `
+version: "1"
+rules:
+  - id: b1
+    intent: intent.b
+    when:
+      all:
+        - signal: X
+          op: lt
+          value: 0.5
+    then:
+      status: weak_signal
+`)
+
+	rulesets, err := LoadRulesets(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rulesets) != 2 {
+		t.Fatalf("expected 2 rulesets, got %d", len(rulesets))
+	}
+	if rulesets[0].RulesetID != "a" || rulesets[1].RulesetID != "b" {
+		t.Fatalf("unexpected ruleset ids: %s, %s", rulesets[0].RulesetID, rulesets[1].RulesetID)
+	}
+	a1 := rulesets[0].Rules[0].When
+	if len(a1.All) != 1 || len(a1.Any) != 1 || a1.Use != nil {
+		t.Fatalf("fragment not expanded: %+v", a1)
+	}
+
+	router, err := NewRulesetRouter("v1", rulesets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := router.Evaluate("intent.a", nil, []SignalInput{
+		{SignalID: "REGIME_SHIFT", Value: 0.7},
+		{SignalID: "CONVICTION_SPIKE", Value: 0.3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Meta.RulesetID != "a" || out.Meta.RulesetVersion != "3" || out.Meta.RulesetHash != rulesets[0].ContentHash {
+		t.Fatalf("unexpected ruleset provenance: %+v", out.Meta)
+	}
+}
+
+func TestLoadRulesets_DuplicateIntentOwner(t *testing.T) {
+	dir := t.TempDir()
+	rule := `
+rules:
+  - id: r
+    intent: intent.shared
+    when:
+      all:
+        - signal: X
+          op: gte
+          value: 0.5
+    then:
+      status: weak_signal
+`
+	writeFile(t, filepath.Join(dir, "one.yaml"), rule)
+	writeFile(t, filepath.Join(dir, "two.yaml"), rule)
+
+	if _, err := LoadRulesets(dir); err == nil {
+		t.Fatal("expected error when two rulesets define the same intent")
+	}
+}
+
+func TestLoadRulesets_UnknownFragment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, `
+rules:
+  - id: r
+    intent: intent.a
+    when:
+      use: [missing]
+    then:
+      status: weak_signal
+`)
+
+	if _, err := LoadRulesets(path); err == nil {
+		t.Fatal("expected error for unknown fragment")
+	}
+}
+
+func TestLoadRulesets_FragmentAnyLists(t *testing.T) {
+	fragments := `
+fragments:
+  x_or_y:
+    any:
+      - {signal: X, op: gte, value: 0.5}
+      - {signal: Y, op: gte, value: 0.5}
+  z_or_w:
+    any:
+      - {signal: Z, op: gte, value: 0.5}
+      - {signal: W, op: gte, value: 0.5}
+rules:
+`
+	cases := map[string]string{
+		"rule and fragment": `
+  - id: r
+    intent: intent.a
+    when:
+      use: [x_or_y]
+      any:
+        - {signal: V, op: gte, value: 0.5}
+    then:
+      status: weak_signal
+`,
+		"two fragments": `
+  - id: r
+    intent: intent.a
+    when:
+      use: [x_or_y, z_or_w]
+    then:
+      status: weak_signal
+`,
+	}
+	for name, rule := range cases {
+		path := filepath.Join(t.TempDir(), "rules.yaml")
+		writeFile(t, path, fragments+rule)
+		if _, err := LoadRulesets(path); err == nil || !strings.Contains(err.Error(), "any lists cannot be combined") {
+			t.Fatalf("%s: expected the any lists to be rejected, got %v", name, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, fragments+`
+  - id: r
+    intent: intent.a
+    when:
+      use: [x_or_y]
+      all:
+        - {signal: V, op: gte, value: 0.5}
+    then:
+      status: weak_signal
+`)
+	if _, err := LoadRulesets(path); err != nil {
+		t.Fatalf("a single any list must load: %v", err)
+	}
+}
+
+func TestValidateIntentCoverage_Shipped(t *testing.T) {
+	registry, err := intents.LoadRegistry("../intents/intents.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rulesets, err := LoadRulesets("../rules")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ValidateIntentCoverage(registry, rulesets); err != nil {
+		t.Fatalf("shipped rules must target registered intents: %v", err)
+	}
+	if got := UncoveredIntents(registry, rulesets); len(got) != 2 || got[0] != "observe.market_state" {
+		t.Fatalf("expected the intents without rules reported, got %v", got)
+	}
+
+	stray := []Ruleset{{RulesetID: "stray", Rules: []Rule{{ID: "r", Intent: "observe.weather"}}}}
+	if err := ValidateIntentCoverage(registry, stray); err == nil {
+		t.Fatal("expected error for a rule targeting an unregistered intent")
+	}
+}
+
+func TestValidateRuleSignals(t *testing.T) {
+	signals, err := intents.LoadSignalMap("../intents/signal_intent_map.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rulesets, err := LoadRulesets("../rules")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ValidateRuleSignals(signals, rulesets); err != nil {
+		t.Fatalf("shipped rules must only use declared signals: %v", err)
+	}
+
+	bad := []Ruleset{{RulesetID: "bad", Rules: []Rule{{
+		ID:     "uses_divergence",
+		Intent: "interpret.regime_state",
+		When:   ConditionBlock{All: []Condition{{Signal: "DIVERGENCE_ALERT", Op: "gte", Value: 0.5}}},
+		Then:   RuleAction{Status: "weak_signal"},
+	}}}}
+	if err := ValidateRuleSignals(signals, bad); err == nil {
+		t.Fatal("expected error for signal not declared for the intent")
+	}
+}
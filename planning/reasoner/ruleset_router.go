@@ -0,0 +1,76 @@
+package reasoner
+
+import (
+	"errors"
+	"fmt"
+
+	"woodpecker/planning/intents"
+)
+
+// ErrNoRules is returned for intents no served ruleset defines rules for.
+// They may be registered intents that are not implemented yet.
+var ErrNoRules = errors.New("intent has no rules")
+
+// RulesetRouter serves several rulesets at once, dispatching each intent to
+// the ruleset that defines rules for it.
+type RulesetRouter struct {
+	Version string
+
+	rulesets []Ruleset
+	byIntent map[string]*RuleBasedReasoner
+}
+
+// NewRulesetRouter validates the rulesets together and indexes them by intent.
+func NewRulesetRouter(version string, rulesets []Ruleset) (*RulesetRouter, error) {
+	if err := ValidateRulesets(rulesets); err != nil {
+		return nil, err
+	}
+
+	r := &RulesetRouter{
+		Version:  version,
+		rulesets: rulesets,
+		byIntent: make(map[string]*RuleBasedReasoner),
+	}
+	for _, rs := range rulesets {
+		rb := NewRuleBasedReasoner(version, rs)
+		for _, rule := range rs.Rules {
+			r.byIntent[rule.Intent] = rb
+		}
+	}
+	return r, nil
+}
+
+// SetCounters attaches runtime match counters to every served ruleset.
+// It must be called before the router serves requests.
+func (r *RulesetRouter) SetCounters(c *RuleCounters) {
+	for _, rb := range r.byIntent {
+		rb.Counters = c
+	}
+}
+
+// SetWeights attaches per-intent signal weights to every served ruleset.
+// It must be called before the router serves requests.
+func (r *RulesetRouter) SetWeights(w SignalWeighter) {
+	for _, rb := range r.byIntent {
+		rb.Weights = w
+	}
+}
+
+// Rulesets returns the rulesets served by the router.
+func (r *RulesetRouter) Rulesets() []Ruleset {
+	return r.rulesets
+}
+
+// Evaluate routes to the owning ruleset. Intents without rules are rejected
+// with ErrNoRules rather than silently reported as low_confidence.
+func (r *RulesetRouter) Evaluate(
+	intentID string,
+	params map[string]any,
+	signals []SignalInput,
+) (intents.IntentOutput, error) {
+	rb, ok := r.byIntent[intentID]
+	if !ok {
+		return intents.IntentOutput{}, fmt.Errorf("%w: '%s'", ErrNoRules, intentID)
+	}
+	return rb.Evaluate(intentID, params, signals)
+}
@@ -29,10 +29,18 @@ func (r *SimpleReasoner) Evaluate(
 	var confidence float64
 	usedSignals := make([]intents.SignalUsage, 0, len(signals))
 
-	weight := 1.0 / float64(len(signals))
+	share := 1.0 / float64(len(signals))
 
 	for _, s := range signals {
-		confidence += s.Value * weight
+		confidence += s.Value * share
+	}
+
+	// Weight = each signal's share of the aggregated confidence.
+	for _, s := range signals {
+		weight := 0.0
+		if confidence > 0 {
+			weight = s.Value * share / confidence
+		}
 		usedSignals = append(usedSignals, intents.SignalUsage{
 			SignalID: s.SignalID,
 			Value:    s.Value,
@@ -28,3 +28,19 @@ func TestSimpleReasoner_Evaluate(t *testing.T) {
 		t.Fatalf("expected 3 signals, got %d", len(out.Signals))
 	}
 }
+
+func TestSimpleReasoner_WeightsFollowContribution(t *testing.T) {
+	r := &SimpleReasoner{Version: "v1"}
+
+	out, err := r.Evaluate("interpret.regime_state", nil, []SignalInput{
+		{SignalID: "REGIME_SHIFT", Value: 0.9},
+		{SignalID: "CONVICTION_SPIKE", Value: 0.3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.Signals[0].Weight != 0.75 || out.Signals[1].Weight != 0.25 {
+		t.Fatalf("expected weights 0.75/0.25, got %v/%v", out.Signals[0].Weight, out.Signals[1].Weight)
+	}
+}
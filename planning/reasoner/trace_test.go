@@ -0,0 +1,87 @@
+package reasoner
+
+import (
+	"testing"
+)
+
+func TestRuleBasedReasoner_Trace(t *testing.T) {
+	rules := []Rule{
+		{
+			ID:       "strong",
+			Intent:   "interpret.regime_state",
+			Priority: 20,
+			When: ConditionBlock{All: []Condition{
+				{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.75},
+				{Signal: "CONVICTION_SPIKE", Op: "gte", Value: 0.6},
+			}},
+			Then: RuleAction{Status: "strong_signal", ConfidenceBoost: 0.45},
+		},
+		{
+			ID:       "weak",
+			Intent:   "interpret.regime_state",
+			Priority: 10,
+			When: ConditionBlock{All: []Condition{
+				{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5},
+			}},
+			Then: RuleAction{Status: "weak_signal", ConfidenceBoost: 0.15},
+		},
+	}
+	r := &RuleBasedReasoner{Version: "v1", Rules: rules}
+	signals := []SignalInput{{SignalID: "REGIME_SHIFT", Value: 0.8}}
+
+	out, err := r.Evaluate("interpret.regime_state", nil, signals)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Trace != nil {
+		t.Fatal("trace must only be attached on request")
+	}
+
+	out, err = r.Evaluate("interpret.regime_state", map[string]any{"explain": true}, signals)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Trace == nil || len(out.Trace.Rules) != 2 {
+		t.Fatalf("expected trace of 2 rules, got %+v", out.Trace)
+	}
+
+	strong := out.Trace.Rules[0]
+	if strong.Matched || len(strong.Conditions) != 2 {
+		t.Fatalf("unexpected strong rule trace: %+v", strong)
+	}
+	if c := strong.Conditions[0]; !c.Outcome || c.Observed == nil || *c.Observed != 0.8 || c.Threshold != 0.75 {
+		t.Fatalf("unexpected REGIME_SHIFT condition trace: %+v", c)
+	}
+	if c := strong.Conditions[1]; c.Outcome || c.Observed != nil {
+		t.Fatalf("missing signal must be traced with null observed value: %+v", c)
+	}
+
+	res := out.Trace.Resolution
+	if res.Strategy != "additive" || res.WinningRuleID != "weak" || len(res.ContributingRuleIDs) != 1 {
+		t.Fatalf("unexpected resolution trace: %+v", res)
+	}
+	if out.Reasoning.Logic[0].RuleID != "weak" {
+		t.Fatalf("expected reasoning step to carry rule id, got %q", out.Reasoning.Logic[0].RuleID)
+	}
+	if err := out.ValidateSchema(); err != nil {
+		t.Fatalf("traced output must match the schema: %v", err)
+	}
+}
+
+func TestExplainRequested(t *testing.T) {
+	cases := []struct {
+		params map[string]any
+		want   bool
+	}{
+		{nil, false},
+		{map[string]any{"explain": true}, true},
+		{map[string]any{"debug": "true"}, true},
+		{map[string]any{"debug": float64(1)}, true},
+		{map[string]any{"explain": false, "debug": "no"}, false},
+	}
+	for _, tc := range cases {
+		if got := ExplainRequested(tc.params); got != tc.want {
+			t.Fatalf("ExplainRequested(%v) = %v, want %v", tc.params, got, tc.want)
+		}
+	}
+}
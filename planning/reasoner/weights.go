@@ -0,0 +1,93 @@
+package reasoner
+
+import (
+	"woodpecker/planning/intents"
+)
+
+// contributionWeights explains a rule-based decision in terms of its inputs.
+//
+// Every condition of a contributing rule that held credits its signal with
+// the rule's confidence_boost, scaled by how far the observed value cleared
+// the threshold (1 at the threshold, up to 2 at the end of the signal range)
+// and by the signal's configured per-intent weight. Credits are normalized to
+// sum to 1; signals no contributing rule looked at get weight 0. Rules that
+// do not boost confidence are credited minBoostShare, so they explain a
+// decision they make alone but barely count next to boosting rules.
+func contributionWeights(
+	intentID string,
+	inputs []SignalInput,
+	contributing []Rule,
+	weighter SignalWeighter,
+) []intents.SignalUsage {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	values := make(map[string]float64, len(inputs))
+	for _, s := range inputs {
+		values[s.SignalID] = s.Value
+	}
+
+	raw := make(map[string]float64)
+	var total float64
+	for _, rule := range contributing {
+		share := rule.Then.ConfidenceBoost
+		if share <= 0 {
+			share = minBoostShare
+		}
+
+		conds := append(append([]Condition(nil), rule.When.All...), rule.When.Any...)
+		for _, c := range conds {
+			v, ok := values[c.Signal]
+			if !ok || !evaluateCondition(c, values) {
+				continue
+			}
+			w := share * (1 + conditionMargin(c, v))
+			if weighter != nil {
+				w *= weighter.Weight(intentID, c.Signal)
+			}
+			raw[c.Signal] += w
+			total += w
+		}
+	}
+
+	out := make([]intents.SignalUsage, 0, len(inputs))
+	for _, s := range inputs {
+		weight := 0.0
+		if total > 0 {
+			weight = raw[s.SignalID] / total
+		}
+		out = append(out, intents.SignalUsage{
+			SignalID: s.SignalID,
+			Value:    s.Value,
+			Weight:   weight,
+		})
+	}
+	return out
+}
+
+// minBoostShare is the credit share of a rule without confidence_boost.
+const minBoostShare = 0.01
+
+// conditionMargin is how far v cleared the condition's threshold, normalized
+// to the remaining signal range: 0 at the threshold, 1 at the range boundary.
+func conditionMargin(c Condition, v float64) float64 {
+	var margin float64
+	switch c.Op {
+	case "gte", "gt":
+		if span := SignalMax - c.Value; span > 0 {
+			margin = (v - c.Value) / span
+		}
+	case "lte", "lt":
+		if span := c.Value - SignalMin; span > 0 {
+			margin = (c.Value - v) / span
+		}
+	}
+	if margin < 0 {
+		return 0
+	}
+	if margin > 1 {
+		return 1
+	}
+	return margin
+}
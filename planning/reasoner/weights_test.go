@@ -0,0 +1,116 @@
+package reasoner
+
+import (
+	"math"
+	"testing"
+
+	"woodpecker/planning/intents"
+)
+
+func weightRules() []Rule {
+	return []Rule{
+		{
+			ID:       "strong",
+			Intent:   "interpret.regime_state",
+			Priority: 2,
+			When: ConditionBlock{All: []Condition{
+				{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5},
+				{Signal: "PROBABILITY_ACCELERATION", Op: "gte", Value: 0.5},
+			}},
+			Then: RuleAction{Status: "strong_signal", ConfidenceBoost: 0.4},
+		},
+	}
+}
+
+func weightsByID(out intents.IntentOutput) map[string]float64 {
+	m := make(map[string]float64)
+	for _, s := range out.Signals {
+		m[s.SignalID] = s.Weight
+	}
+	return m
+}
+
+func TestRuleBasedReasoner_SignalWeights(t *testing.T) {
+	r := &RuleBasedReasoner{Version: "v1", Rules: weightRules()}
+
+	out, err := r.Evaluate("interpret.regime_state", nil, []SignalInput{
+		{SignalID: "REGIME_SHIFT", Value: 1.0},             // margin 1 => credit 2x
+		{SignalID: "PROBABILITY_ACCELERATION", Value: 0.5}, // at threshold => credit 1x
+		{SignalID: "CONVICTION_SPIKE", Value: 0.9},         // not referenced
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w := weightsByID(out)
+	if math.Abs(w["REGIME_SHIFT"]-2.0/3) > 1e-9 || math.Abs(w["PROBABILITY_ACCELERATION"]-1.0/3) > 1e-9 {
+		t.Fatalf("unexpected weights: %v", w)
+	}
+	if w["CONVICTION_SPIKE"] != 0 {
+		t.Fatalf("unused signal must have weight 0, got %v", w["CONVICTION_SPIKE"])
+	}
+}
+
+func TestRuleBasedReasoner_SignalWeightsFromConfig(t *testing.T) {
+	r := &RuleBasedReasoner{
+		Version: "v1",
+		Rules:   weightRules(),
+		Weights: intents.SignalMap{
+			"interpret.regime_state": {Weights: map[string]float64{"PROBABILITY_ACCELERATION": 3}},
+		},
+	}
+
+	out, err := r.Evaluate("interpret.regime_state", nil, []SignalInput{
+		{SignalID: "REGIME_SHIFT", Value: 0.5},
+		{SignalID: "PROBABILITY_ACCELERATION", Value: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w := weightsByID(out)
+	if math.Abs(w["REGIME_SHIFT"]-0.25) > 1e-9 || math.Abs(w["PROBABILITY_ACCELERATION"]-0.75) > 1e-9 {
+		t.Fatalf("unexpected weights: %v", w)
+	}
+}
+
+func TestRuleBasedReasoner_NoMatchZeroWeights(t *testing.T) {
+	r := &RuleBasedReasoner{Version: "v1", Rules: weightRules()}
+
+	out, err := r.Evaluate("interpret.regime_state", nil, []SignalInput{
+		{SignalID: "REGIME_SHIFT", Value: 0.1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Signals[0].Weight != 0 {
+		t.Fatalf("expected weight 0 when no rule matched, got %v", out.Signals[0].Weight)
+	}
+}
+
+func TestContributionWeights_ZeroBoost(t *testing.T) {
+	inputs := []SignalInput{
+		{SignalID: "REGIME_SHIFT", Value: 0.5},
+		{SignalID: "CONVICTION_SPIKE", Value: 0.5},
+	}
+	boosting := Rule{
+		When: ConditionBlock{All: []Condition{{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.5}}},
+		Then: RuleAction{ConfidenceBoost: 0.2},
+	}
+	statusOnly := Rule{
+		When: ConditionBlock{All: []Condition{{Signal: "CONVICTION_SPIKE", Op: "gte", Value: 0.5}}},
+	}
+
+	w := make(map[string]float64)
+	for _, s := range contributionWeights("interpret.regime_state", inputs, []Rule{boosting, statusOnly}, nil) {
+		w[s.SignalID] = s.Weight
+	}
+	if w["CONVICTION_SPIKE"] >= w["REGIME_SHIFT"]/10 {
+		t.Fatalf("a zero-boost rule must get little credit, got %v", w)
+	}
+
+	alone := contributionWeights("interpret.regime_state", inputs, []Rule{statusOnly}, nil)
+	if alone[1].Weight != 1 {
+		t.Fatalf("a zero-boost rule deciding alone must get all the credit, got %+v", alone)
+	}
+}
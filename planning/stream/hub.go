@@ -0,0 +1,191 @@
+// Package stream publishes intent evaluation changes to live subscribers
+// (the SSE endpoint) and keeps a bounded buffer for Last-Event-ID replay.
+package stream
+
+import (
+	"path"
+	"sync"
+	"time"
+
+	"woodpecker/pipeline"
+	"woodpecker/planning/intents"
+)
+
+const (
+	// DefaultBufferSize is how many events a Hub keeps for replay.
+	DefaultBufferSize = 1024
+
+	// subscriberQueue is the per-subscriber backlog. A subscriber that falls
+	// further behind is dropped and must reconnect with Last-Event-ID.
+	subscriberQueue = 64
+)
+
+// Event is one published intent evaluation.
+type Event struct {
+	ID   uint64    `json:"id"`
+	Time time.Time `json:"time"`
+
+	Venue    pipeline.Venue `json:"venue"`
+	EventID  string         `json:"event_id"`
+	MarketID string         `json:"market_id"`
+	IntentID string         `json:"intent_id"`
+
+	Status         intents.IntentStatus `json:"status"`
+	PreviousStatus intents.IntentStatus `json:"previous_status,omitempty"`
+	Output         intents.IntentOutput `json:"output"`
+}
+
+// Filter selects events. Empty fields match everything; Intents are glob
+// patterns (e.g. "trigger.*").
+type Filter struct {
+	Intents   []string
+	Statuses  []intents.IntentStatus
+	MarketIDs []string
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Event) bool {
+	if len(f.Intents) > 0 {
+		ok := false
+		for _, p := range f.Intents {
+			if m, _ := path.Match(p, e.IntentID); m {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(f.Statuses) > 0 {
+		ok := false
+		for _, s := range f.Statuses {
+			if s == e.Status {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(f.MarketIDs) > 0 {
+		ok := false
+		for _, id := range f.MarketIDs {
+			if id == e.MarketID {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Subscription receives matching events on C until it is closed, either by
+// Hub.Unsubscribe or because the subscriber fell behind.
+type Subscription struct {
+	C <-chan Event
+
+	ch     chan Event
+	filter Filter
+}
+
+// Hub fans out published events to subscribers and keeps the last events in
+// a ring buffer.
+type Hub struct {
+	mu     sync.Mutex
+	nextID uint64
+	buf    []Event // ring, oldest at head
+	head   int
+	size   int
+	subs   map[*Subscription]struct{}
+}
+
+// NewHub keeps up to bufferSize events for replay (DefaultBufferSize if <= 0).
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Hub{
+		nextID: 1,
+		buf:    make([]Event, bufferSize),
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the next ID (and Time if unset), buffers e and delivers it
+// to matching subscribers. It never blocks on slow subscribers.
+func (h *Hub) Publish(e Event) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	e.ID = h.nextID
+	h.nextID++
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	if h.size < len(h.buf) {
+		h.buf[(h.head+h.size)%len(h.buf)] = e
+		h.size++
+	} else {
+		h.buf[h.head] = e
+		h.head = (h.head + 1) % len(h.buf)
+	}
+
+	for sub := range h.subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			h.drop(sub)
+		}
+	}
+	return e
+}
+
+// Subscribe registers a subscriber and returns the buffered events after
+// lastID that match f. lastID 0 means no replay. An ID the hub has not issued
+// (e.g. from before a restart) replays the whole buffer.
+func (h *Hub) Subscribe(f Filter, lastID uint64) ([]Event, *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var replay []Event
+	if lastID > 0 {
+		if lastID >= h.nextID {
+			lastID = 0
+		}
+		for i := 0; i < h.size; i++ {
+			e := h.buf[(h.head+i)%len(h.buf)]
+			if e.ID > lastID && f.Match(e) {
+				replay = append(replay, e)
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberQueue)
+	sub := &Subscription{C: ch, ch: ch, filter: f}
+	h.subs[sub] = struct{}{}
+	return replay, sub
+}
+
+// Unsubscribe removes sub and closes its channel. It is safe to call more
+// than once.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(sub)
+}
+
+func (h *Hub) drop(sub *Subscription) {
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
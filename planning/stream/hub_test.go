@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"testing"
+
+	"woodpecker/planning/intents"
+)
+
+func TestHub_ReplayAfterLastID(t *testing.T) {
+	h := NewHub(3)
+	for _, intent := range []string{"a.x", "b.x", "a.y", "a.z"} {
+		h.Publish(Event{IntentID: intent, Status: intents.StatusWeakSignal})
+	}
+
+	// Buffer holds IDs 2..4; IDs start at 1.
+	replay, sub := h.Subscribe(Filter{}, 2)
+	defer h.Unsubscribe(sub)
+	if len(replay) != 2 || replay[0].ID != 3 || replay[1].ID != 4 {
+		t.Fatalf("expected events 3 and 4, got %+v", replay)
+	}
+
+	replay, sub2 := h.Subscribe(Filter{Intents: []string{"a.*"}}, 1)
+	defer h.Unsubscribe(sub2)
+	if len(replay) != 2 || replay[0].IntentID != "a.y" {
+		t.Fatalf("expected filtered replay of a.y and a.z, got %+v", replay)
+	}
+
+	// An ID from another process replays the whole buffer.
+	replay, sub3 := h.Subscribe(Filter{}, 99)
+	defer h.Unsubscribe(sub3)
+	if len(replay) != 3 {
+		t.Fatalf("expected full buffer replay, got %d", len(replay))
+	}
+}
+
+func TestHub_LiveFilteredDelivery(t *testing.T) {
+	h := NewHub(10)
+	_, sub := h.Subscribe(Filter{Statuses: []intents.IntentStatus{intents.StatusStrongSignal}}, 0)
+	defer h.Unsubscribe(sub)
+
+	h.Publish(Event{IntentID: "a.x", Status: intents.StatusWeakSignal})
+	h.Publish(Event{IntentID: "a.x", Status: intents.StatusStrongSignal})
+
+	e := <-sub.C
+	if e.Status != intents.StatusStrongSignal || e.ID != 2 {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	select {
+	case e := <-sub.C:
+		t.Fatalf("unexpected extra event %+v", e)
+	default:
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	h := NewHub(10)
+	_, sub := h.Subscribe(Filter{}, 0)
+
+	for i := 0; i < subscriberQueue+1; i++ {
+		h.Publish(Event{IntentID: "a.x"})
+	}
+
+	n := 0
+	for range sub.C {
+		n++
+	}
+	if n != subscriberQueue {
+		t.Fatalf("expected %d queued events before the channel closed, got %d", subscriberQueue, n)
+	}
+	h.Unsubscribe(sub) // no panic on double close
+}
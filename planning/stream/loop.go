@@ -0,0 +1,202 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"woodpecker/pipeline"
+	"woodpecker/planning/guardrails"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+const (
+	// DefaultInterval is how often the loop snapshots its markets.
+	DefaultInterval = time.Minute
+
+	// DefaultMinConfidenceDelta is the confidence change that is published
+	// even when the status does not change.
+	DefaultMinConfidenceDelta = 0.05
+)
+
+// Config is the watchlist of the evaluation loop.
+type Config struct {
+	Interval           time.Duration `yaml:"interval"`
+	MinConfidenceDelta float64       `yaml:"min_confidence_delta"`
+
+	Intents []string             `yaml:"intents"`
+	Markets []pipeline.MarketRef `yaml:"markets"`
+}
+
+// LoadConfig reads and validates a watchlist file.
+func LoadConfig(p string) (Config, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", p, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks every market reference and that intents are listed.
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("interval must be >= 0")
+	}
+	if c.MinConfidenceDelta < 0 || c.MinConfidenceDelta > 1 {
+		return fmt.Errorf("min_confidence_delta must be between 0 and 1")
+	}
+	if len(c.Intents) == 0 {
+		return fmt.Errorf("at least one intent is required")
+	}
+	for i, m := range c.Markets {
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("markets[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateIntents checks the watchlist intents against the registry and
+// rejects those no ruleset has rules for: they would fail on every tick.
+func (c Config) ValidateIntents(registry *intents.Registry, rulesets []reasoner.Ruleset) error {
+	for _, id := range c.Intents {
+		if _, err := registry.Lookup(id); err != nil {
+			return err
+		}
+	}
+	for _, id := range reasoner.UncoveredIntents(registry, rulesets) {
+		for _, watched := range c.Intents {
+			if id == watched {
+				return fmt.Errorf("intent '%s' has no rules", id)
+			}
+		}
+	}
+	return nil
+}
+
+// MarketPipeline computes reasoner inputs for a market reference.
+type MarketPipeline interface {
+	Run(ref pipeline.MarketRef) (pipeline.Result, error)
+}
+
+// Loop periodically snapshots the watchlist markets, evaluates every intent
+// on them and publishes the evaluations that changed to Hub.
+type Loop struct {
+	Config   Config
+	Pipeline MarketPipeline
+	Reasoner reasoner.IntentReasoner
+	Hub      *Hub
+
+	// Signals, when set, restricts each intent to the signals it declares.
+	Signals intents.SignalMap
+
+	last map[string]intents.IntentOutput
+}
+
+// Run evaluates immediately and then every Config.Interval until ctx is done.
+func (l *Loop) Run(ctx context.Context) {
+	interval := l.Config.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		l.Tick()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs one evaluation round and returns how many events it published.
+// Pipeline and reasoner errors are logged and skip the market or intent.
+func (l *Loop) Tick() int {
+	if l.last == nil {
+		l.last = make(map[string]intents.IntentOutput)
+	}
+
+	published := 0
+	for _, ref := range l.Config.Markets {
+		res, err := l.Pipeline.Run(ref)
+		if err != nil {
+			log.Printf("⚠️ stream: %s %s%s: %v", ref.Venue, ref.MarketID, ref.EventID, err)
+			continue
+		}
+
+		for _, m := range res.Markets {
+			// Markets below the pipeline's minimum data quality keep their
+			// last evaluation: their signals say nothing about the market.
+			if m.Skipped {
+				continue
+			}
+			for _, intentID := range l.Config.Intents {
+				out, err := l.Reasoner.Evaluate(intentID, guardrails.WithDataQuality(nil, m.DataQuality()), l.intentSignals(intentID, m.Signals))
+				if err != nil {
+					log.Printf("⚠️ stream: %s %s: %v", m.Point.MarketID, intentID, err)
+					continue
+				}
+
+				key := pipeline.HistoryKey(m.Venue, m.Point.MarketID) + "|" + intentID
+				prev, seen := l.last[key]
+				if seen && !l.changed(prev, out) {
+					continue
+				}
+				l.last[key] = out
+
+				e := Event{
+					Venue:    m.Venue,
+					EventID:  m.EventID,
+					MarketID: m.Point.MarketID,
+					IntentID: intentID,
+					Status:   out.Status,
+					Output:   out,
+				}
+				if seen {
+					e.PreviousStatus = prev.Status
+				}
+				l.Hub.Publish(e)
+				published++
+			}
+		}
+	}
+	return published
+}
+
+// changed reports whether out differs enough from prev to be published.
+func (l *Loop) changed(prev, out intents.IntentOutput) bool {
+	delta := l.Config.MinConfidenceDelta
+	if delta <= 0 {
+		delta = DefaultMinConfidenceDelta
+	}
+	return prev.Status != out.Status || math.Abs(prev.Confidence-out.Confidence) >= delta
+}
+
+func (l *Loop) intentSignals(intentID string, signals []reasoner.SignalInput) []reasoner.SignalInput {
+	out := make([]reasoner.SignalInput, 0, len(signals))
+	for _, s := range signals {
+		if l.Signals.Accepts(intentID, s.SignalID) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package stream
+
+import (
+	"testing"
+
+	polymarket "woodpecker/adapters/Polymarket/gamma"
+	"woodpecker/pipeline"
+	"woodpecker/planning/intents"
+	"woodpecker/planning/reasoner"
+)
+
+// seqPipeline returns one market whose REGIME_SHIFT value is taken from values
+// in turn. Calls listed in skipped return the market skipped for data quality.
+type seqPipeline struct {
+	values  []float64
+	skipped map[int]bool
+	calls   int
+}
+
+func (p *seqPipeline) Run(ref pipeline.MarketRef) (pipeline.Result, error) {
+	v := p.values[p.calls]
+	m := pipeline.MarketSignals{
+		Venue: ref.Venue,
+		Point: polymarket.MarketPoint{MarketID: ref.MarketID},
+		Signals: []reasoner.SignalInput{
+			{SignalID: "REGIME_SHIFT", Value: v},
+			{SignalID: "DIVERGENCE_ALERT", Value: 1},
+		},
+	}
+	if p.skipped[p.calls] {
+		m.Skipped, m.Signals = true, nil
+	}
+	p.calls++
+	return pipeline.Result{Markets: []pipeline.MarketSignals{m}}, nil
+}
+
+func shiftReasoner() reasoner.IntentReasoner {
+	return reasoner.NewRuleBasedReasoner("v1", reasoner.Ruleset{Rules: []reasoner.Rule{{
+		ID:     "shift",
+		Intent: "interpret.regime_state",
+		When: reasoner.ConditionBlock{All: []reasoner.Condition{
+			{Signal: "REGIME_SHIFT", Op: "gte", Value: 0.7},
+		}},
+		Then: reasoner.RuleAction{Status: "strong_signal", ConfidenceBoost: 0.8},
+	}}})
+}
+
+func TestLoop_PublishesChangesOnly(t *testing.T) {
+	r := shiftReasoner()
+
+	hub := NewHub(10)
+	loop := &Loop{
+		Config: Config{
+			Intents: []string{"interpret.regime_state"},
+			Markets: []pipeline.MarketRef{{Venue: pipeline.VenuePolymarket, MarketID: "m1"}},
+		},
+		Pipeline: &seqPipeline{values: []float64{0.2, 0.3, 0.9}},
+		Reasoner: r,
+		Hub:      hub,
+		Signals: intents.SignalMap{
+			"interpret.regime_state": {Required: []string{"REGIME_SHIFT"}},
+		},
+	}
+
+	if n := loop.Tick(); n != 1 {
+		t.Fatalf("first evaluation must be published, got %d", n)
+	}
+	if n := loop.Tick(); n != 0 {
+		t.Fatalf("unchanged evaluation must not be published, got %d", n)
+	}
+	if n := loop.Tick(); n != 1 {
+		t.Fatalf("status change must be published, got %d", n)
+	}
+
+	replay, sub := hub.Subscribe(Filter{}, 1)
+	defer hub.Unsubscribe(sub)
+	if len(replay) != 1 {
+		t.Fatalf("expected one replayed event, got %d", len(replay))
+	}
+	e := replay[0]
+	if e.Status != intents.StatusStrongSignal || e.PreviousStatus != intents.StatusLowConfidence || e.MarketID != "m1" {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	for _, s := range e.Output.Signals {
+		if s.SignalID == "DIVERGENCE_ALERT" {
+			t.Fatal("undeclared signals must be filtered out")
+		}
+	}
+}
+
+func TestLoadConfig_Shipped(t *testing.T) {
+	cfg, err := LoadConfig("watchlist.yaml")
+	if err != nil {
+		t.Fatalf("shipped watchlist must load: %v", err)
+	}
+	if len(cfg.Intents) == 0 || len(cfg.Markets) == 0 {
+		t.Fatalf("unexpected watchlist: %+v", cfg)
+	}
+
+	registry, err := intents.LoadRegistry("../intents/intents.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rulesets, err := reasoner.LoadRulesets("../rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.ValidateIntents(registry, rulesets); err != nil {
+		t.Fatalf("shipped watchlist intents must have rules: %v", err)
+	}
+}
+
+func TestConfig_ValidateIntents(t *testing.T) {
+	registry, err := intents.NewRegistry([]intents.Intent{
+		{ID: "interpret.regime_state", Category: "interpret"},
+		{ID: "trigger.regime_change", Category: "trigger"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rulesets := []reasoner.Ruleset{{RulesetID: "regime", Rules: []reasoner.Rule{{ID: "shift", Intent: "interpret.regime_state"}}}}
+
+	cfg := Config{Intents: []string{"interpret.regime_state"}}
+	if err := cfg.ValidateIntents(registry, rulesets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.Intents = append(cfg.Intents, "trigger.regime_change")
+	if err := cfg.ValidateIntents(registry, rulesets); err == nil {
+		t.Fatal("expected an intent without rules to be rejected")
+	}
+	cfg.Intents = []string{"interpret.weather"}
+	if err := cfg.ValidateIntents(registry, rulesets); err == nil {
+		t.Fatal("expected an unregistered intent to be rejected")
+	}
+}
+
+func TestLoop_SkippedMarketKeepsState(t *testing.T) {
+	hub := NewHub(10)
+	loop := &Loop{
+		Config: Config{
+			Intents: []string{"interpret.regime_state"},
+			Markets: []pipeline.MarketRef{{Venue: pipeline.VenuePolymarket, MarketID: "m1"}},
+		},
+		Pipeline: &seqPipeline{values: []float64{0.9, 0, 0.9}, skipped: map[int]bool{1: true}},
+		Reasoner: shiftReasoner(),
+		Hub:      hub,
+	}
+
+	if n := loop.Tick(); n != 1 {
+		t.Fatalf("first evaluation must be published, got %d", n)
+	}
+	if n := loop.Tick(); n != 0 {
+		t.Fatalf("a skipped market must not be published, got %d", n)
+	}
+	if n := loop.Tick(); n != 0 {
+		t.Fatalf("the state before the skip must be kept, got %d events", n)
+	}
+
+	if out := loop.last["polymarket:m1|interpret.regime_state"]; out.Status != intents.StatusStrongSignal {
+		t.Fatalf("expected the last evaluation to be kept, got %s", out.Status)
+	}
+}